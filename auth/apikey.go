@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// APIKeyPrefix отличает API-ключи от JWT в заголовке Authorization.
+const APIKeyPrefix = "mk_"
+
+// GenerateAPIKey создаёт ключ вида mk_<id>_<secret>. Возвращает ключ целиком (показывается
+// клиенту один раз), его открытую часть mk_<id> и хэш для хранения.
+func GenerateAPIKey() (key, prefix, hash string) {
+	id := make([]byte, 6)
+	secret := make([]byte, 32)
+	_, _ = rand.Read(id)
+	_, _ = rand.Read(secret)
+
+	prefix = APIKeyPrefix + hex.EncodeToString(id)
+	key = prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return key, prefix, HashAPIKey(key)
+}
+
+// IsAPIKey — похож ли токен на API-ключ.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// SplitAPIKey возвращает открытую часть ключа.
+func SplitAPIKey(key string) (prefix string, ok bool) {
+	if !IsAPIKey(key) {
+		return "", false
+	}
+	// Секрет в base64url сам может содержать «_», поэтому режем по первому разделителю.
+	i := strings.IndexByte(key[len(APIKeyPrefix):], '_')
+	if i <= 0 || len(APIKeyPrefix)+i == len(key)-1 {
+		return "", false
+	}
+	return key[:len(APIKeyPrefix)+i], true
+}
+
+// HashAPIKey — у ключа 256 бит энтропии, медленный хэш вроде bcrypt ему не нужен.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func CheckAPIKey(key, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(hash)) == 1
+}
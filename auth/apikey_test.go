@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash := GenerateAPIKey()
+	if !IsAPIKey(key) || !strings.HasPrefix(key, prefix+"_") {
+		t.Fatalf("key %q does not start with prefix %q", key, prefix)
+	}
+	if got, ok := SplitAPIKey(key); !ok || got != prefix {
+		t.Errorf("SplitAPIKey(%q) = %q, %v; want %q", key, got, ok, prefix)
+	}
+	if !CheckAPIKey(key, hash) {
+		t.Error("CheckAPIKey rejects the generated key")
+	}
+	if CheckAPIKey(key+"x", hash) {
+		t.Error("CheckAPIKey accepts a different key")
+	}
+	if other, _, _ := GenerateAPIKey(); other == key {
+		t.Error("two generated keys are equal")
+	}
+}
+
+func TestSplitAPIKey(t *testing.T) {
+	tests := []struct {
+		key    string
+		prefix string
+		ok     bool
+	}{
+		{"mk_0a1b2c_secret", "mk_0a1b2c", true},
+		// Секрет в base64url может содержать «_».
+		{"mk_0a1b2c_se_cr_et", "mk_0a1b2c", true},
+		{"mk_0a1b2c_", "", false},
+		{"mk__secret", "", false},
+		{"mk_0a1b2c", "", false},
+		{"mk_", "", false},
+		{"eyJhbGciOiJIUzI1NiJ9.e30.sig", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			prefix, ok := SplitAPIKey(tt.key)
+			if prefix != tt.prefix || ok != tt.ok {
+				t.Errorf("SplitAPIKey(%q) = %q, %v; want %q, %v", tt.key, prefix, ok, tt.prefix, tt.ok)
+			}
+		})
+	}
+}
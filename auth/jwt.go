@@ -0,0 +1,179 @@
+// Package auth выпускает и проверяет токены доступа и хранит аутентифицированного
+// пользователя запроса.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+
+	issuer          = "myApi"
+	minSecretLength = 32
+)
+
+// Claims — полезная нагрузка JWT. Subject — ID пользователя, WorkspaceID — пространство,
+// выбранное при входе (0 — не выбрано).
+type Claims struct {
+	Subject     string `json:"sub"`
+	Email       string `json:"email,omitempty"`
+	WorkspaceID int    `json:"wid,omitempty"`
+	Type        string `json:"typ"`
+	ID          string `json:"jti"`
+	Issuer      string `json:"iss"`
+	IssuedAt    int64  `json:"iat"`
+	ExpiresAt   int64  `json:"exp"`
+}
+
+func (c Claims) UserID() (int, error) {
+	id, err := strconv.Atoi(c.Subject)
+	if err != nil {
+		return 0, fmt.Errorf("%w: bad subject", ErrInvalidToken)
+	}
+	return id, nil
+}
+
+func (c Claims) Expiry() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	AccessExpiresAt  time.Time
+	RefreshExpiresAt time.Time
+	// Refresh — claims refresh-токена, чтобы сервис мог сохранить его jti.
+	Refresh Claims
+}
+
+// TokenIssuer подписывает токены HS256 общим секретом.
+type TokenIssuer struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+func NewTokenIssuer(secret []byte, accessTTL, refreshTTL time.Duration) (*TokenIssuer, error) {
+	if len(secret) < minSecretLength {
+		return nil, fmt.Errorf("jwt secret must be at least %d bytes", minSecretLength)
+	}
+	return &TokenIssuer{
+		secret:     secret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}, nil
+}
+
+func (i *TokenIssuer) Issue(userID int, email string, workspaceID int) (TokenPair, error) {
+	now := i.now()
+	access := Claims{
+		Subject:     strconv.Itoa(userID),
+		Email:       email,
+		WorkspaceID: workspaceID,
+		Type:        TokenTypeAccess,
+		ID:          newTokenID(),
+		Issuer:      issuer,
+		IssuedAt:    now.Unix(),
+		ExpiresAt:   now.Add(i.accessTTL).Unix(),
+	}
+	refresh := access
+	refresh.Type = TokenTypeRefresh
+	refresh.ID = newTokenID()
+	refresh.ExpiresAt = now.Add(i.refreshTTL).Unix()
+
+	accessToken, err := i.sign(access)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refreshToken, err := i.sign(refresh)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		AccessExpiresAt:  access.Expiry(),
+		RefreshExpiresAt: refresh.Expiry(),
+		Refresh:          refresh,
+	}, nil
+}
+
+// Parse проверяет подпись, срок действия и тип токена.
+func (i *TokenIssuer) Parse(token, tokenType string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Typ string `json:"typ"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return Claims{}, fmt.Errorf("%w: unsupported header", ErrInvalidToken)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, i.mac(parts[0]+"."+parts[1])) {
+		return Claims{}, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Claims{}, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	if claims.Issuer != issuer || claims.Type != tokenType {
+		return Claims{}, fmt.Errorf("%w: wrong token type", ErrInvalidToken)
+	}
+	if !i.now().Before(claims.Expiry()) {
+		return Claims{}, fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+func (i *TokenIssuer) sign(claims Claims) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(i.mac(unsigned)), nil
+}
+
+func (i *TokenIssuer) mac(data string) []byte {
+	m := hmac.New(sha256.New, i.secret)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+func decodeSegment(seg string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func newTokenID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestIssuer(t *testing.T) *TokenIssuer {
+	t.Helper()
+	issuer, err := NewTokenIssuer([]byte(strings.Repeat("s", minSecretLength)), 15*time.Minute, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer.now = func() time.Time { return testNow }
+	return issuer
+}
+
+func TestNewTokenIssuerRejectsShortSecret(t *testing.T) {
+	if _, err := NewTokenIssuer([]byte("short"), time.Minute, time.Hour); err == nil {
+		t.Fatal("expected an error for a short secret")
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	issuer := newTestIssuer(t)
+	pair, err := issuer.Issue(42, "ann@example.com", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := issuer.Parse(pair.AccessToken, TokenTypeAccess)
+	if err != nil {
+		t.Fatalf("Parse(access) = %v", err)
+	}
+	if id, _ := claims.UserID(); id != 42 || claims.Email != "ann@example.com" || claims.WorkspaceID != 7 {
+		t.Errorf("claims = %+v", claims)
+	}
+	if !claims.Expiry().Equal(testNow.Add(15 * time.Minute)) {
+		t.Errorf("Expiry() = %v", claims.Expiry())
+	}
+
+	refresh, err := issuer.Parse(pair.RefreshToken, TokenTypeRefresh)
+	if err != nil {
+		t.Fatalf("Parse(refresh) = %v", err)
+	}
+	if refresh.ID != pair.Refresh.ID || refresh.ID == claims.ID {
+		t.Errorf("refresh jti = %q, access jti = %q, pair jti = %q", refresh.ID, claims.ID, pair.Refresh.ID)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	issuer := newTestIssuer(t)
+	pair, err := issuer.Issue(42, "ann@example.com", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(pair.AccessToken, ".")
+
+	other, err := NewTokenIssuer([]byte(strings.Repeat("x", minSecretLength)), time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.now = issuer.now
+	foreign, err := other.Issue(42, "ann@example.com", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		t.Fatal(err)
+	}
+	claims.Subject = "1"
+	tamperedPayload := parts[0] + "." + encodeSegment(t, claims) + "." + parts[2]
+
+	sig := []byte(parts[2])
+	sig[0] ^= 1
+	tamperedSig := parts[0] + "." + parts[1] + "." + string(sig)
+
+	tests := []struct {
+		name      string
+		token     string
+		tokenType string
+	}{
+		{"empty", "", TokenTypeAccess},
+		{"two segments", parts[0] + "." + parts[1], TokenTypeAccess},
+		{"tampered payload", tamperedPayload, TokenTypeAccess},
+		{"tampered signature", tamperedSig, TokenTypeAccess},
+		{"no signature", parts[0] + "." + parts[1] + ".", TokenTypeAccess},
+		{"signed with another secret", foreign.AccessToken, TokenTypeAccess},
+		{"alg none", resign(t, issuer, `{"alg":"none","typ":"JWT"}`, parts[1]), TokenTypeAccess},
+		{"alg none unsigned", encodeRaw(`{"alg":"none","typ":"JWT"}`) + "." + parts[1] + ".", TokenTypeAccess},
+		{"alg HS512", resign(t, issuer, `{"alg":"HS512","typ":"JWT"}`, parts[1]), TokenTypeAccess},
+		{"refresh as access", pair.RefreshToken, TokenTypeAccess},
+		{"access as refresh", pair.AccessToken, TokenTypeRefresh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := issuer.Parse(tt.token, tt.tokenType); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Parse() = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestParseWrongIssuer(t *testing.T) {
+	issuer := newTestIssuer(t)
+	claims := Claims{
+		Subject:   "42",
+		Type:      TokenTypeAccess,
+		ID:        "jti",
+		Issuer:    "someone-else",
+		IssuedAt:  testNow.Unix(),
+		ExpiresAt: testNow.Add(time.Hour).Unix(),
+	}
+	token, err := issuer.sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := issuer.Parse(token, TokenTypeAccess); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Parse() = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestParseExpiry(t *testing.T) {
+	issuer := newTestIssuer(t)
+	pair, err := issuer.Issue(42, "ann@example.com", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := pair.AccessExpiresAt
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		valid bool
+	}{
+		{"one second before exp", exp.Add(-time.Second), true},
+		{"exactly at exp", exp, false},
+		{"after exp", exp.Add(time.Second), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer.now = func() time.Time { return tt.now }
+			_, err := issuer.Parse(pair.AccessToken, TokenTypeAccess)
+			if tt.valid && err != nil {
+				t.Errorf("Parse() = %v, want valid", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Parse() = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestClaimsUserID(t *testing.T) {
+	if _, err := (Claims{Subject: "abc"}).UserID(); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("UserID() = %v, want ErrInvalidToken", err)
+	}
+}
+
+// resign подписывает header и payload правильным секретом, чтобы отказ вызвал только заголовок.
+func resign(t *testing.T, issuer *TokenIssuer, header, payload string) string {
+	t.Helper()
+	unsigned := encodeRaw(header) + "." + payload
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(issuer.mac(unsigned))
+}
+
+func encodeSegment(t *testing.T, v any) string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func encodeRaw(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
@@ -0,0 +1,28 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// MaxPasswordLength — bcrypt учитывает только первые 72 байта пароля.
+const MaxPasswordLength = 72
+
+// dummyPasswordHash — хэш случайного пароля со стоимостью bcrypt.DefaultCost, как у HashPassword.
+const dummyPasswordHash = "$2a$10$MJ0WHLQ.dUAzpRX1h91G3.rNB0KVnw.D0PxMD4H1LsRLRmT.o.9US"
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword возвращает false и для неверного пароля, и для испорченного хэша.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// SimulatePasswordCheck тратит на сравнение столько же, сколько CheckPassword, когда
+// пользователя нет: по времени ответа нельзя узнать, зарегистрирован ли email.
+func SimulatePasswordCheck(password string) {
+	CheckPassword(dummyPasswordHash, password)
+}
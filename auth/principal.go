@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"context"
+	"myApi/model"
+	"strconv"
+	"time"
+)
+
+// Principal — тот, от чьего имени выполняется запрос.
+type Principal struct {
+	UserID    int
+	Email     string
+	TokenID   string
+	ExpiresAt time.Time
+	Role      model.Role
+	// APIKeyID — ключ, которым аутентифицирован запрос; 0 для пользовательской сессии.
+	APIKeyID int
+	Scopes   model.Scopes
+	// WorkspaceID — пространство, к которому привязан токен или ключ; 0 — любое
+	// пространство, где пользователь участник.
+	WorkspaceID int
+}
+
+// Actor — как инициатор записывается в историю изменений.
+func (p Principal) Actor() string {
+	if p.APIKeyID != 0 {
+		return "apikey:" + strconv.Itoa(p.APIKeyID)
+	}
+	return "user:" + strconv.Itoa(p.UserID)
+}
+
+func (p Principal) HasScope(scope model.Scope) bool {
+	return p.Scopes.Allows(scope)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
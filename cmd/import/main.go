@@ -0,0 +1,149 @@
+// Команда import загружает задачи из файла JSON Lines или CSV в рабочее пространство
+// напрямую в базу из kis.ini, без ограничения на размер файла:
+//
+//	import -workspace ID [-format jsonl|csv] [-user ID] [-dry-run] FILE
+//
+// FILE «-» читает стандартный ввод. Формат по умолчанию определяется по расширению.
+// -user записывает задачи от имени пользователя, иначе автор не указывается, а в
+// истории инициатором значится system. Отклонённые строки печатаются в отчёте.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"myApi/auth"
+	"myApi/db"
+	"myApi/dto"
+	"myApi/model"
+	"myApi/repository/postgresql"
+	"myApi/reqctx"
+	"myApi/service"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type options struct {
+	workspace int
+	user      int
+	format    string
+	dryRun    bool
+	file      string
+}
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	var opts options
+	flag.IntVar(&opts.workspace, "workspace", 0, "workspace ID to import into")
+	flag.IntVar(&opts.user, "user", 0, "user ID recorded as the author of imported tasks")
+	flag.StringVar(&opts.format, "format", "", "file format: jsonl or csv (default: by file extension)")
+	flag.BoolVar(&opts.dryRun, "dry-run", false, "validate the file without importing")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: import -workspace ID [-format jsonl|csv] [-user ID] [-dry-run] FILE")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || opts.workspace <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	opts.file = flag.Arg(0)
+
+	report, err := run(context.Background(), logger, opts)
+	if err != nil {
+		logger.Error("Import failed", "error", err)
+		os.Exit(1)
+	}
+	printReport(os.Stdout, report)
+}
+
+func run(ctx context.Context, logger *slog.Logger, opts options) (model.ImportReport, error) {
+	format, err := importFormat(opts)
+	if err != nil {
+		return model.ImportReport{}, err
+	}
+	in := os.Stdin
+	if opts.file != "-" {
+		if in, err = os.Open(opts.file); err != nil {
+			return model.ImportReport{}, err
+		}
+		defer in.Close()
+	}
+
+	dsn, err := db.BuildDSN()
+	if err != nil {
+		return model.ImportReport{}, fmt.Errorf("build DSN: %w", err)
+	}
+	dbPool, err := db.NewPool(ctx, dsn, logger)
+	if err != nil {
+		return model.ImportReport{}, err
+	}
+	defer dbPool.Close()
+
+	taskRepo := postgresql.NewTaskRepository(dbPool, logger)
+	if os.Getenv("DB_ROW_LEVEL_SECURITY") == "true" {
+		taskRepo.EnableRowLevelSecurity()
+	}
+	userRepo := postgresql.NewUserRepository(dbPool, logger)
+	wsRepo := postgresql.NewWorkspaceRepository(dbPool, logger)
+	tasks := service.NewTaskService(taskRepo, userRepo, wsRepo, logger)
+
+	if _, err := wsRepo.GetWorkspace(ctx, opts.workspace); err != nil {
+		return model.ImportReport{}, fmt.Errorf("workspace %d: %w", opts.workspace, err)
+	}
+	ctx = reqctx.WithWorkspace(ctx, opts.workspace)
+	if opts.user != 0 {
+		member, err := wsRepo.IsMember(ctx, opts.workspace, opts.user)
+		if err != nil {
+			return model.ImportReport{}, err
+		}
+		if !member {
+			return model.ImportReport{}, fmt.Errorf("user %d is not a member of workspace %d", opts.user, opts.workspace)
+		}
+		principal := auth.Principal{UserID: opts.user, WorkspaceID: opts.workspace}
+		ctx = reqctx.WithActor(auth.WithPrincipal(ctx, principal), principal.Actor())
+	}
+
+	src, err := dto.NewImportSource(in, format)
+	if err != nil {
+		return model.ImportReport{}, err
+	}
+	return tasks.Import(ctx, src, opts.dryRun)
+}
+
+func importFormat(opts options) (model.ImportFormat, error) {
+	format := model.ImportFormat(opts.format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(opts.file)) {
+		case ".jsonl", ".ndjson":
+			format = model.ImportJSONL
+		case ".csv":
+			format = model.ImportCSV
+		default:
+			return "", errors.New("cannot tell the format from the file name, pass -format")
+		}
+	}
+	if !format.Valid() {
+		return "", fmt.Errorf("unknown format %q", format)
+	}
+	return format, nil
+}
+
+func printReport(w io.Writer, report model.ImportReport) {
+	for _, r := range report.Rejections {
+		fmt.Fprintf(w, "line %d: %s\n", r.Line, r.Error)
+	}
+	if report.Rejected > len(report.Rejections) {
+		fmt.Fprintf(w, "... and %d more rejected rows\n", report.Rejected-len(report.Rejections))
+	}
+	if report.DryRun {
+		fmt.Fprintf(w, "dry run: %d rows valid, %d rejected of %d\n", report.Total-report.Rejected, report.Rejected, report.Total)
+		return
+	}
+	fmt.Fprintf(w, "imported %d tasks, rejected %d of %d rows\n", report.Imported, report.Rejected, report.Total)
+}
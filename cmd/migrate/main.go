@@ -0,0 +1,84 @@
+// Команда migrate управляет схемой базы из kis.ini:
+//
+//	migrate up        применить все новые миграции
+//	migrate down [N]  откатить N последних миграций (по умолчанию 1)
+//	migrate status    показать применённые и ожидающие миграции
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"myApi/db"
+	"myApi/db/migrations"
+	"os"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	if err := run(context.Background(), logger, os.Args[1], os.Args[2:]); err != nil {
+		logger.Error("Migration failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, logger *slog.Logger, command string, args []string) error {
+	dsn, err := db.BuildDSN()
+	if err != nil {
+		return fmt.Errorf("build DSN: %w", err)
+	}
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer pool.Close()
+
+	migrator, err := migrations.NewMigrator(pool, logger)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		_, err = migrator.Up(ctx)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			if steps, err = strconv.Atoi(args[0]); err != nil {
+				return fmt.Errorf("invalid step count %q", args[0])
+			}
+		}
+		n, err := migrator.Down(ctx, steps)
+		logger.Info("Migrations reverted", "count", n)
+		return err
+	case "status":
+		list, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range list {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-40s %s\n", st.Version, st.Name, applied)
+		}
+		return nil
+	}
+	usage()
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate up | down [N] | status")
+	os.Exit(2)
+}
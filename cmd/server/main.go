@@ -2,18 +2,30 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"myApi/auth"
+	"myApi/config"
 	"myApi/db"
+	"myApi/db/migrations"
 	_ "myApi/docs"
 	"myApi/handler"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"myApi/repository/memory"
 	"myApi/repository/postgresql"
+	"myApi/service"
+	"myApi/storage"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -23,7 +35,7 @@ import (
 
 // @title           Task API
 // @version         1.0
-// @description     API for managing tasks and notes
+// @description     API for managing tasks and notes. Task endpoints work inside one workspace: pass its ID in the X-Workspace-ID header, otherwise the workspace bound to the token or the user's first workspace is used.
 // @contact.name   API Support
 // @contact.email  support@example.com
 // @host      localhost:8080
@@ -31,6 +43,7 @@ import (
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
+// @description "Bearer <access_token>" from /auth/login or "Bearer <api_key>" from /auth/keys
 
 func main() {
 	// 1. Настраиваем логгер
@@ -39,34 +52,139 @@ func main() {
 
 	logger.Info("Starting application")
 
-	// 2. Загружаем конфиг
+	// 2. Выбираем хранилище: STORAGE=memory запускает API без PostgreSQL
 	ctx := context.Background()
-	dsn, err := db.BuildDSN()
+	var (
+		dbPool      *db.Pool
+		taskRepo    service.TaskRepository
+		userRepo    service.UserRepository
+		tokenRepo   service.TokenRepository
+		keyRepo     service.APIKeyRepository
+		wsRepo      service.WorkspaceRepository
+		noteRepo    service.NoteRepository
+		commentRepo service.CommentRepository
+		attachRepo  service.AttachmentRepository
+		tagRepo     service.TagRepository
+		remindRepo  service.ReminderRepository
+	)
+	if os.Getenv("STORAGE") == "memory" {
+		memRepo := memory.NewTaskRepository(logger)
+		memRepo.Seed(model.Ltask)
+		taskRepo = memRepo
+		tagRepo = memRepo
+		remindRepo = memRepo
+		memWsRepo := memory.NewWorkspaceRepository()
+		wsRepo = memWsRepo
+		userRepo = memory.NewUserRepository(memWsRepo)
+		tokenRepo = memory.NewTokenRepository()
+		keyRepo = memory.NewAPIKeyRepository()
+		noteRepo = memory.NewNoteRepository()
+		commentRepo = memory.NewCommentRepository()
+		attachRepo = memory.NewAttachmentRepository()
+		logger.Warn("Using in-memory storage, data will be lost on restart")
+	} else {
+		// 3. Загружаем конфиг и создаем pool с логгером
+		dsn, err := db.BuildDSN()
+		if err != nil {
+			logger.Error("Failed to build DSN", "error", err)
+			os.Exit(1)
+		}
+
+		dbPool, err = db.NewPool(ctx, dsn, logger)
+		if err != nil {
+			logger.Error("Failed to initialize database pool", "error", err)
+			os.Exit(1)
+		}
+		defer dbPool.Close()
+
+		// AUTO_MIGRATE=true накатывает миграции до приёма запросов
+		if os.Getenv("AUTO_MIGRATE") == "true" {
+			if err := runMigrations(ctx, dbPool, logger); err != nil {
+				logger.Error("Failed to apply migrations", "error", err)
+				os.Exit(1)
+			}
+		}
+
+		// 4. Создаем репозитории с логгером
+		pgTaskRepo := postgresql.NewTaskRepository(dbPool, logger)
+		pgNoteRepo := postgresql.NewNoteRepository(dbPool, logger)
+		pgCommentRepo := postgresql.NewCommentRepository(dbPool, logger)
+		pgAttachRepo := postgresql.NewAttachmentRepository(dbPool, logger)
+		// DB_ROW_LEVEL_SECURITY=true дополнительно выставляет app.workspace_id для политик RLS
+		if os.Getenv("DB_ROW_LEVEL_SECURITY") == "true" {
+			pgTaskRepo.EnableRowLevelSecurity()
+			pgNoteRepo.EnableRowLevelSecurity()
+			pgCommentRepo.EnableRowLevelSecurity()
+			pgAttachRepo.EnableRowLevelSecurity()
+		}
+		taskRepo = pgTaskRepo
+		tagRepo = pgTaskRepo
+		remindRepo = pgTaskRepo
+		noteRepo = pgNoteRepo
+		commentRepo = pgCommentRepo
+		attachRepo = pgAttachRepo
+		userRepo = postgresql.NewUserRepository(dbPool, logger)
+		tokenRepo = postgresql.NewTokenRepository(dbPool, logger)
+		keyRepo = postgresql.NewAPIKeyRepository(dbPool, logger)
+		wsRepo = postgresql.NewWorkspaceRepository(dbPool, logger)
+	}
+
+	// 5. Сервисы с бизнес-правилами
+	issuer, err := newTokenIssuer(logger)
 	if err != nil {
-		logger.Error("Failed to build DSN", "error", err)
+		logger.Error("Failed to configure authentication", "error", err)
 		os.Exit(1)
 	}
-
-	// 3. Создаем pool с логгером
-	dbPool, err := db.NewPool(ctx, dsn, logger)
+	taskService := service.NewTaskService(taskRepo, userRepo, wsRepo, logger)
+	authService := service.NewAuthService(userRepo, tokenRepo, keyRepo, wsRepo, issuer, logger)
+	workspaceService := service.NewWorkspaceService(wsRepo, userRepo, logger)
+	noteService := service.NewNoteService(noteRepo, taskRepo, logger)
+	commentService := service.NewCommentService(commentRepo, taskRepo, logger)
+	attachmentService, err := newAttachmentService(attachRepo, taskRepo, logger)
 	if err != nil {
-		logger.Error("Failed to initialize database pool", "error", err)
+		logger.Error("Failed to configure attachments", "error", err)
 		os.Exit(1)
 	}
-	defer dbPool.Close()
+	tagService := service.NewTagService(tagRepo, logger)
 
-	// 4. Создаем репозитории с логгером
-	taskRepo := postgresql.NewTaskRepository(dbPool, logger)
+	// Напоминания о сроках отправляются из этого же процесса
+	reminderCfg, err := config.LoadReminderConfig("./kis.ini")
+	if err != nil {
+		logger.Error("Failed to load reminder config", "error", err)
+		os.Exit(1)
+	}
+	reminderCtx, stopReminders := context.WithCancel(ctx)
+	remindersDone := make(chan struct{})
+	if reminderCfg.Enabled {
+		scheduler := service.NewReminderScheduler(remindRepo, service.LogReminderSink{Logger: logger},
+			reminderCfg.Lead, reminderCfg.Interval, logger)
+		go func() {
+			defer close(remindersDone)
+			scheduler.Run(reminderCtx)
+		}()
+	} else {
+		close(remindersDone)
+	}
 
-	// 5. Создаем handlers с логгером
-	h := handler.NewHandler(taskRepo, logger)
+	// 6. Создаем handlers с логгером
+	h := handler.NewHandler(handler.Services{
+		Tasks:       taskService,
+		Notes:       noteService,
+		Comments:    commentService,
+		Attachments: attachmentService,
+		Tags:        tagService,
+		Auth:        authService,
+		Workspaces:  workspaceService,
+		Policy:      policy.New(),
+	}, logger)
 	healthHandler := handler.NewHealthHandler(dbPool)
 
-	// 6. Настраиваем router
+	// 7. Настраиваем router
 	gin.SetMode(gin.DebugMode)
 	router := gin.New()
 
 	// Middleware
+	router.Use(handler.RequestID())
 	router.Use(ginLogger(logger))
 	router.Use(gin.Recovery())
 
@@ -79,7 +197,7 @@ func main() {
 	// API routes
 	h.SetupRoutes(router)
 
-	// 7. Запуск сервера
+	// 8. Запуск сервера
 	srv := &http.Server{
 		Addr:         ":8080",
 		Handler:      router,
@@ -97,12 +215,14 @@ func main() {
 		}
 	}()
 
-	// 8. Graceful shutdown
+	// 9. Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopReminders()
+	<-remindersDone
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -114,6 +234,74 @@ func main() {
 	logger.Info("Server exited gracefully")
 }
 
+func runMigrations(ctx context.Context, dbPool *db.Pool, logger *slog.Logger) error {
+	pool := dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+	migrator, err := migrations.NewMigrator(pool, logger)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(ctx)
+	return err
+}
+
+// newTokenIssuer берёт секрет JWT из kis.ini или JWT_SECRET. Без секрета в production
+// сервер не стартует, в разработке генерируется случайный — токены живут до перезапуска.
+func newTokenIssuer(logger *slog.Logger) (*auth.TokenIssuer, error) {
+	cfg, err := config.LoadAuthConfig("./kis.ini")
+	if err != nil {
+		return nil, fmt.Errorf("load auth config: %w", err)
+	}
+
+	secret := []byte(cfg.JWTSecret)
+	if len(secret) == 0 {
+		if os.Getenv("ENV") == "production" {
+			return nil, errors.New("JWT_SECRET is not set")
+		}
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		logger.Warn("JWT_SECRET is not set, using a random secret; tokens will not survive a restart")
+	}
+
+	return auth.NewTokenIssuer(secret, cfg.AccessTTL, cfg.RefreshTTL)
+}
+
+// newAttachmentService выбирает хранилище вложений по секции [Attachments] из kis.ini.
+func newAttachmentService(repo service.AttachmentRepository, tasks service.TaskRepository, logger *slog.Logger) (*service.AttachmentService, error) {
+	cfg, err := config.LoadAttachmentConfig("./kis.ini")
+	if err != nil {
+		return nil, fmt.Errorf("load attachment config: %w", err)
+	}
+
+	var blobs storage.Storage
+	switch cfg.Storage {
+	case "s3":
+		blobs, err = storage.NewS3Storage(storage.S3Options{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			PathStyle: cfg.S3.PathStyle,
+		})
+	default:
+		blobs, err = storage.NewLocalStorage(cfg.Dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Attachment storage configured", "storage", cfg.Storage, "max_size", cfg.MaxSize)
+
+	return service.NewAttachmentService(repo, tasks, blobs, service.AttachmentLimits{
+		MaxSize:      cfg.MaxSize,
+		AllowedTypes: cfg.AllowedTypes,
+	}, logger), nil
+}
+
 func setupLogger() *slog.Logger {
 	// Создаем директорию
 	if err := os.MkdirAll("./logs", 0755); err != nil {
@@ -216,6 +404,7 @@ func ginLogger(logger *slog.Logger) gin.HandlerFunc {
 			"ip", c.ClientIP(),
 			"latency_ms", latency.Milliseconds(),
 			"user_agent", c.Request.UserAgent(),
+			"request_id", c.GetString("request_id"),
 		)
 	}
 }
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/ini.v1"
+)
+
+var defaultAttachmentTypes = []string{
+	"image/*",
+	"text/plain",
+	"application/pdf",
+	"application/zip",
+	"application/x-gzip",
+}
+
+// LoadAttachmentConfig читает секции [Attachments] и [S3]; ключи доступа S3_ACCESS_KEY и
+// S3_SECRET_KEY имеют приоритет над файлом. По умолчанию файлы до 10 МБ хранятся в ./attachments.
+func LoadAttachmentConfig(path string) (*AttachmentConfig, error) {
+	cfgFile, err := ini.LooseLoad(path)
+	if err != nil {
+		return nil, err
+	}
+	sec := cfgFile.Section("Attachments")
+	s3 := cfgFile.Section("S3")
+
+	cfg := &AttachmentConfig{
+		Storage:      sec.Key("storage").MustString("local"),
+		Dir:          sec.Key("dir").MustString("./attachments"),
+		MaxSize:      sec.Key("max_size_mb").MustInt64(10) << 20,
+		AllowedTypes: sec.Key("allowed_types").Strings(","),
+		S3: S3Config{
+			Endpoint:  s3.Key("endpoint").String(),
+			Region:    s3.Key("region").MustString("us-east-1"),
+			Bucket:    s3.Key("bucket").String(),
+			AccessKey: s3.Key("access_key").String(),
+			SecretKey: s3.Key("secret_key").String(),
+			PathStyle: s3.Key("path_style").MustBool(true),
+		},
+	}
+	if len(cfg.AllowedTypes) == 0 {
+		cfg.AllowedTypes = defaultAttachmentTypes
+	}
+	if key := os.Getenv("S3_ACCESS_KEY"); key != "" {
+		cfg.S3.AccessKey = key
+	}
+	if secret := os.Getenv("S3_SECRET_KEY"); secret != "" {
+		cfg.S3.SecretKey = secret
+	}
+	if cfg.Storage != "local" && cfg.Storage != "s3" {
+		return nil, fmt.Errorf("unknown attachment storage %q", cfg.Storage)
+	}
+	if cfg.MaxSize <= 0 {
+		return nil, fmt.Errorf("attachment max_size_mb must be positive")
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// LoadAuthConfig читает секцию [Auth]; переменная JWT_SECRET имеет приоритет над файлом.
+// Отсутствие файла не ошибка — секрет может прийти только из окружения.
+func LoadAuthConfig(path string) (*AuthConfig, error) {
+	cfgFile, err := ini.LooseLoad(path)
+	if err != nil {
+		return nil, err
+	}
+	sec := cfgFile.Section("Auth")
+
+	cfg := &AuthConfig{
+		JWTSecret:  sec.Key("jwt_secret").String(),
+		AccessTTL:  sec.Key("access_ttl").MustDuration(15 * time.Minute),
+		RefreshTTL: sec.Key("refresh_ttl").MustDuration(30 * 24 * time.Hour),
+	}
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		cfg.JWTSecret = secret
+	}
+
+	return cfg, nil
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type DatabaseConfig struct {
 	Server   string
 	Port     int
@@ -7,3 +9,35 @@ type DatabaseConfig struct {
 	Username string
 	Password string
 }
+
+type AuthConfig struct {
+	JWTSecret  string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// ReminderConfig — напоминания о сроках: за Lead до due_at, проверка раз в Interval.
+type ReminderConfig struct {
+	Enabled  bool
+	Lead     time.Duration
+	Interval time.Duration
+}
+
+// AttachmentConfig — вложения задач: бэкенд хранилища ("local" или "s3"), каталог для
+// local, предел размера файла в байтах и допустимые MIME-типы ("image/*" — любой image).
+type AttachmentConfig struct {
+	Storage      string
+	Dir          string
+	MaxSize      int64
+	AllowedTypes []string
+	S3           S3Config
+}
+
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// LoadReminderConfig читает секцию [Reminders]; REMINDER_LEAD_MINUTES имеет приоритет над
+// lead_minutes из файла. Без файла напоминания включены с настройками по умолчанию.
+func LoadReminderConfig(path string) (*ReminderConfig, error) {
+	cfgFile, err := ini.LooseLoad(path)
+	if err != nil {
+		return nil, err
+	}
+	sec := cfgFile.Section("Reminders")
+
+	cfg := &ReminderConfig{
+		Enabled:  sec.Key("enabled").MustBool(true),
+		Lead:     time.Duration(sec.Key("lead_minutes").MustInt(30)) * time.Minute,
+		Interval: sec.Key("interval").MustDuration(time.Minute),
+	}
+	if raw := os.Getenv("REMINDER_LEAD_MINUTES"); raw != "" {
+		minutes, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REMINDER_LEAD_MINUTES: %w", err)
+		}
+		cfg.Lead = time.Duration(minutes) * time.Minute
+	}
+	if cfg.Lead <= 0 || cfg.Interval <= 0 {
+		return nil, fmt.Errorf("reminder lead and interval must be positive")
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"myApi/model"
+	"time"
+)
+
+type AttachmentEntity struct {
+	ID          int       `db:"id"`
+	TaskID      int       `db:"task_id"`
+	WorkspaceID int       `db:"workspace_id"`
+	FileName    string    `db:"file_name"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	Checksum    string    `db:"checksum"`
+	StorageKey  string    `db:"storage_key"`
+	UploadedBy  *int      `db:"uploaded_by"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (a *AttachmentEntity) ToModel() *model.Attachment {
+	return &model.Attachment{
+		ID:          a.ID,
+		TaskID:      a.TaskID,
+		WorkspaceID: a.WorkspaceID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Checksum:    a.Checksum,
+		StorageKey:  a.StorageKey,
+		UploadedBy:  a.UploadedBy,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func AttachmentFromModel(a *model.Attachment) *AttachmentEntity {
+	return &AttachmentEntity{
+		ID:          a.ID,
+		TaskID:      a.TaskID,
+		WorkspaceID: a.WorkspaceID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Checksum:    a.Checksum,
+		StorageKey:  a.StorageKey,
+		UploadedBy:  a.UploadedBy,
+		CreatedAt:   a.CreatedAt,
+	}
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"myApi/model"
+	"time"
+)
+
+type CommentEntity struct {
+	ID          int        `db:"id"`
+	TaskID      int        `db:"task_id"`
+	ReplyTo     *int       `db:"reply_to"`
+	AuthorID    *int       `db:"author_id"`
+	WorkspaceID int        `db:"workspace_id"`
+	Body        string     `db:"body"`
+	CreatedAt   time.Time  `db:"created_at"`
+	EditedAt    *time.Time `db:"edited_at"`
+	DeletedAt   *time.Time `db:"deleted_at"`
+}
+
+func (c *CommentEntity) ToModel() *model.Comment {
+	return &model.Comment{
+		ID:          c.ID,
+		TaskID:      c.TaskID,
+		ReplyTo:     c.ReplyTo,
+		AuthorID:    c.AuthorID,
+		WorkspaceID: c.WorkspaceID,
+		Body:        c.Body,
+		CreatedAt:   c.CreatedAt,
+		EditedAt:    c.EditedAt,
+		DeletedAt:   c.DeletedAt,
+	}
+}
+
+func CommentFromModel(c *model.Comment) *CommentEntity {
+	return &CommentEntity{
+		ID:          c.ID,
+		TaskID:      c.TaskID,
+		ReplyTo:     c.ReplyTo,
+		AuthorID:    c.AuthorID,
+		WorkspaceID: c.WorkspaceID,
+		Body:        c.Body,
+		CreatedAt:   c.CreatedAt,
+		EditedAt:    c.EditedAt,
+		DeletedAt:   c.DeletedAt,
+	}
+}
+
+type CommentEditEntity struct {
+	ID        int       `db:"id"`
+	CommentID int       `db:"comment_id"`
+	Body      string    `db:"body"`
+	EditedBy  *int      `db:"edited_by"`
+	EditedAt  time.Time `db:"edited_at"`
+}
+
+func (e *CommentEditEntity) ToModel() model.CommentEdit {
+	return model.CommentEdit{
+		ID:        e.ID,
+		CommentID: e.CommentID,
+		Body:      e.Body,
+		EditedBy:  e.EditedBy,
+		EditedAt:  e.EditedAt,
+	}
+}
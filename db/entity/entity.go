@@ -6,35 +6,140 @@ import (
 )
 
 type TaskEntity struct {
-	ID          int       `db:"id"`
-	Title       string    `db:"title"`
-	Description string    `db:"description"`
-	Status      string    `db:"status"`
-	Priority    int       `db:"priority"`
-	CreatedAt   time.Time `db:"createdat"`
-	UpdatedAt   time.Time `db:"updatedat"`
+	ID           int        `db:"id"`
+	Title        string     `db:"title"`
+	Description  string     `db:"description"`
+	Status       string     `db:"status"`
+	Priority     int        `db:"priority"`
+	Version      int        `db:"version"`
+	CreatedAt    time.Time  `db:"createdat"`
+	UpdatedAt    time.Time  `db:"updatedat"`
+	StartedAt    *time.Time `db:"started_at"`
+	CompletedAt  *time.Time `db:"completed_at"`
+	DeletedAt    *time.Time `db:"deleted_at"`
+	CreatedBy    *int       `db:"created_by"`
+	AssigneeID   *int       `db:"assignee_id"`
+	WorkspaceID  int        `db:"workspace_id"`
+	Tags         []string   `db:"tags"`
+	ParentID     *int       `db:"parent_id"`
+	BlockedBy    []int      `db:"blocked_by"`
+	StartAt      *time.Time `db:"start_at"`
+	DueAt        *time.Time `db:"due_at"`
+	Timezone     string     `db:"timezone"`
+	Recurrence   string     `db:"recurrence"`
+	RecurredFrom *int       `db:"recurred_from"`
+	// RemindedAt — когда отправлено напоминание о сроке; в модель не попадает.
+	RemindedAt *time.Time `db:"reminded_at"`
+	// Вычисляются запросом: счётчики подзадач всех уровней и незавершённые блокеры.
+	SubtasksTotal     int   `db:"subtasks_total"`
+	SubtasksCompleted int   `db:"subtasks_completed"`
+	OpenBlockers      []int `db:"open_blockers"`
+}
+
+// TaskPage — страница списка задач. Total считается без учёта пагинации.
+type TaskPage struct {
+	Tasks   []TaskEntity
+	Total   int
+	HasMore bool
 }
 
 func (t *TaskEntity) ToModel() *model.Task {
 	return &model.Task{
-		ID:          t.ID,
-		Title:       t.Title,
-		Description: t.Description,
-		Status:      model.TaskStatus(t.Status),
-		Priority:    t.Priority,
-		CreatedAt:   t.CreatedAt,
-		UpdatedAt:   t.UpdatedAt,
+		ID:           t.ID,
+		Title:        t.Title,
+		Description:  t.Description,
+		Status:       model.TaskStatus(t.Status),
+		Priority:     t.Priority,
+		Version:      t.Version,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+		StartedAt:    t.StartedAt,
+		CompletedAt:  t.CompletedAt,
+		DeletedAt:    t.DeletedAt,
+		CreatedBy:    t.CreatedBy,
+		AssigneeID:   t.AssigneeID,
+		WorkspaceID:  t.WorkspaceID,
+		Tags:         t.Tags,
+		ParentID:     t.ParentID,
+		BlockedBy:    t.BlockedBy,
+		StartAt:      t.StartAt,
+		DueAt:        t.DueAt,
+		Timezone:     t.Timezone,
+		Recurrence:   t.Recurrence,
+		RecurredFrom: t.RecurredFrom,
+		Subtasks: model.SubtaskProgress{
+			Total:     t.SubtasksTotal,
+			Completed: t.SubtasksCompleted,
+		},
+		OpenBlockers: t.OpenBlockers,
 	}
 
 }
 func FromModel(task *model.Task) *TaskEntity {
 	return &TaskEntity{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      string(task.Status),
-		Priority:    task.Priority,
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
+		ID:           task.ID,
+		Title:        task.Title,
+		Description:  task.Description,
+		Status:       string(task.Status),
+		Priority:     task.Priority,
+		Version:      task.Version,
+		CreatedAt:    task.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
+		StartedAt:    task.StartedAt,
+		CompletedAt:  task.CompletedAt,
+		DeletedAt:    task.DeletedAt,
+		CreatedBy:    task.CreatedBy,
+		AssigneeID:   task.AssigneeID,
+		WorkspaceID:  task.WorkspaceID,
+		Tags:         task.Tags,
+		ParentID:     task.ParentID,
+		BlockedBy:    task.BlockedBy,
+		StartAt:      task.StartAt,
+		DueAt:        task.DueAt,
+		Timezone:     task.Timezone,
+		Recurrence:   task.Recurrence,
+		RecurredFrom: task.RecurredFrom,
+	}
+}
+
+type AuditEntity struct {
+	ID          int64                        `db:"id"`
+	TaskID      int                          `db:"task_id"`
+	WorkspaceID int                          `db:"workspace_id"`
+	Action      string                       `db:"action"`
+	Actor       string                       `db:"actor"`
+	RequestID   string                       `db:"request_id"`
+	Changes     map[string]model.FieldChange `db:"changes"`
+	CreatedAt   time.Time                    `db:"created_at"`
+}
+
+func (a *AuditEntity) ToModel() model.AuditEntry {
+	return model.AuditEntry{
+		ID:        a.ID,
+		TaskID:    a.TaskID,
+		Action:    model.AuditAction(a.Action),
+		Actor:     a.Actor,
+		RequestID: a.RequestID,
+		Changes:   a.Changes,
+		CreatedAt: a.CreatedAt,
 	}
 }
+
+// SearchHitEntity — задача из результатов поиска; Title и Snippet с маркерами совпадений.
+type SearchHitEntity struct {
+	Task    TaskEntity
+	Rank    float64
+	Title   string
+	Snippet string
+}
+
+type SearchPage struct {
+	Hits  []SearchHitEntity
+	Total int
+}
+
+// BulkResultEntity — итог массовой операции для одной задачи; Err — почему изменение не применилось.
+type BulkResultEntity struct {
+	Task TaskEntity
+	Err  error
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"myApi/model"
+	"time"
+)
+
+type NoteEntity struct {
+	ID          int       `db:"id"`
+	Title       string    `db:"title"`
+	Body        string    `db:"body"`
+	TaskID      *int      `db:"task_id"`
+	WorkspaceID int       `db:"workspace_id"`
+	CreatedBy   *int      `db:"created_by"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+type NotePage struct {
+	Notes []NoteEntity
+	Total int
+}
+
+func (n *NoteEntity) ToModel() *model.Note {
+	return &model.Note{
+		ID:          n.ID,
+		Title:       n.Title,
+		Body:        n.Body,
+		TaskID:      n.TaskID,
+		WorkspaceID: n.WorkspaceID,
+		CreatedBy:   n.CreatedBy,
+		CreatedAt:   n.CreatedAt,
+		UpdatedAt:   n.UpdatedAt,
+	}
+}
+
+func NoteFromModel(n *model.Note) *NoteEntity {
+	return &NoteEntity{
+		ID:          n.ID,
+		Title:       n.Title,
+		Body:        n.Body,
+		TaskID:      n.TaskID,
+		WorkspaceID: n.WorkspaceID,
+		CreatedBy:   n.CreatedBy,
+		CreatedAt:   n.CreatedAt,
+		UpdatedAt:   n.UpdatedAt,
+	}
+}
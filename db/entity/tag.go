@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"myApi/model"
+	"time"
+)
+
+type TagEntity struct {
+	ID          int       `db:"id"`
+	WorkspaceID int       `db:"workspace_id"`
+	Name        string    `db:"name"`
+	CreatedAt   time.Time `db:"created_at"`
+	TaskCount   int       `db:"task_count"`
+}
+
+func (t *TagEntity) ToModel() *model.Tag {
+	return &model.Tag{
+		ID:          t.ID,
+		Name:        t.Name,
+		WorkspaceID: t.WorkspaceID,
+		TaskCount:   t.TaskCount,
+		CreatedAt:   t.CreatedAt,
+	}
+}
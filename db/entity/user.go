@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"myApi/model"
+	"time"
+)
+
+type UserEntity struct {
+	ID           int       `db:"id"`
+	Email        string    `db:"email"`
+	Name         string    `db:"name"`
+	PasswordHash string    `db:"password_hash"`
+	Role         string    `db:"role"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+func (u *UserEntity) ToModel() *model.User {
+	return &model.User{
+		ID:           u.ID,
+		Email:        u.Email,
+		Name:         u.Name,
+		PasswordHash: u.PasswordHash,
+		Role:         model.Role(u.Role),
+		CreatedAt:    u.CreatedAt,
+		UpdatedAt:    u.UpdatedAt,
+	}
+}
+
+// RefreshTokenEntity — выданный refresh-токен; сам токен не хранится, только его jti.
+type RefreshTokenEntity struct {
+	ID        string     `db:"jti"`
+	UserID    int        `db:"user_id"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+type APIKeyEntity struct {
+	ID     int      `db:"id"`
+	UserID int      `db:"user_id"`
+	Name   string   `db:"name"`
+	Prefix string   `db:"prefix"`
+	Hash   string   `db:"key_hash"`
+	Scopes []string `db:"scopes"`
+	// WorkspaceID — пространство, которым ограничен ключ; nil — любое пространство владельца.
+	WorkspaceID *int       `db:"workspace_id"`
+	ExpiresAt   *time.Time `db:"expires_at"`
+	LastUsedAt  *time.Time `db:"last_used_at"`
+	RevokedAt   *time.Time `db:"revoked_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
+
+func (k *APIKeyEntity) ToModel() *model.APIKey {
+	scopes := make(model.Scopes, len(k.Scopes))
+	for i, s := range k.Scopes {
+		scopes[i] = model.Scope(s)
+	}
+	return &model.APIKey{
+		ID:          k.ID,
+		UserID:      k.UserID,
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		Hash:        k.Hash,
+		Scopes:      scopes,
+		WorkspaceID: k.WorkspaceID,
+		ExpiresAt:   k.ExpiresAt,
+		LastUsedAt:  k.LastUsedAt,
+		RevokedAt:   k.RevokedAt,
+		CreatedAt:   k.CreatedAt,
+	}
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"myApi/model"
+	"time"
+)
+
+type WorkspaceEntity struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	CreatedBy *int      `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (w *WorkspaceEntity) ToModel() *model.Workspace {
+	return &model.Workspace{
+		ID:        w.ID,
+		Name:      w.Name,
+		CreatedBy: w.CreatedBy,
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+type WorkspaceMemberEntity struct {
+	WorkspaceID int       `db:"workspace_id"`
+	UserID      int       `db:"user_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (m *WorkspaceMemberEntity) ToModel() model.WorkspaceMember {
+	return model.WorkspaceMember{
+		WorkspaceID: m.WorkspaceID,
+		UserID:      m.UserID,
+		CreatedAt:   m.CreatedAt,
+	}
+}
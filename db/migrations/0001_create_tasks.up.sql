@@ -0,0 +1,11 @@
+CREATE SCHEMA IF NOT EXISTS md;
+
+CREATE TABLE IF NOT EXISTS md.tasks (
+    id          serial PRIMARY KEY,
+    title       text        NOT NULL,
+    description text        NOT NULL DEFAULT '',
+    status      text        NOT NULL DEFAULT 'pending',
+    priority    integer     NOT NULL DEFAULT 3,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now()
+);
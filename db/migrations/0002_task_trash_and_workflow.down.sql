@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS md.tasks_deleted_at_idx;
+DROP INDEX IF EXISTS md.tasks_status_idx;
+DROP INDEX IF EXISTS md.tasks_created_at_idx;
+
+ALTER TABLE md.tasks DROP CONSTRAINT IF EXISTS tasks_status_check;
+
+ALTER TABLE md.tasks
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS completed_at,
+    DROP COLUMN IF EXISTS started_at;
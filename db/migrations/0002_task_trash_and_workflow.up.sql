@@ -0,0 +1,11 @@
+ALTER TABLE md.tasks
+    ADD COLUMN IF NOT EXISTS started_at   timestamptz,
+    ADD COLUMN IF NOT EXISTS completed_at timestamptz,
+    ADD COLUMN IF NOT EXISTS deleted_at   timestamptz;
+
+ALTER TABLE md.tasks
+    ADD CONSTRAINT tasks_status_check CHECK (status IN ('pending', 'in_progress', 'completed'));
+
+CREATE INDEX IF NOT EXISTS tasks_created_at_idx ON md.tasks (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS tasks_status_idx ON md.tasks (status) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS tasks_deleted_at_idx ON md.tasks (deleted_at) WHERE deleted_at IS NOT NULL;
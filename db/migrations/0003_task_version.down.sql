@@ -0,0 +1 @@
+ALTER TABLE md.tasks DROP COLUMN IF EXISTS version;
@@ -0,0 +1 @@
+ALTER TABLE md.tasks ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
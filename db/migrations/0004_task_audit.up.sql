@@ -0,0 +1,12 @@
+-- task_id без внешнего ключа: история переживает окончательное удаление задачи.
+CREATE TABLE IF NOT EXISTS md.task_audit (
+    id         bigserial PRIMARY KEY,
+    task_id    integer     NOT NULL,
+    action     text        NOT NULL,
+    actor      text        NOT NULL,
+    request_id text        NOT NULL DEFAULT '',
+    changes    jsonb       NOT NULL DEFAULT '{}',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS task_audit_task_id_idx ON md.task_audit (task_id, id DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS md.revoked_tokens;
+DROP TABLE IF EXISTS md.refresh_tokens;
+DROP TABLE IF EXISTS md.users;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS md.users (
+    id            serial PRIMARY KEY,
+    email         text        NOT NULL UNIQUE,
+    name          text        NOT NULL DEFAULT '',
+    password_hash text        NOT NULL,
+    created_at    timestamptz NOT NULL DEFAULT now(),
+    updated_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS md.refresh_tokens (
+    jti        text PRIMARY KEY,
+    user_id    integer     NOT NULL REFERENCES md.users (id) ON DELETE CASCADE,
+    expires_at timestamptz NOT NULL,
+    revoked_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_id_idx ON md.refresh_tokens (user_id) WHERE revoked_at IS NULL;
+
+-- Отозванные access-токены хранятся до истечения их срока.
+CREATE TABLE IF NOT EXISTS md.revoked_tokens (
+    jti        text PRIMARY KEY,
+    expires_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS revoked_tokens_expires_at_idx ON md.revoked_tokens (expires_at);
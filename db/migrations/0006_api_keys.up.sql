@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS md.api_keys (
+    id           serial PRIMARY KEY,
+    user_id      integer     NOT NULL REFERENCES md.users (id) ON DELETE CASCADE,
+    name         text        NOT NULL,
+    prefix       text        NOT NULL UNIQUE,
+    key_hash     text        NOT NULL,
+    scopes       text[]      NOT NULL,
+    expires_at   timestamptz,
+    last_used_at timestamptz,
+    revoked_at   timestamptz,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT api_keys_scopes_check CHECK (scopes <@ ARRAY['tasks:read', 'tasks:write', 'admin']::text[])
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON md.api_keys (user_id);
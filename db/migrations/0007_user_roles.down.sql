@@ -0,0 +1,2 @@
+ALTER TABLE md.users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE md.users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,12 @@
+ALTER TABLE md.users
+    ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'member';
+
+ALTER TABLE md.users
+    DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE md.users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('viewer', 'member', 'admin'));
+
+-- Первый зарегистрированный пользователь становится администратором.
+UPDATE md.users SET role = 'admin'
+WHERE id = (SELECT min(id) FROM md.users)
+  AND NOT EXISTS (SELECT 1 FROM md.users WHERE role = 'admin');
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS md.tasks_created_by_idx;
+DROP INDEX IF EXISTS md.tasks_assignee_id_idx;
+
+ALTER TABLE md.tasks
+    DROP COLUMN IF EXISTS assignee_id,
+    DROP COLUMN IF EXISTS created_by;
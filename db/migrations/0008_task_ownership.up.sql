@@ -0,0 +1,6 @@
+ALTER TABLE md.tasks
+    ADD COLUMN IF NOT EXISTS created_by  integer REFERENCES md.users (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS assignee_id integer REFERENCES md.users (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS tasks_assignee_id_idx ON md.tasks (assignee_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS tasks_created_by_idx ON md.tasks (created_by) WHERE deleted_at IS NULL;
@@ -0,0 +1,12 @@
+DROP POLICY IF EXISTS task_audit_workspace_isolation ON md.task_audit;
+DROP POLICY IF EXISTS tasks_workspace_isolation ON md.tasks;
+ALTER TABLE md.task_audit DISABLE ROW LEVEL SECURITY;
+ALTER TABLE md.tasks DISABLE ROW LEVEL SECURITY;
+
+ALTER TABLE md.api_keys DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE md.task_audit DROP COLUMN IF EXISTS workspace_id;
+DROP INDEX IF EXISTS md.tasks_workspace_id_idx;
+ALTER TABLE md.tasks DROP COLUMN IF EXISTS workspace_id;
+
+DROP TABLE IF EXISTS md.workspace_members;
+DROP TABLE IF EXISTS md.workspaces;
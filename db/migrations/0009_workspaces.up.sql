@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS md.workspaces (
+    id         serial PRIMARY KEY,
+    name       text        NOT NULL,
+    created_by integer     REFERENCES md.users (id) ON DELETE SET NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS md.workspace_members (
+    workspace_id integer     NOT NULL REFERENCES md.workspaces (id) ON DELETE CASCADE,
+    user_id      integer     NOT NULL REFERENCES md.users (id) ON DELETE CASCADE,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS workspace_members_user_id_idx ON md.workspace_members (user_id);
+
+-- Существующие задачи и пользователи попадают в общее пространство Default (id = 1).
+INSERT INTO md.workspaces (id, name) VALUES (1, 'Default') ON CONFLICT (id) DO NOTHING;
+SELECT setval(pg_get_serial_sequence('md.workspaces', 'id'), GREATEST((SELECT max(id) FROM md.workspaces), 1));
+
+INSERT INTO md.workspace_members (workspace_id, user_id)
+SELECT 1, id FROM md.users
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE md.tasks ADD COLUMN IF NOT EXISTS workspace_id integer NOT NULL DEFAULT 1 REFERENCES md.workspaces (id);
+ALTER TABLE md.tasks ALTER COLUMN workspace_id DROP DEFAULT;
+CREATE INDEX IF NOT EXISTS tasks_workspace_id_idx ON md.tasks (workspace_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE md.task_audit ADD COLUMN IF NOT EXISTS workspace_id integer;
+UPDATE md.task_audit a SET workspace_id = COALESCE((SELECT t.workspace_id FROM md.tasks t WHERE t.id = a.task_id), 1)
+WHERE workspace_id IS NULL;
+ALTER TABLE md.task_audit ALTER COLUMN workspace_id SET NOT NULL;
+
+-- API-ключ может быть привязан к одному пространству.
+ALTER TABLE md.api_keys ADD COLUMN IF NOT EXISTS workspace_id integer REFERENCES md.workspaces (id) ON DELETE CASCADE;
+
+-- Политики row-level security. Они действуют, только если включить RLS
+-- (ALTER TABLE md.tasks ENABLE ROW LEVEL SECURITY) и подключаться не владельцем таблиц;
+-- приложение с DB_ROW_LEVEL_SECURITY=true выставляет app.workspace_id в каждой транзакции.
+DROP POLICY IF EXISTS tasks_workspace_isolation ON md.tasks;
+CREATE POLICY tasks_workspace_isolation ON md.tasks
+    USING (workspace_id = current_setting('app.workspace_id', true)::integer);
+
+DROP POLICY IF EXISTS task_audit_workspace_isolation ON md.task_audit;
+CREATE POLICY task_audit_workspace_isolation ON md.task_audit
+    USING (workspace_id = current_setting('app.workspace_id', true)::integer);
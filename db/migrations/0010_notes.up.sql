@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS md.notes (
+    id           serial PRIMARY KEY,
+    workspace_id integer     NOT NULL REFERENCES md.workspaces (id) ON DELETE CASCADE,
+    task_id      integer     REFERENCES md.tasks (id) ON DELETE SET NULL,
+    title        text        NOT NULL,
+    body         text        NOT NULL DEFAULT '',
+    created_by   integer     REFERENCES md.users (id) ON DELETE SET NULL,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    updated_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS notes_workspace_updated_idx ON md.notes (workspace_id, updated_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS notes_task_id_idx ON md.notes (task_id) WHERE task_id IS NOT NULL;
+
+DROP POLICY IF EXISTS notes_workspace_isolation ON md.notes;
+CREATE POLICY notes_workspace_isolation ON md.notes
+    USING (workspace_id = current_setting('app.workspace_id', true)::integer);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS md.task_tags;
+DROP TABLE IF EXISTS md.tags;
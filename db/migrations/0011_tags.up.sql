@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS md.tags (
+    id           serial PRIMARY KEY,
+    workspace_id integer     NOT NULL REFERENCES md.workspaces (id) ON DELETE CASCADE,
+    -- Имя хранится нормализованным (нижний регистр, без лишних пробелов).
+    name         text        NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (workspace_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS md.task_tags (
+    task_id integer NOT NULL REFERENCES md.tasks (id) ON DELETE CASCADE,
+    tag_id  integer NOT NULL REFERENCES md.tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_tags_tag_id_idx ON md.task_tags (tag_id);
+
+DROP POLICY IF EXISTS tags_workspace_isolation ON md.tags;
+CREATE POLICY tags_workspace_isolation ON md.tags
+    USING (workspace_id = current_setting('app.workspace_id', true)::integer);
@@ -0,0 +1,6 @@
+-- Подзадачи. При окончательном удалении родителя его подзадачи становятся корневыми.
+ALTER TABLE md.tasks
+    ADD COLUMN IF NOT EXISTS parent_id integer REFERENCES md.tasks (id) ON DELETE SET NULL,
+    ADD CONSTRAINT tasks_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS tasks_parent_id_idx ON md.tasks (parent_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,10 @@
+-- blocker_id блокирует task_id: task_id нельзя начать или завершить, пока blocker_id не завершён.
+CREATE TABLE IF NOT EXISTS md.task_dependencies (
+    task_id    integer     NOT NULL REFERENCES md.tasks (id) ON DELETE CASCADE,
+    blocker_id integer     NOT NULL REFERENCES md.tasks (id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (task_id, blocker_id),
+    CHECK (task_id <> blocker_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_dependencies_blocker_id_idx ON md.task_dependencies (blocker_id);
@@ -0,0 +1,5 @@
+ALTER TABLE md.tasks
+    DROP COLUMN IF EXISTS reminded_at,
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS due_at,
+    DROP COLUMN IF EXISTS start_at;
@@ -0,0 +1,12 @@
+-- Плановые даты. timezone — IANA-зона, в которой задача показывается и в которой
+-- разбираются даты без смещения; пустая строка означает UTC.
+-- reminded_at — когда отправлено напоминание о сроке; сбрасывается при смене due_at.
+ALTER TABLE md.tasks
+    ADD COLUMN IF NOT EXISTS start_at    timestamptz,
+    ADD COLUMN IF NOT EXISTS due_at      timestamptz,
+    ADD COLUMN IF NOT EXISTS timezone    text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS reminded_at timestamptz,
+    ADD CONSTRAINT tasks_start_before_due CHECK (start_at <= due_at);
+
+CREATE INDEX IF NOT EXISTS tasks_due_at_idx ON md.tasks (due_at)
+    WHERE deleted_at IS NULL AND status <> 'completed' AND due_at IS NOT NULL;
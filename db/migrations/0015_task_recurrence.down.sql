@@ -0,0 +1,3 @@
+ALTER TABLE md.tasks
+    DROP COLUMN IF EXISTS recurred_from,
+    DROP COLUMN IF EXISTS recurrence;
@@ -0,0 +1,9 @@
+-- Повторяющиеся задачи. recurrence — RRULE (RFC 5545) в каноническом виде, пустая строка —
+-- задача не повторяется. recurred_from — предыдущий экземпляр серии; уникальный индекс
+-- не даёт создать следующий экземпляр дважды, если задачу переоткрыли и снова завершили.
+ALTER TABLE md.tasks
+    ADD COLUMN IF NOT EXISTS recurrence    text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS recurred_from integer REFERENCES md.tasks (id) ON DELETE SET NULL,
+    ADD CONSTRAINT tasks_recurrence_needs_due CHECK (recurrence = '' OR due_at IS NOT NULL);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_recurred_from_key ON md.tasks (recurred_from);
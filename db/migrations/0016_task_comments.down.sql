@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS md.task_comment_edits;
+DROP TABLE IF EXISTS md.task_comments;
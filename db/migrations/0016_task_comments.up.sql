@@ -0,0 +1,36 @@
+-- Комментарии к задачам. reply_to строит ветки обсуждения; удалённый комментарий остаётся
+-- пустой заглушкой (deleted_at), чтобы ответы на него не потеряли место в ветке.
+CREATE TABLE IF NOT EXISTS md.task_comments (
+    id           serial PRIMARY KEY,
+    workspace_id integer     NOT NULL REFERENCES md.workspaces (id) ON DELETE CASCADE,
+    task_id      integer     NOT NULL REFERENCES md.tasks (id) ON DELETE CASCADE,
+    reply_to     integer     REFERENCES md.task_comments (id) ON DELETE CASCADE,
+    author_id    integer     REFERENCES md.users (id) ON DELETE SET NULL,
+    body         text        NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    edited_at    timestamptz,
+    deleted_at   timestamptz,
+    CHECK (reply_to <> id)
+);
+
+CREATE INDEX IF NOT EXISTS task_comments_task_id_idx ON md.task_comments (task_id, created_at, id);
+
+-- Прежние версии текста: запись добавляется при каждой правке.
+CREATE TABLE IF NOT EXISTS md.task_comment_edits (
+    id           serial PRIMARY KEY,
+    workspace_id integer     NOT NULL REFERENCES md.workspaces (id) ON DELETE CASCADE,
+    comment_id   integer     NOT NULL REFERENCES md.task_comments (id) ON DELETE CASCADE,
+    body         text        NOT NULL,
+    edited_by    integer     REFERENCES md.users (id) ON DELETE SET NULL,
+    edited_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS task_comment_edits_comment_id_idx ON md.task_comment_edits (comment_id, id);
+
+DROP POLICY IF EXISTS task_comments_workspace_isolation ON md.task_comments;
+CREATE POLICY task_comments_workspace_isolation ON md.task_comments
+    USING (workspace_id = current_setting('app.workspace_id', true)::integer);
+
+DROP POLICY IF EXISTS task_comment_edits_workspace_isolation ON md.task_comment_edits;
+CREATE POLICY task_comment_edits_workspace_isolation ON md.task_comment_edits
+    USING (workspace_id = current_setting('app.workspace_id', true)::integer);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS md.task_attachments;
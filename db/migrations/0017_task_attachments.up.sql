@@ -0,0 +1,21 @@
+-- Вложения задач. Содержимое лежит в blob-хранилище под storage_key, в базе — только
+-- описание файла и его SHA-256. При окончательном удалении задачи строки удаляются
+-- каскадом, а сами файлы остаются в хранилище.
+CREATE TABLE IF NOT EXISTS md.task_attachments (
+    id           serial PRIMARY KEY,
+    workspace_id integer     NOT NULL REFERENCES md.workspaces (id) ON DELETE CASCADE,
+    task_id      integer     NOT NULL REFERENCES md.tasks (id) ON DELETE CASCADE,
+    file_name    text        NOT NULL,
+    content_type text        NOT NULL,
+    size         bigint      NOT NULL CHECK (size >= 0),
+    checksum     text        NOT NULL,
+    storage_key  text        NOT NULL UNIQUE,
+    uploaded_by  integer     REFERENCES md.users (id) ON DELETE SET NULL,
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS task_attachments_task_id_idx ON md.task_attachments (task_id, id);
+
+DROP POLICY IF EXISTS task_attachments_workspace_isolation ON md.task_attachments;
+CREATE POLICY task_attachments_workspace_isolation ON md.task_attachments
+    USING (workspace_id = current_setting('app.workspace_id', true)::integer);
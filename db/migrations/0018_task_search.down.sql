@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS md.tasks_search_trgm_idx;
+DROP INDEX IF EXISTS md.tasks_search_vector_idx;
+ALTER TABLE md.tasks DROP COLUMN IF EXISTS search_vector;
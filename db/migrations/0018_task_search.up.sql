@@ -0,0 +1,15 @@
+-- Полнотекстовый поиск по задачам. search_vector индексирует заголовок (вес A) и описание
+-- (вес B) в русской и английской конфигурациях: русская приводит к основе кириллицу,
+-- английская — латиницу. Триграммный индекс на том же тексте находит слова с опечатками.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE md.tasks
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', title), 'A') ||
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('russian', description), 'B') ||
+        setweight(to_tsvector('english', description), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS tasks_search_vector_idx ON md.tasks USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS tasks_search_trgm_idx ON md.tasks USING gin ((title || ' ' || description) gin_trgm_ops);
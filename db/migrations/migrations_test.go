@@ -0,0 +1,98 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadEmbedded(t *testing.T) {
+	list, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if len(list) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	// Версии идут подряд с 1: пропуск обычно означает забытый файл.
+	for i, m := range list {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration #%d has version %d, want %d", i, m.Version, i+1)
+		}
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			t.Errorf("migration %d_%s has an empty up or down script", m.Version, m.Name)
+		}
+	}
+}
+
+func TestLoadPairsAndSorts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_notes.up.sql":    {Data: []byte("CREATE TABLE notes ();")},
+		"0010_notes.down.sql":  {Data: []byte("DROP TABLE notes;")},
+		"0002_tasks.down.sql":  {Data: []byte("DROP TABLE tasks;")},
+		"0002_tasks.up.sql":    {Data: []byte("CREATE TABLE tasks ();")},
+		"0001_schema.up.sql":   {Data: []byte("CREATE SCHEMA md;")},
+		"0001_schema.down.sql": {Data: []byte("DROP SCHEMA md;")},
+		"README.md":            {Data: []byte("not a migration")},
+	}
+	list, err := load(fsys)
+	if err != nil {
+		t.Fatalf("load() = %v", err)
+	}
+	want := []Migration{
+		{Version: 1, Name: "schema", Up: "CREATE SCHEMA md;", Down: "DROP SCHEMA md;"},
+		{Version: 2, Name: "tasks", Up: "CREATE TABLE tasks ();", Down: "DROP TABLE tasks;"},
+		{Version: 10, Name: "notes", Up: "CREATE TABLE notes ();", Down: "DROP TABLE notes;"},
+	}
+	if len(list) != len(want) {
+		t.Fatalf("load() = %d migrations, want %d", len(list), len(want))
+	}
+	for i := range want {
+		if list[i] != want[i] {
+			t.Errorf("migration #%d = %+v, want %+v", i, list[i], want[i])
+		}
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	script := &fstest.MapFile{Data: []byte("SELECT 1;")}
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "missing down script",
+			fsys: fstest.MapFS{"0001_a.up.sql": script, "0002_b.up.sql": script, "0002_b.down.sql": script},
+			want: "1_a has no down script",
+		},
+		{
+			name: "missing up script",
+			fsys: fstest.MapFS{"0001_a.down.sql": script},
+			want: "1_a has no up script",
+		},
+		{
+			name: "conflicting names",
+			fsys: fstest.MapFS{"0001_a.up.sql": script, "0001_b.down.sql": script},
+			want: "conflicting names",
+		},
+		{
+			name: "no name",
+			fsys: fstest.MapFS{"0001.up.sql": script},
+			want: "expected NNNN_name.up.sql",
+		},
+		{
+			name: "bad version",
+			fsys: fstest.MapFS{"v1_a.up.sql": script, "v1_a.down.sql": script},
+			want: "invalid version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(tt.fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("load() = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
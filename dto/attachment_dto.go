@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"myApi/model"
+	"time"
+)
+
+// AttachmentResponse — описание вложения; checksum — SHA-256 содержимого в hex.
+type AttachmentResponse struct {
+	ID          int       `json:"id"`
+	TaskID      int       `json:"task_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	UploadedBy  *int      `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func ToAttachmentResponse(attachment *model.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          attachment.ID,
+		TaskID:      attachment.TaskID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Checksum:    attachment.Checksum,
+		UploadedBy:  attachment.UploadedBy,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+type AttachmentListResponse struct {
+	List []AttachmentResponse `json:"list"`
+}
+
+func ToAttachmentListResponse(attachments []model.Attachment) AttachmentListResponse {
+	list := make([]AttachmentResponse, len(attachments))
+	for i := range attachments {
+		list[i] = ToAttachmentResponse(&attachments[i])
+	}
+	return AttachmentListResponse{List: list}
+}
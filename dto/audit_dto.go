@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"myApi/model"
+	"strconv"
+	"time"
+)
+
+type HistoryQuery struct {
+	Limit  int   `form:"limit" binding:"omitempty,min=1"`
+	Cursor int64 `form:"cursor" binding:"omitempty,min=1"`
+}
+
+type AuditEntryResponse struct {
+	ID        int64                        `json:"id"`
+	TaskID    int                          `json:"task_id"`
+	Action    string                       `json:"action"`
+	Actor     string                       `json:"actor"`
+	RequestID string                       `json:"request_id,omitempty"`
+	Changes   map[string]model.FieldChange `json:"changes"`
+	CreatedAt time.Time                    `json:"created_at"`
+}
+
+type HistoryResponse struct {
+	List       []AuditEntryResponse `json:"list"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+func ToHistoryResponse(page model.HistoryPage) HistoryResponse {
+	resp := HistoryResponse{List: make([]AuditEntryResponse, 0, len(page.Entries))}
+	for _, e := range page.Entries {
+		resp.List = append(resp.List, AuditEntryResponse{
+			ID:        e.ID,
+			TaskID:    e.TaskID,
+			Action:    string(e.Action),
+			Actor:     e.Actor,
+			RequestID: e.RequestID,
+			Changes:   e.Changes,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	if page.HasMore && len(page.Entries) > 0 {
+		resp.NextCursor = strconv.FormatInt(page.Entries[len(page.Entries)-1].ID, 10)
+	}
+	return resp
+}
@@ -0,0 +1,138 @@
+package dto
+
+import (
+	"myApi/auth"
+	"myApi/model"
+	"time"
+)
+
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Name     string `json:"name" binding:"max=200"`
+	Password string `json:"password" binding:"required"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	// WorkspaceID привязывает выданные токены к одному пространству.
+	WorkspaceID int `json:"workspace_id,omitempty" binding:"omitempty,min=1"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type TokenResponse struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int       `json:"expires_in"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+func ToTokenResponse(pair auth.TokenPair, now time.Time) TokenResponse {
+	return TokenResponse{
+		AccessToken:      pair.AccessToken,
+		RefreshToken:     pair.RefreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(pair.AccessExpiresAt.Sub(now).Seconds()),
+		AccessExpiresAt:  pair.AccessExpiresAt,
+		RefreshExpiresAt: pair.RefreshExpiresAt,
+	}
+}
+
+type UserResponse struct {
+	ID        int       `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToUserResponse(user *model.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Role:      string(user.Role),
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func ToUserResponses(users []model.User) []UserResponse {
+	resp := make([]UserResponse, len(users))
+	for i := range users {
+		resp[i] = ToUserResponse(&users[i])
+	}
+	return resp
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// WorkspaceID ограничивает ключ одним пространством.
+	WorkspaceID *int `json:"workspace_id,omitempty" binding:"omitempty,min=1"`
+}
+
+func (r CreateAPIKeyRequest) ToScopes() model.Scopes {
+	scopes := make(model.Scopes, len(r.Scopes))
+	for i, s := range r.Scopes {
+		scopes[i] = model.Scope(s)
+	}
+	return scopes
+}
+
+type APIKeyResponse struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
+	WorkspaceID *int       `json:"workspace_id,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse — единственный ответ, в котором ключ передаётся целиком.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+func ToAPIKeyResponse(key *model.APIKey) APIKeyResponse {
+	scopes := make([]string, len(key.Scopes))
+	for i, s := range key.Scopes {
+		scopes[i] = string(s)
+	}
+	return APIKeyResponse{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Scopes:      scopes,
+		WorkspaceID: key.WorkspaceID,
+		ExpiresAt:   key.ExpiresAt,
+		LastUsedAt:  key.LastUsedAt,
+		RevokedAt:   key.RevokedAt,
+		CreatedAt:   key.CreatedAt,
+	}
+}
+
+func ToAPIKeyResponses(keys []model.APIKey) []APIKeyResponse {
+	resp := make([]APIKeyResponse, len(keys))
+	for i := range keys {
+		resp[i] = ToAPIKeyResponse(&keys[i])
+	}
+	return resp
+}
@@ -0,0 +1,135 @@
+package dto
+
+import "myApi/model"
+
+// BulkFilter — выборка задач для массовой операции: те же условия, что у /task/list,
+// но без сортировки и пагинации. Пустой фильтр выбирает все живые задачи пространства.
+type BulkFilter struct {
+	Status      string `json:"status"`
+	PriorityMin *int   `json:"priority_min"`
+	PriorityMax *int   `json:"priority_max"`
+	CreatedFrom string `json:"created_from"`
+	CreatedTo   string `json:"created_to"`
+	UpdatedFrom string `json:"updated_from"`
+	UpdatedTo   string `json:"updated_to"`
+	Assignee    string `json:"assignee"`
+	CreatedBy   string `json:"created_by"`
+	Tags        string `json:"tags"`
+	TagMatch    string `json:"tag_match" binding:"omitempty,oneof=any all"`
+	Overdue     bool   `json:"overdue"`
+	DueFrom     string `json:"due_from"`
+	DueTo       string `json:"due_to"`
+}
+
+// BulkTaskRequest — тело /task/bulk: задачи задаются либо ids, либо filter. Без mode
+// операция атомарна.
+type BulkTaskRequest struct {
+	IDs        []int       `json:"ids" binding:"omitempty,dive,min=1"`
+	Filter     *BulkFilter `json:"filter"`
+	Status     *string     `json:"status" binding:"omitempty,oneof=pending in_progress completed"`
+	Priority   *int        `json:"priority" binding:"omitempty,min=1,max=5"`
+	AddTags    []string    `json:"add_tags"`
+	RemoveTags []string    `json:"remove_tags"`
+	Delete     bool        `json:"delete"`
+	Mode       string      `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+}
+
+// ToBulkRequest переводит тело в запрос сервиса; currentUserID подставляется вместо «me» в фильтре.
+func ToBulkRequest(req BulkTaskRequest, currentUserID int) (model.BulkRequest, error) {
+	bulk := model.BulkRequest{
+		IDs: req.IDs,
+		Change: model.BulkChange{
+			Priority:   req.Priority,
+			AddTags:    req.AddTags,
+			RemoveTags: req.RemoveTags,
+			Delete:     req.Delete,
+		},
+		Mode: model.BulkMode(req.Mode),
+	}
+	if req.Status != nil {
+		status := model.TaskStatus(*req.Status)
+		bulk.Change.Status = &status
+	}
+	if req.Filter != nil {
+		f := req.Filter
+		filter, err := ToTaskFilter(TaskListQuery{
+			Status:      f.Status,
+			PriorityMin: f.PriorityMin,
+			PriorityMax: f.PriorityMax,
+			CreatedFrom: f.CreatedFrom,
+			CreatedTo:   f.CreatedTo,
+			UpdatedFrom: f.UpdatedFrom,
+			UpdatedTo:   f.UpdatedTo,
+			Assignee:    f.Assignee,
+			CreatedBy:   f.CreatedBy,
+			Tags:        f.Tags,
+			TagMatch:    f.TagMatch,
+			Overdue:     f.Overdue,
+			DueFrom:     f.DueFrom,
+			DueTo:       f.DueTo,
+		}, currentUserID)
+		if err != nil {
+			return model.BulkRequest{}, err
+		}
+		bulk.Filter = &filter
+	}
+	return bulk, nil
+}
+
+// Итог массовой операции для одной задачи.
+const (
+	BulkUpdated   = "updated"
+	BulkDeleted   = "deleted"
+	BulkUnchanged = "unchanged"
+	BulkFailed    = "failed"
+)
+
+// BulkItemResponse — итог для одной задачи. У неудачных code — HTTP-статус, который
+// вернула бы одиночная операция, а task отсутствует.
+type BulkItemResponse struct {
+	ID     int           `json:"id"`
+	Result string        `json:"result" enums:"updated,deleted,unchanged,failed"`
+	Task   *TaskResponse `json:"task,omitempty"`
+	Code   int           `json:"code,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+type BulkResponse struct {
+	Mode      string             `json:"mode"`
+	Total     int                `json:"total"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Results   []BulkItemResponse `json:"results"`
+}
+
+// ToBulkResponse собирает ответ; describe переводит ошибку задачи в код и сообщение.
+func ToBulkResponse(report model.BulkReport, describe func(error) (int, string)) BulkResponse {
+	resp := BulkResponse{
+		Mode:    string(report.Mode),
+		Total:   len(report.Results),
+		Failed:  report.Failed(),
+		Results: make([]BulkItemResponse, len(report.Results)),
+	}
+	resp.Succeeded = resp.Total - resp.Failed
+	for i := range report.Results {
+		r := &report.Results[i]
+		item := BulkItemResponse{ID: r.ID}
+		switch {
+		case r.Err != nil:
+			item.Result = BulkFailed
+			item.Code, item.Error = describe(r.Err)
+		case r.Task == nil:
+			item.Result = BulkDeleted
+		case r.Changed:
+			item.Result = BulkUpdated
+		default:
+			item.Result = BulkUnchanged
+		}
+		if r.Task != nil {
+			task := ToTaskResponse(r.Task)
+			item.Task = &task
+		}
+		resp.Results[i] = item
+	}
+	return resp
+}
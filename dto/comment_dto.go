@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"myApi/model"
+	"time"
+)
+
+// CommentRequest — тело нового комментария; reply_to — комментарий, на который это ответ.
+type CommentRequest struct {
+	Body    string `json:"body" binding:"required"`
+	ReplyTo *int   `json:"reply_to,omitempty" binding:"omitempty,min=1"`
+}
+
+func ToCommentModel(taskID int, req CommentRequest) model.Comment {
+	return model.Comment{
+		TaskID:  taskID,
+		ReplyTo: req.ReplyTo,
+		Body:    req.Body,
+	}
+}
+
+// CommentEditRequest — новый текст комментария.
+type CommentEditRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// CommentResponse — комментарий с ответами. У удалённого комментария нет текста,
+// но он остаётся в ветке, чтобы ответы на него не потерялись.
+type CommentResponse struct {
+	ID        int               `json:"id"`
+	TaskID    int               `json:"task_id"`
+	ReplyTo   *int              `json:"reply_to,omitempty"`
+	AuthorID  *int              `json:"author_id,omitempty"`
+	Body      string            `json:"body"`
+	CreatedAt time.Time         `json:"created_at"`
+	EditedAt  *time.Time        `json:"edited_at,omitempty"`
+	Deleted   bool              `json:"deleted"`
+	Replies   []CommentResponse `json:"replies,omitempty"`
+}
+
+func ToCommentResponse(comment *model.Comment) CommentResponse {
+	return CommentResponse{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		ReplyTo:   comment.ReplyTo,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		EditedAt:  comment.EditedAt,
+		Deleted:   comment.DeletedAt != nil,
+	}
+}
+
+func toCommentThread(node *model.CommentNode) CommentResponse {
+	resp := ToCommentResponse(&node.Comment)
+	for _, reply := range node.Replies {
+		resp.Replies = append(resp.Replies, toCommentThread(reply))
+	}
+	return resp
+}
+
+type CommentListResponse struct {
+	List  []CommentResponse `json:"list"`
+	Total int               `json:"total"`
+}
+
+// ToCommentListResponse разворачивает ветки; total — число комментариев во всех ветках.
+func ToCommentListResponse(threads []*model.CommentNode, total int) CommentListResponse {
+	list := make([]CommentResponse, len(threads))
+	for i, node := range threads {
+		list[i] = toCommentThread(node)
+	}
+	return CommentListResponse{List: list, Total: total}
+}
+
+type CommentEditResponse struct {
+	Body     string    `json:"body"`
+	EditedBy *int      `json:"edited_by,omitempty"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+type CommentHistoryResponse struct {
+	List []CommentEditResponse `json:"list"`
+}
+
+func ToCommentHistoryResponse(edits []model.CommentEdit) CommentHistoryResponse {
+	list := make([]CommentEditResponse, len(edits))
+	for i, e := range edits {
+		list[i] = CommentEditResponse{
+			Body:     e.Body,
+			EditedBy: e.EditedBy,
+			EditedAt: e.EditedAt,
+		}
+	}
+	return CommentHistoryResponse{List: list}
+}
@@ -0,0 +1,46 @@
+package dto
+
+import "myApi/model"
+
+type DependencyRequest struct {
+	BlockerID int `json:"blocker_id" binding:"required,min=1"`
+}
+
+// TaskDependenciesResponse — задачи, которые блокируют данную, и задачи, которые ждут её.
+type TaskDependenciesResponse struct {
+	BlockedBy []TaskResponse `json:"blocked_by"`
+	Blocks    []TaskResponse `json:"blocks"`
+}
+
+func ToTaskDependenciesResponse(deps model.TaskDependencies) TaskDependenciesResponse {
+	return TaskDependenciesResponse{
+		BlockedBy: ToTaskResponses(deps.BlockedBy),
+		Blocks:    ToTaskResponses(deps.Blocks),
+	}
+}
+
+// PlanResponse — открытые задачи по волнам: level 0 можно брать в работу сейчас, каждая
+// следующая волна становится доступной после завершения предыдущих.
+type PlanResponse struct {
+	Steps []PlanStepResponse `json:"steps"`
+	Ready []TaskResponse     `json:"ready"`
+}
+
+type PlanStepResponse struct {
+	Level int            `json:"level"`
+	Tasks []TaskResponse `json:"tasks"`
+}
+
+func ToPlanResponse(steps []model.PlanStep) PlanResponse {
+	resp := PlanResponse{
+		Steps: make([]PlanStepResponse, 0, len(steps)),
+		Ready: []TaskResponse{},
+	}
+	for _, step := range steps {
+		resp.Steps = append(resp.Steps, PlanStepResponse{Level: step.Level, Tasks: ToTaskResponses(step.Tasks)})
+	}
+	if len(resp.Steps) > 0 {
+		resp.Ready = resp.Steps[0].Tasks
+	}
+	return resp
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"myApi/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// overdueSuffix отмечает в ETag просроченную задачу. Просрочка наступает без записи
+// в базу, поэтому версии для ETag мало: без отметки 304 отдавал бы старый флаг overdue.
+const overdueSuffix = "-overdue"
+
+// TaskETag — сильный ETag задачи, построенный по её версии и просрочке.
+func TaskETag(task *model.Task) string {
+	etag := strconv.Itoa(task.Version)
+	if task.Overdue(time.Now()) {
+		etag += overdueSuffix
+	}
+	return `"` + etag + `"`
+}
+
+// ParseTaskETag извлекает версию из ETag, выданного TaskETag. Слабые ETag не подходят для If-Match.
+// Просрочка на сравнение не влияет: она не мешает записи.
+func ParseTaskETag(etag string) (int, bool) {
+	etag = strings.TrimSpace(etag)
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.Atoi(strings.TrimSuffix(etag[1:len(etag)-1], overdueSuffix))
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// ParseTaskETags разбирает список ETag из If-Match и возвращает версии сильных ETag;
+// слабые и нераспознанные элементы пропускаются.
+func ParseTaskETags(header string) []int {
+	var versions []int
+	for _, candidate := range strings.Split(header, ",") {
+		if version, ok := ParseTaskETag(candidate); ok {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// TaskListETag — слабый ETag страницы списка: меняется при изменении состава страницы,
+// версии или просрочки любой задачи на ней или общего количества.
+func TaskListETag(resp TaskListResponse) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "%d|%s", resp.Total, resp.NextCursor)
+	for _, t := range resp.List {
+		fmt.Fprintf(h, "|%d:%d:%t", t.ID, t.Version, t.Overdue)
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
+
+// ETagMatches реализует сравнение для If-None-Match: список через запятую или «*».
+func ETagMatches(header, etag string) bool {
+	weak := func(s string) string { return strings.TrimPrefix(strings.TrimSpace(s), "W/") }
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || weak(candidate) == weak(etag) {
+			return true
+		}
+	}
+	return false
+}
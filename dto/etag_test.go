@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"myApi/model"
+	"testing"
+	"time"
+)
+
+func TestTaskETagOverdue(t *testing.T) {
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	tests := []struct {
+		name string
+		task model.Task
+		want string
+	}{
+		{name: "no due date", task: model.Task{Version: 3}, want: `"3"`},
+		{name: "due later", task: model.Task{Version: 3, DueAt: &future}, want: `"3"`},
+		{name: "overdue", task: model.Task{Version: 3, DueAt: &past}, want: `"3-overdue"`},
+		{name: "completed late", task: model.Task{Version: 3, DueAt: &past, Status: model.StatusCompleted}, want: `"3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etag := TaskETag(&tt.task)
+			if etag != tt.want {
+				t.Errorf("TaskETag() = %s, want %s", etag, tt.want)
+			}
+			// If-Match сравнивает только версию.
+			if version, ok := ParseTaskETag(etag); !ok || version != 3 {
+				t.Errorf("ParseTaskETag(%s) = %d, %v, want 3", etag, version, ok)
+			}
+		})
+	}
+	for _, etag := range []string{`W/"3"`, `"3-late"`, `"-overdue"`, `3-overdue`} {
+		if _, ok := ParseTaskETag(etag); ok {
+			t.Errorf("ParseTaskETag(%s) accepted", etag)
+		}
+	}
+}
+
+func TestTaskListETagOverdue(t *testing.T) {
+	page := func(overdue bool) TaskListResponse {
+		return TaskListResponse{Total: 1, List: []TaskResponse{{ID: 1, Version: 2, Overdue: overdue}}}
+	}
+	// Задача стала просроченной без новой версии — кэш страницы должен устареть.
+	if TaskListETag(page(false)) == TaskListETag(page(true)) {
+		t.Error("TaskListETag() ignores overdue")
+	}
+	if TaskListETag(page(true)) != TaskListETag(page(true)) {
+		t.Error("TaskListETag() is not stable")
+	}
+}
@@ -0,0 +1,265 @@
+package dto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"myApi/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxImportLine — предел длины строки JSON Lines; описание задачи намного короче.
+const maxImportLine = 1 << 20
+
+// ImportRow — задача в файле импорта: объект в строке JSON Lines или строка CSV, в
+// заголовке которого те же имена колонок. Обязателен только title. Даты — RFC 3339,
+// теги в CSV перечисляются через запятую.
+type ImportRow struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Priority    int      `json:"priority"`
+	Tags        []string `json:"tags"`
+	StartAt     string   `json:"start_at"`
+	DueAt       string   `json:"due_at"`
+	Timezone    string   `json:"timezone"`
+	CreatedAt   string   `json:"created_at"`
+	StartedAt   string   `json:"started_at"`
+	CompletedAt string   `json:"completed_at"`
+}
+
+// ImportQuery — query-параметры /task/import.
+type ImportQuery struct {
+	Format string `form:"format" binding:"required,oneof=jsonl csv"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type ImportRejectionResponse struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// ImportResponse — отчёт импорта; rejections перечисляет не больше 1000 строк.
+type ImportResponse struct {
+	Total      int                       `json:"total"`
+	Imported   int                       `json:"imported"`
+	Rejected   int                       `json:"rejected"`
+	DryRun     bool                      `json:"dry_run"`
+	Rejections []ImportRejectionResponse `json:"rejections"`
+}
+
+func ToImportResponse(report model.ImportReport) ImportResponse {
+	resp := ImportResponse{
+		Total:      report.Total,
+		Imported:   report.Imported,
+		Rejected:   report.Rejected,
+		DryRun:     report.DryRun,
+		Rejections: make([]ImportRejectionResponse, len(report.Rejections)),
+	}
+	for i, r := range report.Rejections {
+		resp.Rejections[i] = ImportRejectionResponse{Line: r.Line, Error: r.Error}
+	}
+	return resp
+}
+
+// NewImportSource читает задачи из r в формате format. Для CSV сразу читается заголовок:
+// неизвестная колонка или отсутствие title — ошибка model.ErrInvalidImport.
+func NewImportSource(r io.Reader, format model.ImportFormat) (model.ImportSource, error) {
+	r = skipBOM(r)
+	switch format {
+	case model.ImportJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+		return &jsonlSource{scanner: scanner}, nil
+	case model.ImportCSV:
+		return newCSVSource(r)
+	}
+	return nil, fmt.Errorf("%w: unknown format %q", model.ErrInvalidImport, format)
+}
+
+// skipBOM убирает метку порядка байтов UTF-8, с которой файл начинают Excel и «Блокнот».
+func skipBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	if prefix, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+	return br
+}
+
+var utf8BOM = []byte("\ufeff")
+
+type jsonlSource struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (s *jsonlSource) Next() (model.ImportRecord, error) {
+	for s.scanner.Scan() {
+		s.line++
+		data := bytes.TrimSpace(s.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		record := model.ImportRecord{Line: s.line}
+		var row ImportRow
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&row); err != nil {
+			record.Err = fmt.Errorf("invalid JSON: %w", err)
+			return record, nil
+		}
+		if dec.More() {
+			record.Err = errors.New("invalid JSON: more than one object on the line")
+			return record, nil
+		}
+		record.Task, record.Err = row.toTask()
+		return record, nil
+	}
+	if err := s.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return model.ImportRecord{}, fmt.Errorf("%w: line %d is longer than %d bytes", model.ErrInvalidImport, s.line+1, maxImportLine)
+		}
+		return model.ImportRecord{}, err
+	}
+	return model.ImportRecord{}, io.EOF
+}
+
+type csvSource struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+func newCSVSource(r io.Reader) (*csvSource, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: CSV header is missing", model.ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", model.ErrInvalidImport, err)
+	}
+
+	s := &csvSource{reader: reader, columns: make([]string, len(header))}
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !csvColumns[name] {
+			return nil, fmt.Errorf("%w: unknown CSV column %q", model.ErrInvalidImport, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate CSV column %q", model.ErrInvalidImport, name)
+		}
+		seen[name] = true
+		s.columns[i] = name
+	}
+	if !seen["title"] {
+		return nil, fmt.Errorf("%w: CSV has no title column", model.ErrInvalidImport)
+	}
+	return s, nil
+}
+
+var csvColumns = map[string]bool{
+	"title": true, "description": true, "status": true, "priority": true, "tags": true,
+	"start_at": true, "due_at": true, "timezone": true,
+	"created_at": true, "started_at": true, "completed_at": true,
+}
+
+func (s *csvSource) Next() (model.ImportRecord, error) {
+	fields, err := s.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return model.ImportRecord{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return model.ImportRecord{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return model.ImportRecord{}, err
+	}
+
+	line, _ := s.reader.FieldPos(0)
+	record := model.ImportRecord{Line: line}
+	var row ImportRow
+	for i, value := range fields {
+		switch s.columns[i] {
+		case "title":
+			row.Title = value
+		case "description":
+			row.Description = value
+		case "status":
+			row.Status = value
+		case "priority":
+			if value = strings.TrimSpace(value); value != "" {
+				if row.Priority, err = strconv.Atoi(value); err != nil {
+					record.Err = fmt.Errorf("priority %q is not a number", value)
+					return record, nil
+				}
+			}
+		case "tags":
+			if strings.TrimSpace(value) != "" {
+				row.Tags = strings.Split(value, ",")
+			}
+		case "start_at":
+			row.StartAt = value
+		case "due_at":
+			row.DueAt = value
+		case "timezone":
+			row.Timezone = value
+		case "created_at":
+			row.CreatedAt = value
+		case "started_at":
+			row.StartedAt = value
+		case "completed_at":
+			row.CompletedAt = value
+		}
+	}
+	record.Task, record.Err = row.toTask()
+	return record, nil
+}
+
+// toTask переносит поля строки в задачу; смысловые проверки делает сервис.
+func (row ImportRow) toTask() (model.Task, error) {
+	task := model.Task{
+		Title:       row.Title,
+		Description: row.Description,
+		Status:      model.TaskStatus(strings.TrimSpace(row.Status)),
+		Priority:    row.Priority,
+		Tags:        row.Tags,
+		Timezone:    strings.TrimSpace(row.Timezone),
+	}
+	dates := []struct {
+		name string
+		raw  string
+		dst  **time.Time
+	}{
+		{"start_at", row.StartAt, &task.StartAt},
+		{"due_at", row.DueAt, &task.DueAt},
+		{"started_at", row.StartedAt, &task.StartedAt},
+		{"completed_at", row.CompletedAt, &task.CompletedAt},
+	}
+	for _, d := range dates {
+		if strings.TrimSpace(d.raw) == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(d.raw))
+		if err != nil {
+			return model.Task{}, fmt.Errorf("%s must be RFC 3339", d.name)
+		}
+		*d.dst = &t
+	}
+	if raw := strings.TrimSpace(row.CreatedAt); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return model.Task{}, errors.New("created_at must be RFC 3339")
+		}
+		task.CreatedAt = t
+	}
+	return task, nil
+}
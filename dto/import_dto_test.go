@@ -0,0 +1,188 @@
+package dto
+
+import (
+	"errors"
+	"io"
+	"myApi/model"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// readAll читает источник до конца и возвращает его строки.
+func readAll(t *testing.T, input string, format model.ImportFormat) []model.ImportRecord {
+	t.Helper()
+	source, err := NewImportSource(strings.NewReader(input), format)
+	if err != nil {
+		t.Fatalf("NewImportSource() = %v", err)
+	}
+	var records []model.ImportRecord
+	for {
+		record, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Next() = %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestCSVColumnsInAnyOrder(t *testing.T) {
+	input := "priority,Tags, title,due_at\n" +
+		"2,\"work,urgent\",Write report,2025-03-01T10:00:00Z\n" +
+		",,Call back,\n"
+	records := readAll(t, input, model.ImportCSV)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	first := records[0]
+	if first.Err != nil || first.Line != 2 {
+		t.Fatalf("first record = %+v", first)
+	}
+	if first.Task.Title != "Write report" || first.Task.Priority != 2 || !slices.Equal(first.Task.Tags, []string{"work", "urgent"}) {
+		t.Errorf("first task = %+v", first.Task)
+	}
+	if first.Task.DueAt == nil || first.Task.DueAt.Format("2006-01-02") != "2025-03-01" {
+		t.Errorf("first due_at = %v", first.Task.DueAt)
+	}
+	if second := records[1]; second.Err != nil || second.Task.Title != "Call back" || second.Task.Priority != 0 || second.Task.Tags != nil {
+		t.Errorf("second record = %+v", second)
+	}
+}
+
+func TestCSVHeaderRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unknown column", "title,owner\nWrite report,alice\n"},
+		{"duplicate column", "title,Title\na,b\n"},
+		{"no title column", "description,priority\nsomething,1\n"},
+		{"empty file", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewImportSource(strings.NewReader(tt.input), model.ImportCSV); !errors.Is(err, model.ErrInvalidImport) {
+				t.Errorf("NewImportSource() = %v, want ErrInvalidImport", err)
+			}
+		})
+	}
+}
+
+func TestCSVQuotedNewline(t *testing.T) {
+	input := "title,description\n" +
+		"First,\"line one\nline two\"\n" +
+		"Second,plain\n"
+	records := readAll(t, input, model.ImportCSV)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if got := records[0].Task.Description; got != "line one\nline two" {
+		t.Errorf("description = %q", got)
+	}
+	// Номер строки — строка файла, где запись начинается.
+	if records[0].Line != 2 || records[1].Line != 4 {
+		t.Errorf("lines = %d, %d, want 2, 4", records[0].Line, records[1].Line)
+	}
+}
+
+func TestCSVBadRowKeepsReading(t *testing.T) {
+	input := "title,priority\n" +
+		"First,high\n" +
+		"Second,1,extra\n" +
+		"Third,2\n"
+	records := readAll(t, input, model.ImportCSV)
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	for i, wantErr := range []bool{true, true, false} {
+		if (records[i].Err != nil) != wantErr || records[i].Line != i+2 {
+			t.Errorf("record %d = line %d err %v", i, records[i].Line, records[i].Err)
+		}
+	}
+}
+
+func TestJSONLMalformedLine(t *testing.T) {
+	input := `{"title": "First"}` + "\n" +
+		"\n" +
+		`{"title": "Second"` + "\n" +
+		`{"title": "Third", "owner": "alice"}` + "\n" +
+		`{"title": "Fourth"} {"title": "Fifth"}` + "\n" +
+		`{"title": "Sixth", "due_at": "tomorrow"}` + "\n" +
+		`{"title": "Seventh", "tags": ["a"]}`
+	records := readAll(t, input, model.ImportJSONL)
+
+	tests := []struct {
+		line    int
+		title   string
+		wantErr string
+	}{
+		{line: 1, title: "First"},
+		{line: 3, wantErr: "invalid JSON"},
+		{line: 4, wantErr: "unknown field"},
+		{line: 5, wantErr: "more than one object"},
+		{line: 6, wantErr: "due_at must be RFC 3339"},
+		{line: 7, title: "Seventh"},
+	}
+	if len(records) != len(tests) {
+		t.Fatalf("got %d records, want %d", len(records), len(tests))
+	}
+	for i, tt := range tests {
+		r := records[i]
+		if r.Line != tt.line {
+			t.Errorf("record %d line = %d, want %d", i, r.Line, tt.line)
+		}
+		if tt.wantErr == "" {
+			if r.Err != nil || r.Task.Title != tt.title {
+				t.Errorf("line %d = %+v, want task %q", r.Line, r, tt.title)
+			}
+			continue
+		}
+		if r.Err == nil || !strings.Contains(r.Err.Error(), tt.wantErr) {
+			t.Errorf("line %d error = %v, want %q", r.Line, r.Err, tt.wantErr)
+		}
+	}
+}
+
+func TestJSONLLineTooLong(t *testing.T) {
+	input := `{"title": "ok"}` + "\n" + `{"title": "` + strings.Repeat("a", maxImportLine) + `"}` + "\n"
+	source, err := NewImportSource(strings.NewReader(input), model.ImportJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Next(); err != nil {
+		t.Fatalf("first Next() = %v", err)
+	}
+	if _, err := source.Next(); !errors.Is(err, model.ErrInvalidImport) || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Next() = %v, want ErrInvalidImport for line 2", err)
+	}
+}
+
+func TestImportSkipsBOM(t *testing.T) {
+	tests := []struct {
+		name   string
+		format model.ImportFormat
+		input  string
+	}{
+		{"jsonl", model.ImportJSONL, "\ufeff{\"title\": \"First\"}\n"},
+		{"csv", model.ImportCSV, "\ufefftitle,priority\nFirst,1\n"},
+		{"csv with quoted header", model.ImportCSV, "\ufeff\"title\",\"priority\"\nFirst,1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := readAll(t, tt.input, tt.format)
+			if len(records) != 1 || records[0].Err != nil || records[0].Task.Title != "First" {
+				t.Errorf("records = %+v", records)
+			}
+		})
+	}
+}
+
+func TestImportKeepsBOMInsideTheFile(t *testing.T) {
+	records := readAll(t, "title\nFirst\n\ufeffSecond\n", model.ImportCSV)
+	if len(records) != 2 || records[1].Task.Title != "\ufeffSecond" {
+		t.Errorf("records = %+v", records)
+	}
+}
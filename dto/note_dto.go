@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"myApi/model"
+	"time"
+)
+
+type NoteResponse struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	TaskID    *int      `json:"task_id,omitempty"`
+	CreatedBy *int      `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToNoteResponse(note *model.Note) NoteResponse {
+	return NoteResponse{
+		ID:        note.ID,
+		Title:     note.Title,
+		Body:      note.Body,
+		TaskID:    note.TaskID,
+		CreatedBy: note.CreatedBy,
+		CreatedAt: note.CreatedAt,
+		UpdatedAt: note.UpdatedAt,
+	}
+}
+
+type NoteListResponse struct {
+	List   []NoteResponse `json:"list"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+func ToNoteListResponse(page model.NotePage, filter model.NoteFilter) NoteListResponse {
+	list := make([]NoteResponse, len(page.Notes))
+	for i := range page.Notes {
+		list[i] = ToNoteResponse(&page.Notes[i])
+	}
+	return NoteListResponse{
+		List:   list,
+		Total:  page.Total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+}
+
+// NoteRequest — тело создания и замены заметки. Body — текст в markdown.
+type NoteRequest struct {
+	Title  string `json:"title" binding:"required,max=200"`
+	Body   string `json:"body,omitempty"`
+	TaskID *int   `json:"task_id,omitempty" binding:"omitempty,min=1"`
+}
+
+func ToNoteModel(id int, req NoteRequest) *model.Note {
+	return &model.Note{
+		ID:     id,
+		Title:  req.Title,
+		Body:   req.Body,
+		TaskID: req.TaskID,
+	}
+}
+
+// NoteListQuery — query-параметры /notes/list.
+type NoteListQuery struct {
+	TaskID *int `form:"task_id" binding:"omitempty,min=1"`
+	Limit  int  `form:"limit" binding:"omitempty,min=1"`
+	Offset int  `form:"offset" binding:"omitempty,min=0"`
+}
+
+func ToNoteFilter(q NoteListQuery) model.NoteFilter {
+	limit := q.Limit
+	if limit == 0 {
+		limit = model.DefaultPageSize
+	}
+	return model.NoteFilter{
+		TaskID: q.TaskID,
+		Limit:  min(limit, model.MaxPageSize),
+		Offset: q.Offset,
+	}
+}
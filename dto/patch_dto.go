@@ -0,0 +1,140 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"myApi/model"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// ParseMergePatch разбирает JSON Merge Patch (RFC 7396) для задачи.
+// null в description очищает описание, в priority — возвращает приоритет по умолчанию.
+func ParseMergePatch(body []byte) (model.TaskPatch, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil || doc == nil {
+		return model.TaskPatch{}, fmt.Errorf("%w: merge patch must be a JSON object", model.ErrInvalidPatch)
+	}
+
+	var patch model.TaskPatch
+	for key, raw := range doc {
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+		if err := setPatchField(&patch.Set, "/"+key, raw, isNull); err != nil {
+			return model.TaskPatch{}, err
+		}
+	}
+	return patch, nil
+}
+
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ParseJSONPatch разбирает JSON Patch (RFC 6902). Поддерживаются add, replace, remove и test;
+// move и copy для плоского документа задачи не поддерживаются.
+func ParseJSONPatch(body []byte) (model.TaskPatch, error) {
+	var ops []jsonPatchOperation
+	if err := json.Unmarshal(body, &ops); err != nil {
+		return model.TaskPatch{}, fmt.Errorf("%w: json patch must be an array of operations", model.ErrInvalidPatch)
+	}
+
+	var patch model.TaskPatch
+	for i, op := range ops {
+		var err error
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				return model.TaskPatch{}, fmt.Errorf("%w: operation %d: value is required", model.ErrInvalidPatch, i)
+			}
+			err = setPatchField(&patch.Set, op.Path, op.Value, false)
+		case "remove":
+			err = setPatchField(&patch.Set, op.Path, nil, true)
+		case "test":
+			err = addPatchTest(&patch, op)
+		case "move", "copy":
+			err = fmt.Errorf("%w: operation %q is not supported", model.ErrInvalidPatch, op.Op)
+		default:
+			err = fmt.Errorf("%w: unknown operation %q", model.ErrInvalidPatch, op.Op)
+		}
+		if err != nil {
+			return model.TaskPatch{}, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return patch, nil
+}
+
+func setPatchField(f *model.TaskFields, path string, raw json.RawMessage, remove bool) error {
+	switch path {
+	case "/title":
+		if remove {
+			return fmt.Errorf("%w: title cannot be removed", model.ErrInvalidPatch)
+		}
+		return decodePatchValue(path, raw, &f.Title)
+	case "/description":
+		if remove {
+			f.Description = new(string)
+			return nil
+		}
+		return decodePatchValue(path, raw, &f.Description)
+	case "/priority":
+		if remove {
+			f.Priority = new(int)
+			return nil
+		}
+		return decodePatchValue(path, raw, &f.Priority)
+	case "/status":
+		return fmt.Errorf("%w: status is changed via /start, /complete, /stop and /reopen", model.ErrInvalidPatch)
+	}
+	return fmt.Errorf("%w: path %q cannot be patched", model.ErrInvalidPatch, path)
+}
+
+// addPatchTest превращает test в условие записи. Если поле уже изменено этим же патчем,
+// проверка выполняется сразу — по RFC 6902 операции применяются последовательно.
+func addPatchTest(patch *model.TaskPatch, op jsonPatchOperation) error {
+	var expected model.TaskFields
+	if err := setPatchField(&expected, op.Path, op.Value, false); err != nil {
+		return err
+	}
+
+	switch {
+	case expected.Title != nil:
+		if patch.Set.Title != nil {
+			return checkPatchTest(*patch.Set.Title == *expected.Title, op.Path)
+		}
+		patch.Test.Title = expected.Title
+	case expected.Description != nil:
+		if patch.Set.Description != nil {
+			return checkPatchTest(*patch.Set.Description == *expected.Description, op.Path)
+		}
+		patch.Test.Description = expected.Description
+	case expected.Priority != nil:
+		if patch.Set.Priority != nil {
+			return checkPatchTest(*patch.Set.Priority == *expected.Priority, op.Path)
+		}
+		patch.Test.Priority = expected.Priority
+	}
+	return nil
+}
+
+func checkPatchTest(ok bool, path string) error {
+	if !ok {
+		return fmt.Errorf("%w: %s", model.ErrPatchTestFailed, path)
+	}
+	return nil
+}
+
+func decodePatchValue[T any](path string, raw json.RawMessage, dst **T) error {
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return fmt.Errorf("%w: invalid value for %s", model.ErrInvalidPatch, path)
+	}
+	*dst = &v
+	return nil
+}
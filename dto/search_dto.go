@@ -0,0 +1,55 @@
+package dto
+
+import "myApi/model"
+
+// SearchQuery — query-параметры /task/search. Без fuzzy поиск с опечатками включён.
+type SearchQuery struct {
+	Q      string `form:"q" binding:"required"`
+	Fuzzy  *bool  `form:"fuzzy"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+func ToTaskSearch(q SearchQuery) model.TaskSearch {
+	return model.TaskSearch{
+		Query:  q.Q,
+		Fuzzy:  q.Fuzzy == nil || *q.Fuzzy,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+}
+
+// SearchHitResponse — найденная задача. title_highlight и snippet — HTML: текст задачи
+// экранирован, совпадения обёрнуты в <mark>.
+type SearchHitResponse struct {
+	Task           TaskResponse `json:"task"`
+	Rank           float64      `json:"rank"`
+	TitleHighlight string       `json:"title_highlight"`
+	Snippet        string       `json:"snippet"`
+}
+
+type SearchResponse struct {
+	List   []SearchHitResponse `json:"list"`
+	Total  int                 `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+func ToSearchResponse(page model.SearchPage) SearchResponse {
+	list := make([]SearchHitResponse, len(page.Hits))
+	for i := range page.Hits {
+		hit := &page.Hits[i]
+		list[i] = SearchHitResponse{
+			Task:           ToTaskResponse(&hit.Task),
+			Rank:           hit.Rank,
+			TitleHighlight: model.RenderHighlight(hit.Title),
+			Snippet:        model.RenderHighlight(hit.Snippet),
+		}
+	}
+	return SearchResponse{
+		List:   list,
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+}
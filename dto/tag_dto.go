@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"myApi/model"
+	"time"
+)
+
+type TagResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	TaskCount int       `json:"task_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToTagResponse(tag *model.Tag) TagResponse {
+	return TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		TaskCount: tag.TaskCount,
+		CreatedAt: tag.CreatedAt,
+	}
+}
+
+func ToTagResponses(tags []model.Tag) []TagResponse {
+	resp := make([]TagResponse, len(tags))
+	for i := range tags {
+		resp[i] = ToTagResponse(&tags[i])
+	}
+	return resp
+}
+
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+type MergeTagRequest struct {
+	Into int `json:"into" binding:"required,min=1"`
+}
+
+// TaskTagsRequest — имена тегов, которые ставятся задаче; недостающие теги создаются.
+type TaskTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20"`
+}
+
+// tagNames возвращает пустой список вместо nil, чтобы в JSON всегда был массив.
+func tagNames(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
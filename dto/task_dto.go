@@ -1,49 +1,368 @@
 package dto
 
 import (
+	"fmt"
 	"myApi/model"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type TaskResponse struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	Priority    int       `json:"priority"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Priority    int        `json:"priority"`
+	Version     int        `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	CreatedBy   *int       `json:"created_by,omitempty"`
+	AssigneeID  *int       `json:"assignee_id,omitempty"`
+	WorkspaceID int        `json:"workspace_id"`
+	Tags        []string   `json:"tags"`
+	ParentID    *int       `json:"parent_id,omitempty"`
+	BlockedBy   []int      `json:"blocked_by,omitempty"`
+	// StartAt и DueAt показываются в зоне задачи.
+	StartAt  *time.Time `json:"start_at,omitempty"`
+	DueAt    *time.Time `json:"due_at,omitempty"`
+	Timezone string     `json:"timezone,omitempty"`
+	Overdue  bool       `json:"overdue,omitempty"`
+	// Recurrence — RRULE повторяющейся задачи; RecurredFrom — предыдущий экземпляр серии.
+	Recurrence   string `json:"recurrence,omitempty"`
+	RecurredFrom *int   `json:"recurred_from,omitempty"`
+	// OpenBlockers — незавершённые блокеры; пока список не пуст, задачу нельзя начать или завершить.
+	OpenBlockers []int `json:"open_blockers,omitempty"`
+	// Subtasks есть только у задач с подзадачами.
+	Subtasks *SubtaskProgressResponse `json:"subtasks,omitempty"`
+}
+
+// SubtaskProgressResponse — прогресс по подзадачам всех уровней.
+type SubtaskProgressResponse struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Percent   int `json:"percent"`
 }
 
 func ToTaskResponse(task *model.Task) TaskResponse {
+	loc := task.Location()
 	return TaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      string(task.Status),
-		Priority:    task.Priority,
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
+		ID:           task.ID,
+		Title:        task.Title,
+		Description:  task.Description,
+		Status:       string(task.Status),
+		Priority:     task.Priority,
+		Version:      task.Version,
+		CreatedAt:    task.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
+		StartedAt:    task.StartedAt,
+		CompletedAt:  task.CompletedAt,
+		DeletedAt:    task.DeletedAt,
+		CreatedBy:    task.CreatedBy,
+		AssigneeID:   task.AssigneeID,
+		WorkspaceID:  task.WorkspaceID,
+		Tags:         tagNames(task.Tags),
+		ParentID:     task.ParentID,
+		BlockedBy:    task.BlockedBy,
+		StartAt:      inLocation(task.StartAt, loc),
+		DueAt:        inLocation(task.DueAt, loc),
+		Timezone:     task.Timezone,
+		Overdue:      task.Overdue(time.Now()),
+		Recurrence:   task.Recurrence,
+		RecurredFrom: task.RecurredFrom,
+		OpenBlockers: task.OpenBlockers,
+		Subtasks:     toSubtaskProgress(task.Subtasks),
+	}
+}
+
+func inLocation(t *time.Time, loc *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local := t.In(loc)
+	return &local
+}
+
+// OccurrencesQuery — query-параметры предпросмотра повторений.
+type OccurrencesQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// OccurrencesResponse — ближайшие сроки повторяющейся задачи в её зоне.
+type OccurrencesResponse struct {
+	Occurrences []time.Time `json:"occurrences"`
+}
+
+func toSubtaskProgress(p model.SubtaskProgress) *SubtaskProgressResponse {
+	if p.Total == 0 {
+		return nil
+	}
+	return &SubtaskProgressResponse{Total: p.Total, Completed: p.Completed, Percent: p.Percent()}
+}
+
+// TaskTreeResponse — задача с вложенными подзадачами.
+type TaskTreeResponse struct {
+	TaskResponse
+	Children []TaskTreeResponse `json:"children"`
+}
+
+func ToTaskTreeResponse(node *model.TaskNode) TaskTreeResponse {
+	resp := TaskTreeResponse{
+		TaskResponse: ToTaskResponse(&node.Task),
+		Children:     make([]TaskTreeResponse, 0, len(node.Children)),
 	}
+	for _, child := range node.Children {
+		resp.Children = append(resp.Children, ToTaskTreeResponse(child))
+	}
+	return resp
 }
 
 type CreateTaskRequest struct {
 	Title       string `json:"title" binding:"required,min=2,max=200"`
 	Description string `json:"description,omitempty"`
 	Priority    int    `json:"priority,omitempty"`
+	AssigneeID  *int   `json:"assignee_id,omitempty"`
+	ParentID    *int   `json:"parent_id,omitempty"`
+	ScheduleRequest
+}
+
+// ScheduleRequest — плановые даты задачи. Даты принимаются в RFC 3339, без смещения
+// («2026-03-01T18:00») — в зоне timezone, а дата без времени означает начало дня для
+// start_at и конец дня для due_at. Пустое значение снимает дату.
+// recurrence — RRULE (например, «FREQ=WEEKLY;BYDAY=MO»), повторяющий due_at; завершение
+// экземпляра создаёт следующий.
+type ScheduleRequest struct {
+	StartAt    string `json:"start_at,omitempty"`
+	DueAt      string `json:"due_at,omitempty"`
+	Timezone   string `json:"timezone,omitempty"`
+	Recurrence string `json:"recurrence,omitempty"`
+}
+
+func ToTaskSchedule(req ScheduleRequest) (model.TaskSchedule, error) {
+	loc, err := model.LoadTimezone(req.Timezone)
+	if err != nil {
+		return model.TaskSchedule{}, err
+	}
+	schedule := model.TaskSchedule{Timezone: req.Timezone, Recurrence: req.Recurrence}
+	for _, d := range []struct {
+		raw      string
+		endOfDay bool
+		dst      **time.Time
+	}{
+		{req.StartAt, false, &schedule.StartAt},
+		{req.DueAt, true, &schedule.DueAt},
+	} {
+		if d.raw == "" {
+			continue
+		}
+		t, err := model.ParseScheduleTime(d.raw, loc, d.endOfDay)
+		if err != nil {
+			return model.TaskSchedule{}, err
+		}
+		*d.dst = &t
+	}
+	return schedule, nil
+}
+
+type AssignTaskRequest struct {
+	AssigneeID int `json:"assignee_id" binding:"required,min=1"`
+}
+
+type MoveTaskRequest struct {
+	ParentID int `json:"parent_id" binding:"required,min=1"`
 }
 type UpdateTaskRequest struct {
-	ID          int    `json:"id" binding:"required"`
 	Title       string `json:"title" binding:"required,max=200"`
 	Description string `json:"description,omitempty"`
 	Priority    int    `json:"priority,omitempty"`
 }
 
-func ToTaskModel(req CreateTaskRequest) *model.Task {
+func ToTaskModel(req CreateTaskRequest) (*model.Task, error) {
+	schedule, err := ToTaskSchedule(req.ScheduleRequest)
+	if err != nil {
+		return nil, err
+	}
 	return &model.Task{
 		Title:       req.Title,
 		Description: req.Description,
 		Priority:    req.Priority,
 		Status:      model.StatusPending,
+		AssigneeID:  req.AssigneeID,
+		ParentID:    req.ParentID,
+		StartAt:     schedule.StartAt,
+		DueAt:       schedule.DueAt,
+		Timezone:    schedule.Timezone,
+		Recurrence:  schedule.Recurrence,
+	}, nil
+}
+
+func UpdateToTaskModel(id int, req UpdateTaskRequest) *model.Task {
+	return &model.Task{
+		ID:          id,
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    req.Priority,
+	}
+}
+
+// TaskListQuery — query-параметры /task/list. Даты в RFC 3339, status через запятую,
+// assignee — ID пользователя, «me» или «none», created_by — ID или «me»; tags через запятую,
+// tag_match — any (по умолчанию) или all; overdue=true — только просроченные.
+type TaskListQuery struct {
+	Status      string `form:"status"`
+	PriorityMin *int   `form:"priority_min"`
+	PriorityMax *int   `form:"priority_max"`
+	CreatedFrom string `form:"created_from"`
+	CreatedTo   string `form:"created_to"`
+	UpdatedFrom string `form:"updated_from"`
+	UpdatedTo   string `form:"updated_to"`
+	Assignee    string `form:"assignee"`
+	CreatedBy   string `form:"created_by"`
+	Tags        string `form:"tags"`
+	TagMatch    string `form:"tag_match" binding:"omitempty,oneof=any all"`
+	Overdue     bool   `form:"overdue"`
+	DueFrom     string `form:"due_from"`
+	DueTo       string `form:"due_to"`
+	Sort        string `form:"sort"`
+	Order       string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1"`
+	Offset      int    `form:"offset" binding:"omitempty,min=0"`
+	Cursor      string `form:"cursor"`
+}
+
+// ToTaskFilter переводит query в фильтр; currentUserID подставляется вместо «me».
+// Значения по умолчанию и согласованность полей проверяет сервис.
+func ToTaskFilter(q TaskListQuery, currentUserID int) (model.TaskFilter, error) {
+	f := model.TaskFilter{
+		PriorityMin: q.PriorityMin,
+		PriorityMax: q.PriorityMax,
+		SortBy:      model.TaskSortField(q.Sort),
+		TagMatch:    model.TagMatch(q.TagMatch),
+		Overdue:     q.Overdue,
+		Limit:       q.Limit,
+		Offset:      q.Offset,
+	}
+	if q.Sort != "" {
+		f.SortDesc = q.Order == "desc"
+	} else if q.Order != "" {
+		f.SortBy = model.SortByCreatedAt
+		f.SortDesc = q.Order == "desc"
+	}
+
+	if q.Status != "" {
+		for _, st := range strings.Split(q.Status, ",") {
+			status := model.TaskStatus(strings.TrimSpace(st))
+			if !status.Valid() {
+				return model.TaskFilter{}, fmt.Errorf("%w: unknown status %q", model.ErrInvalidFilter, status)
+			}
+			f.Statuses = append(f.Statuses, status)
+		}
+	}
+
+	dates := []struct {
+		name string
+		raw  string
+		dst  **time.Time
+	}{
+		{"created_from", q.CreatedFrom, &f.CreatedFrom},
+		{"created_to", q.CreatedTo, &f.CreatedTo},
+		{"updated_from", q.UpdatedFrom, &f.UpdatedFrom},
+		{"updated_to", q.UpdatedTo, &f.UpdatedTo},
+		{"due_from", q.DueFrom, &f.DueFrom},
+		{"due_to", q.DueTo, &f.DueTo},
+	}
+	for _, d := range dates {
+		if d.raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, d.raw)
+		if err != nil {
+			return model.TaskFilter{}, fmt.Errorf("%w: %s must be RFC 3339", model.ErrInvalidFilter, d.name)
+		}
+		*d.dst = &t
+	}
+
+	switch q.Assignee {
+	case "":
+	case "none":
+		f.Unassigned = true
+	default:
+		id, err := parseUserRef("assignee", q.Assignee, currentUserID)
+		if err != nil {
+			return model.TaskFilter{}, err
+		}
+		f.AssigneeID = &id
+	}
+	if q.CreatedBy != "" {
+		id, err := parseUserRef("created_by", q.CreatedBy, currentUserID)
+		if err != nil {
+			return model.TaskFilter{}, err
+		}
+		f.CreatedBy = &id
+	}
+
+	if q.Tags != "" {
+		tags, err := model.NormalizeTagNames(strings.Split(q.Tags, ","))
+		if err != nil {
+			return model.TaskFilter{}, fmt.Errorf("%w: %w", model.ErrInvalidFilter, err)
+		}
+		f.Tags = tags
+	}
+
+	if q.Cursor != "" {
+		cursor, err := model.DecodeTaskCursor(q.Cursor)
+		if err != nil {
+			return model.TaskFilter{}, err
+		}
+		f.Cursor = cursor
+	}
+
+	return f, nil
+}
+
+func parseUserRef(name, raw string, currentUserID int) (int, error) {
+	if raw == "me" {
+		if currentUserID == 0 {
+			return 0, fmt.Errorf("%w: %s=me requires a user", model.ErrInvalidFilter, name)
+		}
+		return currentUserID, nil
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: %s must be a user id or \"me\"", model.ErrInvalidFilter, name)
+	}
+	return id, nil
+}
+
+func ToTaskResponses(tasks []model.Task) []TaskResponse {
+	list := make([]TaskResponse, 0, len(tasks))
+	for i := range tasks {
+		list = append(list, ToTaskResponse(&tasks[i]))
+	}
+	return list
+}
+
+type TaskListResponse struct {
+	List       []TaskResponse `json:"list"`
+	Total      int            `json:"total"`
+	Limit      int            `json:"limit"`
+	Offset     int            `json:"offset"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func ToTaskListResponse(page model.TaskPage) TaskListResponse {
+	resp := TaskListResponse{
+		List:   ToTaskResponses(page.Tasks),
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	if page.HasMore && len(page.Tasks) > 0 {
+		last := &page.Tasks[len(page.Tasks)-1]
+		resp.NextCursor = model.NewTaskCursor(page.SortBy, last).Encode()
 	}
+	return resp
 }
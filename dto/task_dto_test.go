@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"errors"
+	"myApi/model"
+	"testing"
+)
+
+func TestToTaskFilterUserRefs(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         TaskListQuery
+		currentUserID int
+		assignee      *int
+		unassigned    bool
+		createdBy     *int
+		wantErr       bool
+	}{
+		{name: "no filter", currentUserID: 7},
+		{name: "me with a principal", query: TaskListQuery{Assignee: "me"}, currentUserID: 7, assignee: ptr(7)},
+		{name: "me without a principal", query: TaskListQuery{Assignee: "me"}, wantErr: true},
+		{name: "none", query: TaskListQuery{Assignee: "none"}, currentUserID: 7, unassigned: true},
+		{name: "none without a principal", query: TaskListQuery{Assignee: "none"}, unassigned: true},
+		{name: "numeric id", query: TaskListQuery{Assignee: "12"}, currentUserID: 7, assignee: ptr(12)},
+		{name: "numeric id without a principal", query: TaskListQuery{Assignee: "12"}, assignee: ptr(12)},
+		{name: "zero id", query: TaskListQuery{Assignee: "0"}, currentUserID: 7, wantErr: true},
+		{name: "negative id", query: TaskListQuery{Assignee: "-3"}, currentUserID: 7, wantErr: true},
+		{name: "garbage", query: TaskListQuery{Assignee: "Me"}, currentUserID: 7, wantErr: true},
+		{name: "created by me", query: TaskListQuery{CreatedBy: "me"}, currentUserID: 7, createdBy: ptr(7)},
+		{name: "created by me without a principal", query: TaskListQuery{CreatedBy: "me"}, wantErr: true},
+		{name: "created by none", query: TaskListQuery{CreatedBy: "none"}, currentUserID: 7, wantErr: true},
+		{name: "created by id", query: TaskListQuery{CreatedBy: "3", Assignee: "me"}, currentUserID: 7, assignee: ptr(7), createdBy: ptr(3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ToTaskFilter(tt.query, tt.currentUserID)
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrInvalidFilter) {
+					t.Fatalf("ToTaskFilter() = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToTaskFilter() = %v", err)
+			}
+			if !equalRef(f.AssigneeID, tt.assignee) || f.Unassigned != tt.unassigned || !equalRef(f.CreatedBy, tt.createdBy) {
+				t.Errorf("assignee %v, unassigned %v, created_by %v; want %v, %v, %v",
+					deref(f.AssigneeID), f.Unassigned, deref(f.CreatedBy), deref(tt.assignee), tt.unassigned, deref(tt.createdBy))
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func equalRef(a, b *int) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+// deref печатает nil как nil, а не как адрес.
+func deref(p *int) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
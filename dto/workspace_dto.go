@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"myApi/model"
+	"time"
+)
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type AddMemberRequest struct {
+	UserID int `json:"user_id" binding:"required,min=1"`
+}
+
+type WorkspaceResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedBy *int      `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToWorkspaceResponse(ws *model.Workspace) WorkspaceResponse {
+	return WorkspaceResponse{
+		ID:        ws.ID,
+		Name:      ws.Name,
+		CreatedBy: ws.CreatedBy,
+		CreatedAt: ws.CreatedAt,
+	}
+}
+
+func ToWorkspaceResponses(workspaces []model.Workspace) []WorkspaceResponse {
+	resp := make([]WorkspaceResponse, len(workspaces))
+	for i := range workspaces {
+		resp[i] = ToWorkspaceResponse(&workspaces[i])
+	}
+	return resp
+}
+
+type WorkspaceMemberResponse struct {
+	UserID   int       `json:"user_id"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+func ToWorkspaceMemberResponses(members []model.WorkspaceMember) []WorkspaceMemberResponse {
+	resp := make([]WorkspaceMemberResponse, len(members))
+	for i, m := range members {
+		resp[i] = WorkspaceMemberResponse{UserID: m.UserID, JoinedAt: m.CreatedAt}
+	}
+	return resp
+}
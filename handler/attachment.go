@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"myApi/dto"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"myApi/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead — запас на заголовки частей и границы сверх размера самого файла.
+const multipartOverhead = 64 << 10
+
+// AttachmentService — файлы задач, см. service.AttachmentService.
+type AttachmentService interface {
+	List(ctx context.Context, taskID int) ([]model.Attachment, error)
+	Upload(ctx context.Context, taskID int, name string, content io.ReadSeeker) (*model.Attachment, error)
+	Open(ctx context.Context, taskID, id int) (*model.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, taskID, id int) error
+	MaxSize() int64
+}
+
+// TaskAttachmentsHandler godoc
+// @Summary      Get task attachments
+// @Description  Files attached to the task in upload order
+// @Tags         attachments
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.AttachmentListResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/attachments [get]
+func (h *Handler) TaskAttachmentsHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.attachments.List(c.Request.Context(), id)
+	if err != nil {
+		h.abortWithAttachmentError(c, "get attachments", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToAttachmentListResponse(attachments))
+}
+
+// UploadAttachmentHandler godoc
+// @Summary      Upload attachment
+// @Description  Attach a file to the task as multipart/form-data. The type is detected from the content and must be on the server's allow list; the response carries the SHA-256 checksum of the stored file.
+// @Tags         attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id    path      int   true  "Task ID"
+// @Param        file  formData  file  true  "File to attach"
+// @Success      201  {object}  dto.AttachmentResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      413  {object}  map[string]string
+// @Failure      415  {object}  map[string]string
+// @Router       /task/{id}/attachments [post]
+func (h *Handler) UploadAttachmentHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.attachments.MaxSize()+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.abortWithAttachmentError(c, "upload attachment", id, service.ErrAttachmentTooLarge)
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Expected a multipart form with a file field"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		h.abortWithAttachmentError(c, "upload attachment", id, err)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachments.Upload(c.Request.Context(), id, header.Filename, file)
+	if err != nil {
+		h.abortWithAttachmentError(c, "upload attachment", id, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToAttachmentResponse(attachment))
+}
+
+// DownloadAttachmentHandler godoc
+// @Summary      Download attachment
+// @Description  Content of the attached file. The ETag is the SHA-256 checksum of the content.
+// @Tags         attachments
+// @Produce      octet-stream
+// @Security     ApiKeyAuth
+// @Param        id             path  int  true  "Task ID"
+// @Param        attachment_id  path  int  true  "Attachment ID"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/attachments/{attachment_id} [get]
+func (h *Handler) DownloadAttachmentHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	id, attachmentID, ok := parseAttachmentID(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.attachments.Open(c.Request.Context(), id, attachmentID)
+	if err != nil {
+		h.abortWithAttachmentError(c, "download attachment", id, err)
+		return
+	}
+	defer content.Close()
+
+	c.Header("ETag", `"`+attachment.Checksum+`"`)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+	})
+}
+
+// DeleteAttachmentHandler godoc
+// @Summary      Delete attachment
+// @Description  Remove the file from the task and from storage
+// @Tags         attachments
+// @Security     ApiKeyAuth
+// @Param        id             path  int  true  "Task ID"
+// @Param        attachment_id  path  int  true  "Attachment ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/attachments/{attachment_id} [delete]
+func (h *Handler) DeleteAttachmentHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, attachmentID, ok := parseAttachmentID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.attachments.Delete(c.Request.Context(), id, attachmentID); err != nil {
+		h.abortWithAttachmentError(c, "delete attachment", id, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// abortWithAttachmentError переводит ошибку сервиса вложений в HTTP-ответ.
+func (h *Handler) abortWithAttachmentError(c *gin.Context, op string, id int, err error) {
+	switch {
+	case errors.Is(err, repository.ErrAttachmentNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnsupportedMediaType):
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	default:
+		h.abortWithTaskError(c, op, id, err)
+	}
+}
+
+func parseAttachmentID(c *gin.Context) (int, int, bool) {
+	id, ok := parseTaskID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	attachmentID, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil || attachmentID <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment id"})
+		return 0, 0, false
+	}
+	return id, attachmentID, true
+}
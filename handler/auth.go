@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"myApi/auth"
+	"myApi/dto"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"myApi/reqctx"
+	"myApi/service"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principalKey — ключ gin.Context, под которым middleware сохраняет пользователя.
+const principalKey = "principal"
+
+// AuthService — регистрация, выдача и проверка токенов, см. service.AuthService.
+type AuthService interface {
+	Register(ctx context.Context, email, name, password string) (*model.User, error)
+	Login(ctx context.Context, email, password string, workspaceID int) (auth.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (auth.TokenPair, error)
+	Logout(ctx context.Context, principal auth.Principal, refreshToken string) error
+	Authenticate(ctx context.Context, accessToken string) (auth.Principal, error)
+	GetUser(ctx context.Context, id int) (*model.User, error)
+	CreateAPIKey(ctx context.Context, principal auth.Principal, name string, scopes model.Scopes, workspaceID *int, expiresAt *time.Time) (*model.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, principal auth.Principal) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, principal auth.Principal, id int) (*model.APIKey, error)
+	ListUsers(ctx context.Context) ([]model.User, error)
+	SetUserRole(ctx context.Context, principal auth.Principal, id int, role model.Role) (*model.User, error)
+}
+
+// RegisterHandler godoc
+// @Summary      Register
+// @Description  Create a user account. Password must be 8-72 characters.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        user  body      dto.RegisterRequest  true  "Account data"
+// @Success      201   {object}  dto.UserResponse
+// @Failure      400   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /auth/register [post]
+func (h *Handler) RegisterHandler(c *gin.Context) {
+	var req dto.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.auth.Register(c.Request.Context(), req.Email, req.Name, req.Password)
+	if err != nil {
+		h.abortWithAuthError(c, "register user", err)
+		return
+	}
+
+	h.logger.Info("User registered", "user_id", user.ID)
+	c.JSON(http.StatusCreated, dto.ToUserResponse(user))
+}
+
+// LoginHandler godoc
+// @Summary      Log in
+// @Description  Exchange email and password for an access and refresh token pair. With workspace_id the tokens only work in that workspace.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      dto.LoginRequest  true  "Credentials"
+// @Success      200          {object}  dto.TokenResponse
+// @Failure      400          {object}  map[string]string
+// @Failure      401          {object}  map[string]string
+// @Failure      500          {object}  map[string]string
+// @Router       /auth/login [post]
+func (h *Handler) LoginHandler(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pair, err := h.auth.Login(c.Request.Context(), req.Email, req.Password, req.WorkspaceID)
+	if err != nil {
+		h.abortWithAuthError(c, "log in", err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToTokenResponse(pair, time.Now()))
+}
+
+// RefreshHandler godoc
+// @Summary      Refresh tokens
+// @Description  Exchange a refresh token for a new token pair. Each refresh token can be used once.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        token  body      dto.RefreshRequest  true  "Refresh token"
+// @Success      200    {object}  dto.TokenResponse
+// @Failure      400    {object}  map[string]string
+// @Failure      401    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /auth/refresh [post]
+func (h *Handler) RefreshHandler(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pair, err := h.auth.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		h.abortWithAuthError(c, "refresh token", err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToTokenResponse(pair, time.Now()))
+}
+
+// LogoutHandler godoc
+// @Summary      Log out
+// @Description  Revoke the current access token and, if given, the refresh token of the session
+// @Tags         auth
+// @Accept       json
+// @Security     ApiKeyAuth
+// @Param        token  body  dto.LogoutRequest  false  "Refresh token to revoke"
+// @Success      204
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /auth/logout [post]
+func (h *Handler) LogoutHandler(c *gin.Context) {
+	var req dto.LogoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	principal := c.MustGet(principalKey).(auth.Principal)
+	if err := h.auth.Logout(c.Request.Context(), principal, req.RefreshToken); err != nil {
+		h.abortWithAuthError(c, "log out", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MeHandler godoc
+// @Summary      Current user
+// @Description  Get the account the access token belongs to
+// @Tags         auth
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {object}  dto.UserResponse
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /auth/me [get]
+func (h *Handler) MeHandler(c *gin.Context) {
+	principal := c.MustGet(principalKey).(auth.Principal)
+	user, err := h.auth.GetUser(c.Request.Context(), principal.UserID)
+	if err != nil {
+		h.abortWithAuthError(c, "get user", err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToUserResponse(user))
+}
+
+// CreateAPIKeyHandler godoc
+// @Summary      Create API key
+// @Description  Issue a long-lived API key for machine clients. The key is returned only once; send it as "Bearer <key>". With workspace_id the key only works in that workspace.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        key  body      dto.CreateAPIKeyRequest  true  "Key name, scopes (tasks:read, tasks:write, admin), optional workspace and expiry"
+// @Success      201  {object}  dto.CreatedAPIKeyResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /auth/keys [post]
+func (h *Handler) CreateAPIKeyHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ManageAPIKeys) {
+		return
+	}
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal := c.MustGet(principalKey).(auth.Principal)
+	key, secret, err := h.auth.CreateAPIKey(c.Request.Context(), principal, req.Name, req.ToScopes(), req.WorkspaceID, req.ExpiresAt)
+	if err != nil {
+		h.abortWithAuthError(c, "create api key", err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.CreatedAPIKeyResponse{
+		APIKeyResponse: dto.ToAPIKeyResponse(key),
+		Key:            secret,
+	})
+}
+
+// ListAPIKeysHandler godoc
+// @Summary      List API keys
+// @Description  List API keys of the current user, including revoked and expired ones
+// @Tags         auth
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {object}  map[string][]dto.APIKeyResponse
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /auth/keys [get]
+func (h *Handler) ListAPIKeysHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ManageAPIKeys) {
+		return
+	}
+	principal := c.MustGet(principalKey).(auth.Principal)
+	keys, err := h.auth.ListAPIKeys(c.Request.Context(), principal)
+	if err != nil {
+		h.abortWithAuthError(c, "list api keys", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"list": dto.ToAPIKeyResponses(keys)})
+}
+
+// RevokeAPIKeyHandler godoc
+// @Summary      Revoke API key
+// @Description  Revoke an API key of the current user. Revoking twice is not an error.
+// @Tags         auth
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "API key ID"
+// @Success      200  {object}  dto.APIKeyResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /auth/keys/{id} [delete]
+func (h *Handler) RevokeAPIKeyHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ManageAPIKeys) {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid api key id"})
+		return
+	}
+
+	principal := c.MustGet(principalKey).(auth.Principal)
+	key, err := h.auth.RevokeAPIKey(c.Request.Context(), principal, id)
+	if err != nil {
+		h.abortWithAuthError(c, "revoke api key", err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToAPIKeyResponse(key))
+}
+
+// Authenticate пропускает только запросы с действующим access-токеном в заголовке
+// «Authorization: Bearer <token>» и кладёт пользователя в контекст запроса.
+func (h *Handler) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			abortUnauthorized(c, "Missing bearer token")
+			return
+		}
+
+		principal, err := h.auth.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			h.abortWithAuthError(c, "authenticate", err)
+			return
+		}
+
+		ctx := auth.WithPrincipal(c.Request.Context(), principal)
+		ctx = reqctx.WithActor(ctx, principal.Actor())
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// Authorizer — проверка прав на действие, см. policy.Policy.
+type Authorizer interface {
+	Authorize(principal auth.Principal, action policy.Action) error
+}
+
+// authorize сверяет права клиента с политикой; при отказе отвечает 403 и возвращает false.
+func (h *Handler) authorize(c *gin.Context, action policy.Action) bool {
+	principal, _ := auth.PrincipalFromContext(c.Request.Context())
+	if err := h.policy.Authorize(principal, action); err != nil {
+		h.logger.Warn("Access denied", "actor", principal.Actor(), "action", action, "reason", err)
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// abortWithAuthError переводит ошибку аутентификации в HTTP-ответ.
+func (h *Handler) abortWithAuthError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, auth.ErrInvalidToken):
+		abortUnauthorized(c, "Invalid or expired token")
+	case errors.Is(err, service.ErrInvalidCredentials):
+		abortUnauthorized(c, "Invalid email or password")
+	case errors.Is(err, service.ErrValidation):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, policy.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrAPIKeyNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+	case errors.Is(err, service.ErrEmailTaken):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, repository.ErrWorkspaceNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+	case errors.Is(err, repository.ErrNoWorkspace):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You are not a member of any workspace"})
+	case errors.Is(err, repository.ErrDatabaseUnavailable):
+		h.logger.Warn("Database unavailable", "op", op)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Database temporarily unavailable",
+			"message": "Please retry your request in a few moments",
+		})
+	default:
+		h.logger.Error("Failed to "+op, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + op})
+	}
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": message})
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"myApi/auth"
+	"myApi/dto"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkTasksHandler godoc
+// @Summary      Change many tasks at once
+// @Description  Apply one change (status, priority, tags to add or remove, or delete) to tasks given by ids or by a filter with the same fields as /task/list, at most 500 tasks, in one transaction. In atomic mode (the default) either every task changes or none does; in best_effort mode failed tasks are reported and the rest are changed. A status the task already has is not a change. Each result carries the task after the change or the error with the status code the single-task operation would return.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        request  body      dto.BulkTaskRequest  true  "Tasks and the change"
+// @Success      200  {object}  dto.BulkResponse  "Every task succeeded"
+// @Success      207  {object}  dto.BulkResponse  "Best effort: some tasks failed"
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      409  {object}  dto.BulkResponse  "Atomic: a task failed, nothing was changed"
+// @Failure      503  {object}  map[string]string
+// @Router       /task/bulk [post]
+func (h *Handler) BulkTasksHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	var req dto.BulkTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	principal, _ := auth.PrincipalFromContext(c.Request.Context())
+	bulk, err := dto.ToBulkRequest(req, principal.UserID)
+	if err != nil {
+		h.abortWithTaskError(c, "change tasks", 0, err)
+		return
+	}
+
+	report, err := h.tasks.Bulk(c.Request.Context(), bulk)
+	if err != nil {
+		h.abortWithTaskError(c, "change tasks", 0, err)
+		return
+	}
+
+	status := http.StatusOK
+	if report.Failed() > 0 {
+		status = http.StatusMultiStatus
+		if report.Mode == model.BulkAtomic {
+			status = http.StatusConflict
+		}
+	}
+	c.JSON(status, dto.ToBulkResponse(report, describeBulkError))
+}
+
+// describeBulkError — код и сообщение для задачи, которую не удалось изменить, как у
+// abortWithTaskError для одиночной операции.
+func describeBulkError(err error) (int, string) {
+	switch {
+	case errors.Is(err, repository.ErrTaskNotFound):
+		return http.StatusNotFound, "Task not found"
+	case errors.Is(err, model.ErrVersionMismatch):
+		return http.StatusPreconditionFailed, "Task was modified, reload it and retry"
+	case errors.Is(err, model.ErrBulkAborted), errors.Is(err, model.ErrInvalidTransition):
+		return http.StatusConflict, err.Error()
+	}
+	return http.StatusInternalServerError, err.Error()
+}
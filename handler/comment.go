@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"myApi/auth"
+	"myApi/dto"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CommentService — обсуждение задач, см. service.CommentService.
+type CommentService interface {
+	Threads(ctx context.Context, taskID int) ([]*model.CommentNode, int, error)
+	Create(ctx context.Context, comment model.Comment) (*model.Comment, error)
+	Edit(ctx context.Context, taskID, id int, body string) (*model.Comment, error)
+	Delete(ctx context.Context, taskID, id int, moderate bool) error
+	Edits(ctx context.Context, taskID, id int) ([]model.CommentEdit, error)
+}
+
+// TaskCommentsHandler godoc
+// @Summary      Get task comments
+// @Description  Comments of the task grouped into threads; threads and replies are ordered by creation time. Deleted comments stay as placeholders without a body so their replies keep their place.
+// @Tags         comments
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.CommentListResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/comments [get]
+func (h *Handler) TaskCommentsHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	threads, total, err := h.comments.Threads(c.Request.Context(), id)
+	if err != nil {
+		h.abortWithCommentError(c, "get comments", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToCommentListResponse(threads, total))
+}
+
+// CreateCommentHandler godoc
+// @Summary      Add comment
+// @Description  Add a comment to the task on behalf of the caller; reply_to makes it a reply to another comment of the same task
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id       path      int                 true  "Task ID"
+// @Param        comment  body      dto.CommentRequest  true  "Comment"
+// @Success      201  {object}  dto.CommentResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/comments [post]
+func (h *Handler) CreateCommentHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	var req dto.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.comments.Create(c.Request.Context(), dto.ToCommentModel(id, req))
+	if err != nil {
+		h.abortWithCommentError(c, "create comment", id, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToCommentResponse(comment))
+}
+
+// EditCommentHandler godoc
+// @Summary      Edit comment
+// @Description  Replace the body of the caller's own comment; the previous body is kept in the edit history
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id          path      int                     true  "Task ID"
+// @Param        comment_id  path      int                     true  "Comment ID"
+// @Param        comment     body      dto.CommentEditRequest  true  "New body"
+// @Success      200  {object}  dto.CommentResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/comments/{comment_id} [put]
+func (h *Handler) EditCommentHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+	var req dto.CommentEditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.comments.Edit(c.Request.Context(), id, commentID, req.Body)
+	if err != nil {
+		h.abortWithCommentError(c, "edit comment", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToCommentResponse(comment))
+}
+
+// DeleteCommentHandler godoc
+// @Summary      Delete comment
+// @Description  Delete the caller's own comment; admins can delete any comment. The body and edit history are erased, replies stay.
+// @Tags         comments
+// @Security     ApiKeyAuth
+// @Param        id          path  int  true  "Task ID"
+// @Param        comment_id  path  int  true  "Comment ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/comments/{comment_id} [delete]
+func (h *Handler) DeleteCommentHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+
+	principal, _ := auth.PrincipalFromContext(c.Request.Context())
+	moderate := h.policy.Authorize(principal, policy.ModerateComments) == nil
+	if err := h.comments.Delete(c.Request.Context(), id, commentID, moderate); err != nil {
+		h.abortWithCommentError(c, "delete comment", id, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CommentHistoryHandler godoc
+// @Summary      Get comment edit history
+// @Description  Previous bodies of the comment, oldest first
+// @Tags         comments
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id          path      int  true  "Task ID"
+// @Param        comment_id  path      int  true  "Comment ID"
+// @Success      200  {object}  dto.CommentHistoryResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/comments/{comment_id}/history [get]
+func (h *Handler) CommentHistoryHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	id, commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+
+	edits, err := h.comments.Edits(c.Request.Context(), id, commentID)
+	if err != nil {
+		h.abortWithCommentError(c, "get comment history", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToCommentHistoryResponse(edits))
+}
+
+// abortWithCommentError переводит ошибку сервиса комментариев в HTTP-ответ.
+func (h *Handler) abortWithCommentError(c *gin.Context, op string, id int, err error) {
+	switch {
+	case errors.Is(err, repository.ErrCommentNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+	case errors.Is(err, policy.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		h.abortWithTaskError(c, op, id, err)
+	}
+}
+
+func parseCommentID(c *gin.Context) (int, int, bool) {
+	id, ok := parseTaskID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	commentID, err := strconv.Atoi(c.Param("comment_id"))
+	if err != nil || commentID <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid comment id"})
+		return 0, 0, false
+	}
+	return id, commentID, true
+}
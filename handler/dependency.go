@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"myApi/dto"
+	"myApi/policy"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaskDependenciesHandler godoc
+// @Summary      Get task dependencies
+// @Description  List tasks blocking this task and tasks waiting for it
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.TaskDependenciesResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/dependencies [get]
+func (h *Handler) TaskDependenciesHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	deps, err := h.tasks.Dependencies(c.Request.Context(), id)
+	if err != nil {
+		h.abortWithTaskError(c, "get task dependencies", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToTaskDependenciesResponse(deps))
+}
+
+// AddTaskDependencyHandler godoc
+// @Summary      Add blocker
+// @Description  Mark the task as blocked by another task. A blocked task cannot be started or completed until every blocker is completed. Dependencies that would form a cycle are rejected.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id          path      int                    true   "Task ID"
+// @Param        dependency  body      dto.DependencyRequest  true   "Blocking task"
+// @Param        If-Match    header    string                 false  "ETag from a previous read"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/dependencies [post]
+func (h *Handler) AddTaskDependencyHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	var req dto.DependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.tasks.AddBlocker(c.Request.Context(), id, req.BlockerID, version)
+	if err != nil {
+		h.abortWithTaskError(c, "add task dependency", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// RemoveTaskDependencyHandler godoc
+// @Summary      Remove blocker
+// @Description  Remove a blocking task from the task
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id          path      int     true   "Task ID"
+// @Param        blocker_id  path      int     true   "Blocking task ID"
+// @Param        If-Match    header    string  false  "ETag from a previous read"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/dependencies/{blocker_id} [delete]
+func (h *Handler) RemoveTaskDependencyHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	blockerID, err := strconv.Atoi(c.Param("blocker_id"))
+	if err != nil || blockerID <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid blocker id"})
+		return
+	}
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.tasks.RemoveBlocker(c.Request.Context(), id, blockerID, version)
+	if err != nil {
+		h.abortWithTaskError(c, "remove task dependency", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// TaskPlanHandler godoc
+// @Summary      Get work plan
+// @Description  Open tasks of the workspace in dependency order, grouped into levels. Level 0 ("ready") can be worked on now; each next level unblocks once the previous ones are completed.
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {object}  dto.PlanResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Router       /task/plan [get]
+func (h *Handler) TaskPlanHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+
+	steps, err := h.tasks.Plan(c.Request.Context())
+	if err != nil {
+		h.abortWithTaskError(c, "build task plan", 0, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToPlanResponse(steps))
+}
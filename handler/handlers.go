@@ -4,33 +4,92 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"myApi/auth"
 	"myApi/db"
-	"myApi/db/entity"
 	"myApi/dto"
 	"myApi/model"
-	"myApi/repository/postgresql"
+	"myApi/policy"
+	"myApi/repository"
+	"myApi/service"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5"
 )
 
-type TaskRepo interface {
-	GetAllTasks(ctx context.Context) ([]entity.TaskEntity, error)
-	CreateTask(ctx context.Context, task model.Task) (entity.TaskEntity, error)
-	UpdateTask(ctx context.Context, task dto.UpdateTaskRequest) (entity.TaskEntity, error)
-	GetTaskById(ctx context.Context, id string) (entity.TaskEntity, error)
+// TaskService — бизнес-операции над задачами, см. service.TaskService.
+type TaskService interface {
+	List(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error)
+	Search(ctx context.Context, search model.TaskSearch) (model.SearchPage, error)
+	Get(ctx context.Context, id int) (*model.Task, error)
+	Create(ctx context.Context, task model.Task) (*model.Task, error)
+	Update(ctx context.Context, task model.Task) (*model.Task, error)
+	Patch(ctx context.Context, id int, patch model.TaskPatch) (*model.Task, error)
+	Start(ctx context.Context, id, version int) (*model.Task, error)
+	Complete(ctx context.Context, id, version int) (*model.Task, error)
+	Stop(ctx context.Context, id, version int) (*model.Task, error)
+	Reopen(ctx context.Context, id, version int) (*model.Task, error)
+	Delete(ctx context.Context, id int) error
+	ListTrash(ctx context.Context) ([]model.Task, error)
+	Restore(ctx context.Context, id int) (*model.Task, error)
+	Purge(ctx context.Context, id int) error
+	History(ctx context.Context, taskID int, limit int, beforeID int64) (model.HistoryPage, error)
+	Assign(ctx context.Context, id, assigneeID, version int) (*model.Task, error)
+	Unassign(ctx context.Context, id, version int) (*model.Task, error)
+	AddTags(ctx context.Context, id int, names []string, version int) (*model.Task, error)
+	RemoveTags(ctx context.Context, id int, names []string, version int) (*model.Task, error)
+	SetParent(ctx context.Context, id, parentID, version int) (*model.Task, error)
+	RemoveParent(ctx context.Context, id, version int) (*model.Task, error)
+	Subtasks(ctx context.Context, id int) ([]model.Task, error)
+	Tree(ctx context.Context, id int) (*model.TaskNode, error)
+	AddBlocker(ctx context.Context, id, blockerID, version int) (*model.Task, error)
+	RemoveBlocker(ctx context.Context, id, blockerID, version int) (*model.Task, error)
+	Dependencies(ctx context.Context, id int) (model.TaskDependencies, error)
+	Plan(ctx context.Context) ([]model.PlanStep, error)
+	Schedule(ctx context.Context, id int, schedule model.TaskSchedule, version int) (*model.Task, error)
+	Occurrences(ctx context.Context, id, limit int) ([]time.Time, error)
+	Bulk(ctx context.Context, req model.BulkRequest) (model.BulkReport, error)
+	Import(ctx context.Context, src model.ImportSource, dryRun bool) (model.ImportReport, error)
 }
+
+// Services — зависимости Handler; у каждой подсистемы свой сервис.
+type Services struct {
+	Tasks       TaskService
+	Notes       NoteService
+	Comments    CommentService
+	Attachments AttachmentService
+	Tags        TagService
+	Auth        AuthService
+	Workspaces  WorkspaceService
+	Policy      Authorizer
+}
+
 type Handler struct {
-	taskRepo TaskRepo
-	logger   *slog.Logger
+	tasks       TaskService
+	notes       NoteService
+	comments    CommentService
+	attachments AttachmentService
+	tags        TagService
+	auth        AuthService
+	workspaces  WorkspaceService
+	policy      Authorizer
+	logger      *slog.Logger
 }
 
-func NewHandler(taskRepo TaskRepo, logger *slog.Logger) *Handler {
+func NewHandler(services Services, logger *slog.Logger) *Handler {
 	return &Handler{
-		taskRepo: taskRepo,
-		logger:   logger,
+		tasks:       services.Tasks,
+		notes:       services.Notes,
+		comments:    services.Comments,
+		attachments: services.Attachments,
+		tags:        services.Tags,
+		auth:        services.Auth,
+		workspaces:  services.Workspaces,
+		policy:      services.Policy,
+		logger:      logger,
 	}
 }
 
@@ -38,19 +97,29 @@ type HealthHandler struct {
 	dbPool *db.Pool
 }
 
+// NewHealthHandler принимает nil, если сервер работает без базы (STORAGE=memory).
 func NewHealthHandler(dbPool *db.Pool) *HealthHandler {
 	return &HealthHandler{dbPool: dbPool}
 }
 
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
+	if h.dbPool == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "ok",
+			"storage": "memory",
+		})
+		return
+	}
+
+	healthy := h.dbPool.IsHealthy()
 	status := gin.H{
 		"status": "ok",
 		"database": gin.H{
-			"connected": h.dbPool.IsHealthy(),
+			"connected": healthy,
 		},
 	}
 
-	if !h.dbPool.IsHealthy() {
+	if !healthy {
 		c.JSON(http.StatusServiceUnavailable, status)
 		return
 	}
@@ -59,36 +128,69 @@ func (h *HealthHandler) HealthCheck(c *gin.Context) {
 }
 
 // TaskListHandler godoc
-// @Summary      Get all tasks
-// @Description  Get list of all tasks
+// @Summary      Get tasks
+// @Description  Get a filtered, sorted page of tasks. Use either offset or cursor pagination.
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
 // @Security     ApiKeyAuth
-// @Success      200  {array}   dto.TaskResponse
+// @Param        status        query     string  false  "Comma-separated statuses"
+// @Param        priority_min  query     int     false  "Minimum priority"
+// @Param        priority_max  query     int     false  "Maximum priority"
+// @Param        created_from  query     string  false  "Created at or after (RFC 3339)"
+// @Param        created_to    query     string  false  "Created before (RFC 3339)"
+// @Param        updated_from  query     string  false  "Updated at or after (RFC 3339)"
+// @Param        updated_to    query     string  false  "Updated before (RFC 3339)"
+// @Param        assignee      query     string  false  "Assignee user ID, \"me\" or \"none\""
+// @Param        created_by    query     string  false  "Author user ID or \"me\""
+// @Param        tags          query     string  false  "Comma-separated tag names"
+// @Param        tag_match     query     string  false  "Match any or all of the tags"  Enums(any, all)
+// @Param        overdue       query     bool    false  "Only uncompleted tasks past their due date"
+// @Param        due_from      query     string  false  "Due at or after (RFC 3339)"
+// @Param        due_to        query     string  false  "Due before (RFC 3339)"
+// @Param        sort          query     string  false  "Sort field"  Enums(created_at, updated_at, priority, title, id)
+// @Param        order         query     string  false  "Sort direction"  Enums(asc, desc)
+// @Param        limit         query     int     false  "Page size (max 500)"
+// @Param        offset        query     int     false  "Rows to skip"
+// @Param        cursor        query     string  false  "next_cursor from the previous page"
+// @Success      200  {object}  dto.TaskListResponse
+// @Failure      400  {object}  map[string]string
 // @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
+// @Header       200  {string}  ETag  "Weak ETag of the page"
+// @Success      304
 // @Router       /task/list [get]
 func (h *Handler) TaskListHandler(c *gin.Context) {
-	tasks, err := h.taskRepo.GetAllTasks(c.Request.Context())
-
-	if errors.Is(err, postgresql.ErrDatabaseUnavailable) {
-		h.logger.Warn("Database unavailable during task list request")
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error": "Database temporarily unavailable",
-		})
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	var query dto.TaskListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	principal, _ := auth.PrincipalFromContext(c.Request.Context())
+	filter, err := dto.ToTaskFilter(query, principal.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	page, err := h.tasks.List(c.Request.Context(), filter)
 	if err != nil {
-		h.logger.Error("Failed to get tasks", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Не удалось получить список задач",
-		})
+		h.abortWithTaskError(c, "get tasks", 0, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"list": tasks})
+	resp := dto.ToTaskListResponse(page)
+	etag := dto.TaskListETag(resp)
+	c.Header("ETag", etag)
+	if dto.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 // CreateTaskHandler godoc
@@ -102,10 +204,14 @@ func (h *Handler) TaskListHandler(c *gin.Context) {
 // @Success      201   {object}  dto.TaskResponse
 // @Failure      400   {object}  map[string]string
 // @Failure      401   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
 // @Failure      503   {object}  map[string]string
 // @Failure      500   {object}  map[string]string
 // @Router       /task/create [post]
 func (h *Handler) CreateTaskHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
 	var newtask dto.CreateTaskRequest
 	if err := c.ShouldBindJSON(&newtask); err != nil {
 		h.logger.Warn("Invalid request body", "error", err)
@@ -113,92 +219,529 @@ func (h *Handler) CreateTaskHandler(c *gin.Context) {
 		return
 	}
 
-	taskModel := dto.ToTaskModel(newtask)
-	if taskModel.Priority == 0 {
-		taskModel.Priority = 3
-	}
-
-	createdTask, err := h.taskRepo.CreateTask(c.Request.Context(), *taskModel)
-
-	if errors.Is(err, postgresql.ErrDatabaseUnavailable) {
-		h.logger.Warn("Database unavailable during task creation")
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error":   "Database temporarily unavailable",
-			"message": "Please retry your request in a few moments",
-		})
+	task, err := dto.ToTaskModel(newtask)
+	if err != nil {
+		h.abortWithTaskError(c, "create task", 0, err)
 		return
 	}
 
+	createdTask, err := h.tasks.Create(c.Request.Context(), *task)
 	if err != nil {
-		h.logger.Error("Failed to create task", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task"})
+		h.abortWithTaskError(c, "create task", 0, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.ToTaskResponse(createdTask.ToModel()))
+	c.JSON(http.StatusCreated, dto.ToTaskResponse(createdTask))
 }
 
+// GetTaskByIdHandler godoc
+// @Summary      Get task by ID
+// @Description  Get a single task; tasks in the trash are not returned
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      503  {object}  map[string]string
+// @Header       200  {string}  ETag  "Task version"
+// @Success      304
+// @Router       /task/{id} [get]
 func (h *Handler) GetTaskByIdHandler(c *gin.Context) {
-	id := c.Param("id")
-	task, err := h.taskRepo.GetTaskById(c.Request.Context(), id)
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	task, err := h.tasks.Get(c.Request.Context(), id)
 	if err != nil {
-		// 1. Проверяем на "Нет соединения"
-		if errors.Is(err, postgresql.ErrDatabaseUnavailable) {
-			h.logger.Error("Database unavailable", "error", err)
-			c.AbortWithStatus(http.StatusServiceUnavailable)
-			return
-		}
-		// 2. Проверяем на "Не найдено" (pgx.ErrNoRows можно обернуть в свой ErrNotFound)
-		if errors.Is(err, pgx.ErrNoRows) {
-			c.AbortWithStatus(http.StatusNotFound)
-			return
-		}
-
-		// 3. Остальные ошибки
-		h.logger.Error("Internal server error during get task", "id", id, "error", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		h.abortWithTaskError(c, "get task", id, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, task)
-
+	etag := dto.TaskETag(task)
+	c.Header("ETag", etag)
+	if dto.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
 }
 
+// UpdateTaskHandler godoc
+// @Summary      Update task
+// @Description  Replace title, description and priority of a task
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id    path      int                    true  "Task ID"
+// @Param        task  body      dto.UpdateTaskRequest  true  "Task data"
+// @Success      200   {object}  dto.TaskResponse
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      503   {object}  map[string]string
+// @Param        If-Match  header    string  false  "ETag from a previous read"
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id} [put]
 func (h *Handler) UpdateTaskHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
 	var updateTask dto.UpdateTaskRequest
 	if err := c.ShouldBindJSON(&updateTask); err != nil {
 		h.logger.Warn("Invalid request body", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	id := c.Param("id")
-	update, err := h.taskRepo.UpdateTask(c.Request.Context(), updateTask)
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+	task := dto.UpdateToTaskModel(id, updateTask)
+	task.Version = version
+	update, err := h.tasks.Update(c.Request.Context(), *task)
 	if err != nil {
-		if errors.Is(err, postgresql.ErrDatabaseUnavailable) {
-			h.logger.Error("Database unavailable", "error", err)
-			c.AbortWithStatus(http.StatusServiceUnavailable)
-			return
-		}
-		h.logger.Error("Internal server error during get task", "id", id, "error", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		h.abortWithTaskError(c, "update task", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(update))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(update))
+}
+
+// PatchTaskHandler godoc
+// @Summary      Partially update task
+// @Description  Apply a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) to title, description and priority.
+// @Description  JSON Patch supports add, replace, remove and test; a failed test returns 409.
+// @Tags         tasks
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id     path      int     true  "Task ID"
+// @Param        patch  body      object  true  "Patch document"
+// @Success      200    {object}  dto.TaskResponse
+// @Failure      400    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      409    {object}  map[string]string
+// @Failure      415    {object}  map[string]string
+// @Param        If-Match  header    string  false  "ETag from a previous read"
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id} [patch]
+func (h *Handler) PatchTaskHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	body, err := c.GetRawData()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var patch model.TaskPatch
+	switch c.ContentType() {
+	case dto.MergePatchContentType, "application/json":
+		patch, err = dto.ParseMergePatch(body)
+	case dto.JSONPatchContentType:
+		patch, err = dto.ParseJSONPatch(body)
+	default:
+		c.Header("Accept-Patch", dto.MergePatchContentType+", "+dto.JSONPatchContentType)
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": "Unsupported patch format"})
+		return
+	}
+	if err != nil {
+		h.abortWithTaskError(c, "patch task", id, err)
+		return
+	}
+	if patch.Version, ok = h.ifMatchVersion(c, id); !ok {
 		return
 	}
-	c.JSON(http.StatusOK, update.ToModel())
 
+	task, err := h.tasks.Patch(c.Request.Context(), id, patch)
+	if err != nil {
+		h.abortWithTaskError(c, "patch task", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
 }
 
-// ListNoteHandler godoc
-// @Summary      Get all notes
-// @Description  Get list of all notes
-// @Tags         notes
-// @Accept       json
+// DeleteTaskHandler godoc
+// @Summary      Move task to trash
+// @Description  Soft-delete a task; it can be restored until it is purged
+// @Tags         tasks
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      503  {object}  map[string]string
+// @Router       /task/{id} [delete]
+func (h *Handler) DeleteTaskHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	if err := h.tasks.Delete(c.Request.Context(), id); err != nil {
+		h.abortWithTaskError(c, "delete task", id, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// TrashListHandler godoc
+// @Summary      List deleted tasks
+// @Description  Get tasks that are in the trash, most recently deleted first
+// @Tags         tasks
 // @Produce      json
-// @Security     Authorization
-// @Failure      401  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Success      200  {array}   dto.TaskResponse
+// @Failure      503  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
-// @Router       /notes/list [get]
-func (h *Handler) ListNoteHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"list": nil})
+// @Router       /task/trash [get]
+func (h *Handler) TrashListHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	tasks, err := h.tasks.ListTrash(c.Request.Context())
+	if err != nil {
+		h.abortWithTaskError(c, "list trash", 0, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"list": dto.ToTaskResponses(tasks)})
+}
+
+// RestoreTaskHandler godoc
+// @Summary      Restore task from trash
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      503  {object}  map[string]string
+// @Router       /task/{id}/restore [post]
+func (h *Handler) RestoreTaskHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	task, err := h.tasks.Restore(c.Request.Context(), id)
+	if err != nil {
+		h.abortWithTaskError(c, "restore task", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// PurgeTaskHandler godoc
+// @Summary      Permanently delete task
+// @Description  Remove a task that is already in the trash; this cannot be undone
+// @Tags         tasks
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      503  {object}  map[string]string
+// @Router       /task/{id}/purge [delete]
+func (h *Handler) PurgeTaskHandler(c *gin.Context) {
+	if !h.authorize(c, policy.PurgeTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	if err := h.tasks.Purge(c.Request.Context(), id); err != nil {
+		h.abortWithTaskError(c, "purge task", id, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// StartTaskHandler godoc
+// @Summary      Start task
+// @Description  Move a pending task to in_progress
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Param        If-Match  header    string  false  "ETag from a previous read"
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/start [post]
+func (h *Handler) StartTaskHandler(c *gin.Context) {
+	h.transitionTask(c, h.tasks.Start)
+}
+
+// CompleteTaskHandler godoc
+// @Summary      Complete task
+// @Description  Move an in_progress task to completed. Completing a recurring task creates its next occurrence.
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Param        If-Match  header    string  false  "ETag from a previous read"
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/complete [post]
+func (h *Handler) CompleteTaskHandler(c *gin.Context) {
+	h.transitionTask(c, h.tasks.Complete)
+}
+
+// StopTaskHandler godoc
+// @Summary      Stop task
+// @Description  Return an in_progress task to pending
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Param        If-Match  header    string  false  "ETag from a previous read"
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/stop [post]
+func (h *Handler) StopTaskHandler(c *gin.Context) {
+	h.transitionTask(c, h.tasks.Stop)
+}
+
+// ReopenTaskHandler godoc
+// @Summary      Reopen task
+// @Description  Move a completed task back to in_progress
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Param        If-Match  header    string  false  "ETag from a previous read"
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/reopen [post]
+func (h *Handler) ReopenTaskHandler(c *gin.Context) {
+	h.transitionTask(c, h.tasks.Reopen)
+}
+
+func (h *Handler) transitionTask(c *gin.Context, transition func(ctx context.Context, id, version int) (*model.Task, error)) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+	task, err := transition(c.Request.Context(), id, version)
+	if err != nil {
+		h.abortWithTaskError(c, "change task status", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// TaskHistoryHandler godoc
+// @Summary      Task change history
+// @Description  Audit trail of a task, newest entries first. Available for purged tasks too.
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id      path      int  true   "Task ID"
+// @Param        limit   query     int  false  "Page size"
+// @Param        cursor  query     int  false  "next_cursor from the previous page"
+// @Success      200     {object}  dto.HistoryResponse
+// @Failure      400     {object}  map[string]string
+// @Failure      503     {object}  map[string]string
+// @Router       /task/{id}/history [get]
+func (h *Handler) TaskHistoryHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	var query dto.HistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.tasks.History(c.Request.Context(), id, query.Limit, query.Cursor)
+	if err != nil {
+		h.abortWithTaskError(c, "get task history", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToHistoryResponse(page))
+}
+
+// AssignTaskHandler godoc
+// @Summary      Assign task
+// @Description  Set the user responsible for a task
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id        path      int                    true   "Task ID"
+// @Param        assignee  body      dto.AssignTaskRequest  true   "Assignee"
+// @Param        If-Match  header    string                 false  "ETag from a previous read"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/assign [post]
+func (h *Handler) AssignTaskHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	var req dto.AssignTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.tasks.Assign(c.Request.Context(), id, req.AssigneeID, version)
+	if err != nil {
+		h.abortWithTaskError(c, "assign task", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// UnassignTaskHandler godoc
+// @Summary      Unassign task
+// @Description  Remove the assignee of a task
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id        path      int     true   "Task ID"
+// @Param        If-Match  header    string  false  "ETag from a previous read"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/assign [delete]
+func (h *Handler) UnassignTaskHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.tasks.Unassign(c.Request.Context(), id, version)
+	if err != nil {
+		h.abortWithTaskError(c, "unassign task", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// abortWithTaskError переводит ошибку сервиса в HTTP-ответ.
+func (h *Handler) abortWithTaskError(c *gin.Context, op string, id int, err error) {
+	switch {
+	case errors.Is(err, repository.ErrDatabaseUnavailable):
+		h.logger.Warn("Database unavailable", "op", op)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Database temporarily unavailable",
+			"message": "Please retry your request in a few moments",
+		})
+	case errors.Is(err, service.ErrValidation), errors.Is(err, model.ErrInvalidFilter), errors.Is(err, model.ErrInvalidPatch),
+		errors.Is(err, model.ErrInvalidSchedule), errors.Is(err, model.ErrInvalidRecurrence), errors.Is(err, model.ErrInvalidSearch),
+		errors.Is(err, model.ErrInvalidBulk), errors.Is(err, model.ErrInvalidStatus):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrTaskNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	case errors.Is(err, repository.ErrNoWorkspace):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Select a workspace with the " + workspaceHeader + " header"})
+	case errors.Is(err, model.ErrVersionMismatch):
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": "Task was modified, reload it and retry"})
+	case errors.Is(err, model.ErrInvalidTransition), errors.Is(err, model.ErrPatchTestFailed),
+		errors.Is(err, model.ErrTaskCycle), errors.Is(err, model.ErrDependencyCycle):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Failed to "+op, "id", id, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + op})
+	}
+}
+
+// ifMatchVersion возвращает версию из If-Match задачи id; 0 — заголовка нет или он равен «*».
+// Слабые и нераспознанные ETag не могут совпасть ни с одной версией: если других нет,
+// сразу 412. Для списка из нескольких версий выбирается текущая, если она в списке;
+// запись затем проверяет, что задачу не успели изменить.
+func (h *Handler) ifMatchVersion(c *gin.Context, id int) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	versions := dto.ParseTaskETags(header)
+	if len(versions) == 1 {
+		return versions[0], true
+	}
+	if len(versions) > 1 {
+		task, err := h.tasks.Get(c.Request.Context(), id)
+		if err != nil {
+			h.abortWithTaskError(c, "get task", id, err)
+			return 0, false
+		}
+		if slices.Contains(versions, task.Version) {
+			return task.Version, true
+		}
+	}
+	c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": "If-Match does not match any task version"})
+	return 0, false
+}
+
+func parseTaskID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid task id"})
+		return 0, false
+	}
+	return id, true
 }
 
 func (h *Handler) HealthHandler(c *gin.Context) {
@@ -209,30 +752,108 @@ func (h *Handler) HealthHandler(c *gin.Context) {
 }
 
 func (h *Handler) SetupRoutes(router *gin.Engine) {
+	// Регистрация и выдача токенов доступны без авторизации
+	public := router.Group("/api/auth")
+	{
+		public.POST("/register", h.RegisterHandler)
+		public.POST("/login", h.LoginHandler)
+		public.POST("/refresh", h.RefreshHandler)
+	}
+
 	api := router.Group("/api")
 	{
-		api.Use(authMiddlewareGroup("123"))
+		api.Use(h.Authenticate())
 		api.GET("/health", h.HealthHandler)
 
-		tasks := api.Group("/task")
+		account := api.Group("/auth")
+		{
+			account.POST("/logout", h.LogoutHandler)
+			account.GET("/me", h.MeHandler)
+
+			keys := account.Group("/keys")
+			{
+				keys.POST("", h.CreateAPIKeyHandler)
+				keys.GET("", h.ListAPIKeysHandler)
+				keys.DELETE("/:id", h.RevokeAPIKeyHandler)
+			}
+		}
+
+		users := api.Group("/users")
+		{
+			users.GET("", h.ListUsersHandler)
+			users.PUT("/:id/role", h.SetUserRoleHandler)
+		}
+
+		workspaces := api.Group("/workspaces")
+		{
+			workspaces.GET("", h.ListWorkspacesHandler)
+			workspaces.POST("", h.CreateWorkspaceHandler)
+			workspaces.GET("/:id/members", h.ListWorkspaceMembersHandler)
+			workspaces.POST("/:id/members", h.AddWorkspaceMemberHandler)
+			workspaces.DELETE("/:id/members/:user_id", h.RemoveWorkspaceMemberHandler)
+		}
+
+		// Задачи всегда читаются и пишутся в рамках одного рабочего пространства
+		tasks := api.Group("/task", h.ResolveWorkspace())
 		{
 			tasks.GET("/list", h.TaskListHandler)
 			tasks.POST("/create", h.CreateTaskHandler)
+			tasks.PUT("/:id", h.UpdateTaskHandler)
+			tasks.PATCH("/:id", h.PatchTaskHandler)
+			tasks.GET("/trash", h.TrashListHandler)
+			tasks.GET("/plan", h.TaskPlanHandler)
+			tasks.GET("/search", h.SearchTasksHandler)
+			tasks.POST("/bulk", h.BulkTasksHandler)
+			tasks.POST("/import", h.ImportTasksHandler)
+			tasks.GET("/:id", h.GetTaskByIdHandler)
+			tasks.DELETE("/:id", h.DeleteTaskHandler)
+			tasks.POST("/:id/restore", h.RestoreTaskHandler)
+			tasks.GET("/:id/history", h.TaskHistoryHandler)
+			tasks.POST("/:id/start", h.StartTaskHandler)
+			tasks.POST("/:id/complete", h.CompleteTaskHandler)
+			tasks.POST("/:id/stop", h.StopTaskHandler)
+			tasks.POST("/:id/reopen", h.ReopenTaskHandler)
+			tasks.DELETE("/:id/purge", h.PurgeTaskHandler)
+			tasks.POST("/:id/assign", h.AssignTaskHandler)
+			tasks.DELETE("/:id/assign", h.UnassignTaskHandler)
+			tasks.POST("/:id/tags", h.AddTaskTagsHandler)
+			tasks.DELETE("/:id/tags/:tag", h.RemoveTaskTagHandler)
+			tasks.POST("/:id/parent", h.SetTaskParentHandler)
+			tasks.DELETE("/:id/parent", h.RemoveTaskParentHandler)
+			tasks.GET("/:id/children", h.TaskChildrenHandler)
+			tasks.GET("/:id/tree", h.TaskTreeHandler)
+			tasks.GET("/:id/dependencies", h.TaskDependenciesHandler)
+			tasks.POST("/:id/dependencies", h.AddTaskDependencyHandler)
+			tasks.DELETE("/:id/dependencies/:blocker_id", h.RemoveTaskDependencyHandler)
+			tasks.PUT("/:id/schedule", h.ScheduleTaskHandler)
+			tasks.GET("/:id/occurrences", h.TaskOccurrencesHandler)
+			tasks.GET("/:id/comments", h.TaskCommentsHandler)
+			tasks.POST("/:id/comments", h.CreateCommentHandler)
+			tasks.PUT("/:id/comments/:comment_id", h.EditCommentHandler)
+			tasks.DELETE("/:id/comments/:comment_id", h.DeleteCommentHandler)
+			tasks.GET("/:id/comments/:comment_id/history", h.CommentHistoryHandler)
+			tasks.GET("/:id/attachments", h.TaskAttachmentsHandler)
+			tasks.POST("/:id/attachments", h.UploadAttachmentHandler)
+			tasks.GET("/:id/attachments/:attachment_id", h.DownloadAttachmentHandler)
+			tasks.DELETE("/:id/attachments/:attachment_id", h.DeleteAttachmentHandler)
 		}
 
-		notes := api.Group("/notes")
+		tags := api.Group("/tags", h.ResolveWorkspace())
 		{
-			notes.GET("/list", h.ListNoteHandler)
+			tags.GET("", h.ListTagsHandler)
+			tags.POST("", h.CreateTagHandler)
+			tags.PATCH("/:id", h.RenameTagHandler)
+			tags.POST("/:id/merge", h.MergeTagHandler)
+			tags.DELETE("/:id", h.DeleteTagHandler)
 		}
-	}
-}
 
-func authMiddlewareGroup(token string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		if c.GetHeader("Authorization") != token {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
-			return
+		notes := api.Group("/notes", h.ResolveWorkspace())
+		{
+			notes.GET("/list", h.ListNoteHandler)
+			notes.POST("/create", h.CreateNoteHandler)
+			notes.GET("/:id", h.GetNoteHandler)
+			notes.PUT("/:id", h.UpdateNoteHandler)
+			notes.DELETE("/:id", h.DeleteNoteHandler)
 		}
-		c.Next()
 	}
 }
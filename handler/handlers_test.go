@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/auth"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"myApi/repository/memory"
+	"myApi/reqctx"
+	"myApi/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestRouter собирает маршруты задач над хранилищем в памяти. Аутентификацию и
+// выбор пространства заменяет middleware, подставляющий администратора.
+func newTestRouter(t *testing.T) (*gin.Engine, *service.TaskService) {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	workspaces := memory.NewWorkspaceRepository()
+	tasks := service.NewTaskService(memory.NewTaskRepository(logger), memory.NewUserRepository(workspaces), workspaces, logger)
+	h := NewHandler(Services{Tasks: tasks, Policy: policy.New()}, logger)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		principal := auth.Principal{UserID: 1, Role: model.RoleAdmin, Scopes: model.Scopes{model.ScopeAdmin}}
+		ctx := auth.WithPrincipal(c.Request.Context(), principal)
+		ctx = reqctx.WithWorkspace(ctx, model.DefaultWorkspaceID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(principalKey, principal)
+	})
+	router.GET("/task/list", h.TaskListHandler)
+	router.GET("/task/:id", h.GetTaskByIdHandler)
+	router.PUT("/task/:id", h.UpdateTaskHandler)
+	router.PATCH("/task/:id", h.PatchTaskHandler)
+	router.POST("/task/:id/start", h.StartTaskHandler)
+	router.DELETE("/task/:id", h.DeleteTaskHandler)
+	return router, tasks
+}
+
+func serve(router *gin.Engine, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int
+	}{
+		{"no header", "", http.StatusOK},
+		{"any version", "*", http.StatusOK},
+		{"current version", `"2"`, http.StatusOK},
+		{"stale version", `"1"`, http.StatusPreconditionFailed},
+		{"weak current version", `W/"2"`, http.StatusPreconditionFailed},
+		{"unquoted version", `2`, http.StatusPreconditionFailed},
+		{"garbage", `"abc"`, http.StatusPreconditionFailed},
+		{"list with the current version", `"1", "2"`, http.StatusOK},
+		{"list with the current version last", `"5","4" , "2"`, http.StatusOK},
+		{"list without the current version", `"1", "3"`, http.StatusPreconditionFailed},
+		{"weak tags in a list are ignored", `W/"2", "1"`, http.StatusPreconditionFailed},
+		{"strong tag next to a weak one", `W/"1", "2"`, http.StatusOK},
+		{"only weak tags", `W/"1", W/"2"`, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, tasks := newTestRouter(t)
+			ctx := reqctx.WithWorkspace(t.Context(), model.DefaultWorkspaceID)
+			task, err := tasks.Create(ctx, model.Task{Title: "Task"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Вторая версия, чтобы и старая, и текущая были настоящими.
+			if _, err := tasks.Start(ctx, task.ID, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			rec := serve(router, http.MethodPut, fmt.Sprintf("/task/%d", task.ID), `{"title": "Renamed"}`,
+				map[string]string{"If-Match": tt.ifMatch})
+			if rec.Code != tt.want {
+				t.Fatalf("PUT with If-Match %s = %d %s, want %d", tt.ifMatch, rec.Code, rec.Body, tt.want)
+			}
+			current, err := tasks.Get(ctx, task.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if renamed := current.Title == "Renamed"; renamed != (tt.want == http.StatusOK) {
+				t.Errorf("title = %q after status %d", current.Title, rec.Code)
+			}
+			if tt.want == http.StatusOK && rec.Header().Get("ETag") != `"3"` {
+				t.Errorf("ETag = %q, want \"3\"", rec.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestIfMatchListForMissingTask(t *testing.T) {
+	router, _ := newTestRouter(t)
+	rec := serve(router, http.MethodPut, "/task/42", `{"title": "Renamed"}`, map[string]string{"If-Match": `"1", "2"`})
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("PUT = %d, want 404", rec.Code)
+	}
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	router, tasks := newTestRouter(t)
+	ctx := reqctx.WithWorkspace(t.Context(), model.DefaultWorkspaceID)
+	task, err := tasks.Create(ctx, model.Task{Title: "Task"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/task/%d", task.ID)
+
+	first := serve(router, http.MethodGet, path, "", nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag != `"1"` {
+		t.Fatalf("GET = %d ETag %q", first.Code, etag)
+	}
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        int
+	}{
+		{"same etag", etag, http.StatusNotModified},
+		{"weak form of the same etag", "W/" + etag, http.StatusNotModified},
+		{"list with the etag", `"7", ` + etag, http.StatusNotModified},
+		{"any", "*", http.StatusNotModified},
+		{"other etag", `"2"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(router, http.MethodGet, path, "", map[string]string{"If-None-Match": tt.ifNoneMatch})
+			if rec.Code != tt.want {
+				t.Errorf("GET with If-None-Match %s = %d, want %d", tt.ifNoneMatch, rec.Code, tt.want)
+			}
+			if tt.want == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 has a body: %s", rec.Body)
+			}
+		})
+	}
+
+	// После изменения старый ETag больше не подходит.
+	if _, err := tasks.Start(ctx, task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(router, http.MethodGet, path, "", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusOK {
+		t.Errorf("GET after a change = %d, want 200", rec.Code)
+	}
+}
+
+func TestTaskListIfNoneMatch(t *testing.T) {
+	router, tasks := newTestRouter(t)
+	ctx := reqctx.WithWorkspace(t.Context(), model.DefaultWorkspaceID)
+	task, err := tasks.Create(ctx, model.Task{Title: "Task"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := serve(router, http.MethodGet, "/task/list", "", nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("GET list = %d ETag %q", first.Code, etag)
+	}
+	if rec := serve(router, http.MethodGet, "/task/list", "", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Errorf("GET list with its ETag = %d, want 304", rec.Code)
+	}
+	if _, err := tasks.Start(ctx, task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(router, http.MethodGet, "/task/list", "", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusOK {
+		t.Errorf("GET list after a change = %d, want 200", rec.Code)
+	}
+}
+
+func TestTaskErrorResponses(t *testing.T) {
+	router, tasks := newTestRouter(t)
+	ctx := reqctx.WithWorkspace(t.Context(), model.DefaultWorkspaceID)
+	task, err := tasks.Create(ctx, model.Task{Title: "Task"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/task/%d", task.ID)
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"bad id", http.MethodGet, "/task/abc", "", http.StatusBadRequest},
+		{"missing task", http.MethodGet, "/task/42", "", http.StatusNotFound},
+		{"invalid filter", http.MethodGet, "/task/list?priority_min=4&priority_max=2", "", http.StatusBadRequest},
+		{"validation", http.MethodPut, path, `{"title": "x"}`, http.StatusBadRequest},
+		{"start", http.MethodPost, path + "/start", "", http.StatusOK},
+		{"invalid transition", http.MethodPost, path + "/start", "", http.StatusConflict},
+		{"patch", http.MethodPatch, path, `{"title": "Task 2"}`, http.StatusOK},
+		{"delete", http.MethodDelete, path, "", http.StatusNoContent},
+		{"deleted task", http.MethodGet, path, "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := serve(router, tt.method, tt.path, tt.body, nil)
+		if rec.Code != tt.want {
+			t.Errorf("%s: %s %s = %d %s, want %d", tt.name, tt.method, tt.path, rec.Code, rec.Body, tt.want)
+		}
+	}
+}
+
+func TestAbortWithTaskError(t *testing.T) {
+	h := NewHandler(Services{}, slog.New(slog.DiscardHandler))
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{repository.ErrDatabaseUnavailable, http.StatusServiceUnavailable},
+		{fmt.Errorf("%w: title is required", service.ErrValidation), http.StatusBadRequest},
+		{model.ErrInvalidFilter, http.StatusBadRequest},
+		{model.ErrInvalidPatch, http.StatusBadRequest},
+		{model.ErrInvalidSchedule, http.StatusBadRequest},
+		{model.ErrInvalidRecurrence, http.StatusBadRequest},
+		{model.ErrInvalidSearch, http.StatusBadRequest},
+		{model.ErrInvalidBulk, http.StatusBadRequest},
+		{model.ErrInvalidStatus, http.StatusBadRequest},
+		{repository.ErrNoWorkspace, http.StatusBadRequest},
+		{fmt.Errorf("get: %w", repository.ErrTaskNotFound), http.StatusNotFound},
+		{model.ErrVersionMismatch, http.StatusPreconditionFailed},
+		{model.ErrInvalidTransition, http.StatusConflict},
+		{model.ErrPatchTestFailed, http.StatusConflict},
+		{model.ErrTaskCycle, http.StatusConflict},
+		{model.ErrDependencyCycle, http.StatusConflict},
+		{errors.New("connection reset"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			h.abortWithTaskError(c, "update task", 1, tt.err)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == nil {
+				t.Errorf("body = %s, want a JSON error", rec.Body)
+			}
+			// Внутренние ошибки не выходят наружу.
+			if tt.want == http.StatusInternalServerError && strings.Contains(rec.Body.String(), "connection reset") {
+				t.Errorf("body leaks the internal error: %s", rec.Body)
+			}
+		})
+	}
+}
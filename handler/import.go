@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"myApi/dto"
+	"myApi/model"
+	"myApi/policy"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxImportBody — предел размера файла импорта; файлы больше загружаются командой import.
+	maxImportBody = 64 << 20
+	// importTimeout заменяет общие таймауты сервера: большой файл идёт дольше 15 секунд.
+	importTimeout = 5 * time.Minute
+)
+
+// ImportTasksHandler godoc
+// @Summary      Import tasks
+// @Description  Import tasks from a JSON Lines or CSV body (up to 64 MiB) in one transaction. Each line or CSV row is a task with title (required), description, status, priority, tags, start_at, due_at, timezone, created_at, started_at and completed_at; dates are RFC 3339, CSV tags are comma-separated. Invalid rows are skipped and listed in the report with their line numbers. With dry_run the rows are only validated.
+// @Tags         tasks
+// @Accept       plain
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        format   query     string  true   "File format"  Enums(jsonl, csv)
+// @Param        dry_run  query     bool    false  "Validate without importing"
+// @Param        file     body      string  true   "JSON Lines or CSV with a header row"
+// @Success      200  {object}  dto.ImportResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      413  {object}  map[string]string
+// @Failure      503  {object}  map[string]string
+// @Router       /task/import [post]
+func (h *Handler) ImportTasksHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	var query dto.ImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rc := http.NewResponseController(c.Writer)
+	deadline := time.Now().Add(importTimeout)
+	if err := rc.SetReadDeadline(deadline); err != nil {
+		h.logger.Warn("Failed to extend import read deadline", "error", err)
+	}
+	if err := rc.SetWriteDeadline(deadline); err != nil {
+		h.logger.Warn("Failed to extend import write deadline", "error", err)
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBody)
+
+	src, err := dto.NewImportSource(c.Request.Body, model.ImportFormat(query.Format))
+	if err != nil {
+		h.abortWithImportError(c, err)
+		return
+	}
+	report, err := h.tasks.Import(c.Request.Context(), src, query.DryRun)
+	if err != nil {
+		h.abortWithImportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToImportResponse(report))
+}
+
+func (h *Handler) abortWithImportError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Import file is too large, use the import command"})
+	case errors.Is(err, model.ErrInvalidImport):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.abortWithTaskError(c, "import tasks", 0, err)
+	}
+}
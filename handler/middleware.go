@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"myApi/reqctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID берёт X-Request-ID клиента или генерирует новый, возвращает его в ответе
+// и кладёт в контекст запроса для логов и истории изменений.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			buf := make([]byte, 16)
+			_, _ = rand.Read(buf)
+			id = hex.EncodeToString(buf)
+		}
+		c.Header(RequestIDHeader, id)
+		c.Set("request_id", id)
+		c.Request = c.Request.WithContext(reqctx.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
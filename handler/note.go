@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"myApi/dto"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"myApi/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NoteService — заметки рабочего пространства, см. service.NoteService.
+type NoteService interface {
+	List(ctx context.Context, filter model.NoteFilter) (model.NotePage, error)
+	Get(ctx context.Context, id int) (*model.Note, error)
+	Create(ctx context.Context, note model.Note) (*model.Note, error)
+	Update(ctx context.Context, note model.Note) (*model.Note, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// ListNoteHandler godoc
+// @Summary      Get notes
+// @Description  Get a page of notes in the current workspace, most recently updated first
+// @Tags         notes
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        task_id  query     int  false  "Only notes linked to this task"
+// @Param        limit    query     int  false  "Page size (max 500)"
+// @Param        offset   query     int  false  "Rows to skip"
+// @Success      200      {object}  dto.NoteListResponse
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /notes/list [get]
+func (h *Handler) ListNoteHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadNotes) {
+		return
+	}
+	var query dto.NoteListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := dto.ToNoteFilter(query)
+	page, err := h.notes.List(c.Request.Context(), filter)
+	if err != nil {
+		h.abortWithNoteError(c, "get notes", 0, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToNoteListResponse(page, filter))
+}
+
+// CreateNoteHandler godoc
+// @Summary      Create note
+// @Description  Create a note with a markdown body, optionally linked to a task of the same workspace
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        note  body      dto.NoteRequest  true  "Note data"
+// @Success      201   {object}  dto.NoteResponse
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      503   {object}  map[string]string
+// @Router       /notes/create [post]
+func (h *Handler) CreateNoteHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteNotes) {
+		return
+	}
+	var req dto.NoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := h.notes.Create(c.Request.Context(), *dto.ToNoteModel(0, req))
+	if err != nil {
+		h.abortWithNoteError(c, "create note", 0, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToNoteResponse(note))
+}
+
+// GetNoteHandler godoc
+// @Summary      Get note by ID
+// @Description  Get a single note
+// @Tags         notes
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Note ID"
+// @Success      200  {object}  dto.NoteResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      503  {object}  map[string]string
+// @Router       /notes/{id} [get]
+func (h *Handler) GetNoteHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadNotes) {
+		return
+	}
+	id, ok := parseNoteID(c)
+	if !ok {
+		return
+	}
+
+	note, err := h.notes.Get(c.Request.Context(), id)
+	if err != nil {
+		h.abortWithNoteError(c, "get note", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToNoteResponse(note))
+}
+
+// UpdateNoteHandler godoc
+// @Summary      Update note
+// @Description  Replace title, body and task link of a note; omit task_id to unlink it
+// @Tags         notes
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id    path      int              true  "Note ID"
+// @Param        note  body      dto.NoteRequest  true  "Note data"
+// @Success      200   {object}  dto.NoteResponse
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      503   {object}  map[string]string
+// @Router       /notes/{id} [put]
+func (h *Handler) UpdateNoteHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteNotes) {
+		return
+	}
+	id, ok := parseNoteID(c)
+	if !ok {
+		return
+	}
+	var req dto.NoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := h.notes.Update(c.Request.Context(), *dto.ToNoteModel(id, req))
+	if err != nil {
+		h.abortWithNoteError(c, "update note", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToNoteResponse(note))
+}
+
+// DeleteNoteHandler godoc
+// @Summary      Delete note
+// @Description  Delete a note permanently
+// @Tags         notes
+// @Security     ApiKeyAuth
+// @Param        id   path  int  true  "Note ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      503  {object}  map[string]string
+// @Router       /notes/{id} [delete]
+func (h *Handler) DeleteNoteHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteNotes) {
+		return
+	}
+	id, ok := parseNoteID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notes.Delete(c.Request.Context(), id); err != nil {
+		h.abortWithNoteError(c, "delete note", id, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// abortWithNoteError переводит ошибку сервиса заметок в HTTP-ответ.
+func (h *Handler) abortWithNoteError(c *gin.Context, op string, id int, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNoteNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Note not found"})
+	case errors.Is(err, repository.ErrTaskNotFound):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": service.ErrValidation.Error() + ": linked task does not exist"})
+	default:
+		h.abortWithTaskError(c, op, id, err)
+	}
+}
+
+func parseNoteID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid note id"})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"myApi/dto"
+	"myApi/policy"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleTaskHandler godoc
+// @Summary      Set task schedule
+// @Description  Replace the start date, due date, time zone and recurrence rule of the task. Dates without an offset are read in the given IANA time zone; a bare date means the start of that day for start_at and its end for due_at. Omitted fields are cleared. Changing due_at re-arms the due date reminder. A recurrence rule (RRULE subset: FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY) needs due_at; completing the task creates the next occurrence.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id        path      int                  true   "Task ID"
+// @Param        schedule  body      dto.ScheduleRequest  true   "Schedule"
+// @Param        If-Match  header    string               false  "ETag from a previous read"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/schedule [put]
+func (h *Handler) ScheduleTaskHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	var req dto.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	schedule, err := dto.ToTaskSchedule(req)
+	if err != nil {
+		h.abortWithTaskError(c, "schedule task", id, err)
+		return
+	}
+	task, err := h.tasks.Schedule(c.Request.Context(), id, schedule, version)
+	if err != nil {
+		h.abortWithTaskError(c, "schedule task", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// TaskOccurrencesHandler godoc
+// @Summary      Preview recurrence
+// @Description  Upcoming due dates of a recurring task in its time zone: the current one while the task is open, then the following ones. The series stops at COUNT or UNTIL.
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id     path      int  true   "Task ID"
+// @Param        limit  query     int  false  "Number of occurrences (max 100)"
+// @Success      200  {object}  dto.OccurrencesResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/occurrences [get]
+func (h *Handler) TaskOccurrencesHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	var query dto.OccurrencesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	occurrences, err := h.tasks.Occurrences(c.Request.Context(), id, query.Limit)
+	if err != nil {
+		h.abortWithTaskError(c, "preview task occurrences", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.OccurrencesResponse{Occurrences: occurrences})
+}
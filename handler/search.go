@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"myApi/dto"
+	"myApi/policy"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchTasksHandler godoc
+// @Summary      Search tasks
+// @Description  Full-text search over titles and descriptions of live tasks in Russian and English, best matches first. The query accepts words, "quoted phrases", OR and -exclusions. With fuzzy (on by default) words with typos also match. Highlights are HTML with matches wrapped in <mark>.
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        q       query     string  true   "Search query"
+// @Param        fuzzy   query     bool    false  "Match words with typos (default true)"
+// @Param        limit   query     int     false  "Page size (max 100)"
+// @Param        offset  query     int     false  "Results to skip"
+// @Success      200  {object}  dto.SearchResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Router       /task/search [get]
+func (h *Handler) SearchTasksHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	var query dto.SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.tasks.Search(c.Request.Context(), dto.ToTaskSearch(query))
+	if err != nil {
+		h.abortWithTaskError(c, "search tasks", 0, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToSearchResponse(page))
+}
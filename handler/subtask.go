@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"myApi/dto"
+	"myApi/policy"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetTaskParentHandler godoc
+// @Summary      Move task under a parent
+// @Description  Make the task a subtask of another task. A task cannot be nested under itself or its own subtasks.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id        path      int                  true   "Task ID"
+// @Param        parent    body      dto.MoveTaskRequest  true   "Parent task"
+// @Param        If-Match  header    string               false  "ETag from a previous read"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/parent [post]
+func (h *Handler) SetTaskParentHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	var req dto.MoveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.tasks.SetParent(c.Request.Context(), id, req.ParentID, version)
+	if err != nil {
+		h.abortWithTaskError(c, "move task", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// RemoveTaskParentHandler godoc
+// @Summary      Detach subtask
+// @Description  Turn a subtask into a top-level task
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id        path      int     true   "Task ID"
+// @Param        If-Match  header    string  false  "ETag from a previous read"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/parent [delete]
+func (h *Handler) RemoveTaskParentHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.tasks.RemoveParent(c.Request.Context(), id, version)
+	if err != nil {
+		h.abortWithTaskError(c, "detach task", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// TaskChildrenHandler godoc
+// @Summary      List subtasks
+// @Description  List direct subtasks of a task, excluding trashed ones
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  map[string][]dto.TaskResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/children [get]
+func (h *Handler) TaskChildrenHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	tasks, err := h.tasks.Subtasks(c.Request.Context(), id)
+	if err != nil {
+		h.abortWithTaskError(c, "list subtasks", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"list": dto.ToTaskResponses(tasks)})
+}
+
+// TaskTreeHandler godoc
+// @Summary      Get task tree
+// @Description  Get a task with all levels of its subtasks nested under "children". Subtask progress counts every level.
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.TaskTreeResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /task/{id}/tree [get]
+func (h *Handler) TaskTreeHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+
+	tree, err := h.tasks.Tree(c.Request.Context(), id)
+	if err != nil {
+		h.abortWithTaskError(c, "get task tree", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToTaskTreeResponse(tree))
+}
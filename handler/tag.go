@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"myApi/dto"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagService — теги рабочего пространства, см. service.TagService.
+type TagService interface {
+	List(ctx context.Context) ([]model.Tag, error)
+	Create(ctx context.Context, name string) (*model.Tag, error)
+	Rename(ctx context.Context, id int, name string) (*model.Tag, error)
+	Merge(ctx context.Context, sourceID, targetID int) (*model.Tag, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// ListTagsHandler godoc
+// @Summary      List tags
+// @Description  List tags of the current workspace in alphabetical order with the number of tasks using each
+// @Tags         tags
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {object}  map[string][]dto.TagResponse
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /tags [get]
+func (h *Handler) ListTagsHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ReadTasks) {
+		return
+	}
+	tags, err := h.tags.List(c.Request.Context())
+	if err != nil {
+		h.abortWithTagError(c, "list tags", 0, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"list": dto.ToTagResponses(tags)})
+}
+
+// CreateTagHandler godoc
+// @Summary      Create tag
+// @Description  Create a tag. Names are stored in lower case; letters, digits, spaces and "-_.:" are allowed.
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        tag  body      dto.TagRequest  true  "Tag name"
+// @Success      201  {object}  dto.TagResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /tags [post]
+func (h *Handler) CreateTagHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.tags.Create(c.Request.Context(), req.Name)
+	if err != nil {
+		h.abortWithTagError(c, "create tag", 0, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToTagResponse(tag))
+}
+
+// RenameTagHandler godoc
+// @Summary      Rename tag
+// @Description  Rename a tag on every task. If the new name is taken, merge the tags instead.
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int             true  "Tag ID"
+// @Param        tag  body      dto.TagRequest  true  "New name"
+// @Success      200  {object}  dto.TagResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /tags/{id} [patch]
+func (h *Handler) RenameTagHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTagID(c)
+	if !ok {
+		return
+	}
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.tags.Rename(c.Request.Context(), id, req.Name)
+	if err != nil {
+		h.abortWithTagError(c, "rename tag", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToTagResponse(tag))
+}
+
+// MergeTagHandler godoc
+// @Summary      Merge tags
+// @Description  Replace the tag with another one on every task and delete it
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id     path      int                  true  "Tag to merge and delete"
+// @Param        merge  body      dto.MergeTagRequest  true  "Tag to merge into"
+// @Success      200    {object}  dto.TagResponse
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Router       /tags/{id}/merge [post]
+func (h *Handler) MergeTagHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTagID(c)
+	if !ok {
+		return
+	}
+	var req dto.MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.tags.Merge(c.Request.Context(), id, req.Into)
+	if err != nil {
+		h.abortWithTagError(c, "merge tags", id, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToTagResponse(tag))
+}
+
+// DeleteTagHandler godoc
+// @Summary      Delete tag
+// @Description  Delete a tag and remove it from every task
+// @Tags         tags
+// @Security     ApiKeyAuth
+// @Param        id   path  int  true  "Tag ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /tags/{id} [delete]
+func (h *Handler) DeleteTagHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTagID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.tags.Delete(c.Request.Context(), id); err != nil {
+		h.abortWithTagError(c, "delete tag", id, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddTaskTagsHandler godoc
+// @Summary      Tag task
+// @Description  Attach tags to a task by name; missing tags are created
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id        path      int                  true   "Task ID"
+// @Param        tags      body      dto.TaskTagsRequest  true   "Tag names"
+// @Param        If-Match  header    string               false  "ETag from a previous read"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/tags [post]
+func (h *Handler) AddTaskTagsHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	var req dto.TaskTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.tasks.AddTags(c.Request.Context(), id, req.Tags, version)
+	if err != nil {
+		h.abortWithTaskError(c, "tag task", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// RemoveTaskTagHandler godoc
+// @Summary      Untag task
+// @Description  Remove a tag from a task by name; the tag itself is kept
+// @Tags         tasks
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id        path      int     true   "Task ID"
+// @Param        tag       path      string  true   "Tag name"
+// @Param        If-Match  header    string  false  "ETag from a previous read"
+// @Success      200  {object}  dto.TaskResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      412  {object}  map[string]string
+// @Router       /task/{id}/tags/{tag} [delete]
+func (h *Handler) RemoveTaskTagHandler(c *gin.Context) {
+	if !h.authorize(c, policy.WriteTasks) {
+		return
+	}
+	id, ok := parseTaskID(c)
+	if !ok {
+		return
+	}
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.tasks.RemoveTags(c.Request.Context(), id, []string{c.Param("tag")}, version)
+	if err != nil {
+		h.abortWithTaskError(c, "untag task", id, err)
+		return
+	}
+	c.Header("ETag", dto.TaskETag(task))
+	c.JSON(http.StatusOK, dto.ToTaskResponse(task))
+}
+
+// abortWithTagError переводит ошибку сервиса тегов в HTTP-ответ.
+func (h *Handler) abortWithTagError(c *gin.Context, op string, id int, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTagNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+	case errors.Is(err, repository.ErrTagExists):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "A tag with this name already exists"})
+	default:
+		h.abortWithTaskError(c, op, id, err)
+	}
+}
+
+func parseTagID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid tag id"})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"myApi/auth"
+	"myApi/dto"
+	"myApi/model"
+	"myApi/policy"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListUsersHandler godoc
+// @Summary      List users
+// @Description  List all user accounts with their roles. Admin only.
+// @Tags         users
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {object}  map[string][]dto.UserResponse
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /users [get]
+func (h *Handler) ListUsersHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ManageUsers) {
+		return
+	}
+
+	users, err := h.auth.ListUsers(c.Request.Context())
+	if err != nil {
+		h.abortWithAuthError(c, "list users", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"list": dto.ToUserResponses(users)})
+}
+
+// SetUserRoleHandler godoc
+// @Summary      Set user role
+// @Description  Change the role of a user: viewer, member or admin. Admin only; admins cannot change their own role.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id    path      int                 true  "User ID"
+// @Param        role  body      dto.SetRoleRequest  true  "New role"
+// @Success      200   {object}  dto.UserResponse
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /users/{id}/role [put]
+func (h *Handler) SetUserRoleHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ManageUsers) {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+	var req dto.SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal := c.MustGet(principalKey).(auth.Principal)
+	user, err := h.auth.SetUserRole(c.Request.Context(), principal, id, model.Role(req.Role))
+	if err != nil {
+		h.abortWithAuthError(c, "set user role", err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToUserResponse(user))
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+	"myApi/auth"
+	"myApi/dto"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/reqctx"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// workspaceHeader — заголовок, которым клиент выбирает рабочее пространство.
+const workspaceHeader = "X-Workspace-ID"
+
+// WorkspaceService — рабочие пространства и участники, см. service.WorkspaceService.
+type WorkspaceService interface {
+	Resolve(ctx context.Context, principal auth.Principal, requested int) (int, error)
+	Create(ctx context.Context, principal auth.Principal, name string) (*model.Workspace, error)
+	List(ctx context.Context, principal auth.Principal) ([]model.Workspace, error)
+	ListMembers(ctx context.Context, principal auth.Principal, workspaceID int) ([]model.WorkspaceMember, error)
+	AddMember(ctx context.Context, principal auth.Principal, workspaceID, userID int) error
+	RemoveMember(ctx context.Context, principal auth.Principal, workspaceID, userID int) error
+}
+
+// ResolveWorkspace выбирает рабочее пространство запроса по токену или заголовку
+// X-Workspace-ID и кладёт его в контекст; без этого репозитории задач не выполняют запросы.
+func (h *Handler) ResolveWorkspace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requested := 0
+		if header := strings.TrimSpace(c.GetHeader(workspaceHeader)); header != "" {
+			id, err := strconv.Atoi(header)
+			if err != nil || id <= 0 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid " + workspaceHeader + " header"})
+				return
+			}
+			requested = id
+		}
+
+		principal := c.MustGet(principalKey).(auth.Principal)
+		id, err := h.workspaces.Resolve(c.Request.Context(), principal, requested)
+		if err != nil {
+			h.abortWithAuthError(c, "resolve workspace", err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(reqctx.WithWorkspace(c.Request.Context(), id))
+		c.Header(workspaceHeader, strconv.Itoa(id))
+		c.Next()
+	}
+}
+
+// ListWorkspacesHandler godoc
+// @Summary      List workspaces
+// @Description  List workspaces the current user is a member of
+// @Tags         workspaces
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {object}  map[string][]dto.WorkspaceResponse
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /workspaces [get]
+func (h *Handler) ListWorkspacesHandler(c *gin.Context) {
+	principal := c.MustGet(principalKey).(auth.Principal)
+	workspaces, err := h.workspaces.List(c.Request.Context(), principal)
+	if err != nil {
+		h.abortWithAuthError(c, "list workspaces", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"list": dto.ToWorkspaceResponses(workspaces)})
+}
+
+// CreateWorkspaceHandler godoc
+// @Summary      Create workspace
+// @Description  Create a workspace; the creator becomes its first member
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        workspace  body      dto.CreateWorkspaceRequest  true  "Workspace name"
+// @Success      201        {object}  dto.WorkspaceResponse
+// @Failure      400        {object}  map[string]string
+// @Failure      401        {object}  map[string]string
+// @Failure      403        {object}  map[string]string
+// @Failure      500        {object}  map[string]string
+// @Router       /workspaces [post]
+func (h *Handler) CreateWorkspaceHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ManageWorkspaces) {
+		return
+	}
+	var req dto.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal := c.MustGet(principalKey).(auth.Principal)
+	if principal.WorkspaceID != 0 {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Token is bound to a single workspace"})
+		return
+	}
+	ws, err := h.workspaces.Create(c.Request.Context(), principal, req.Name)
+	if err != nil {
+		h.abortWithAuthError(c, "create workspace", err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.ToWorkspaceResponse(ws))
+}
+
+// ListWorkspaceMembersHandler godoc
+// @Summary      List workspace members
+// @Description  List members of a workspace. Only members and admins can see them.
+// @Tags         workspaces
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Workspace ID"
+// @Success      200  {object}  map[string][]dto.WorkspaceMemberResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /workspaces/{id}/members [get]
+func (h *Handler) ListWorkspaceMembersHandler(c *gin.Context) {
+	id, ok := parseWorkspaceID(c)
+	if !ok {
+		return
+	}
+
+	principal := c.MustGet(principalKey).(auth.Principal)
+	members, err := h.workspaces.ListMembers(c.Request.Context(), principal, id)
+	if err != nil {
+		h.abortWithAuthError(c, "list workspace members", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"list": dto.ToWorkspaceMemberResponses(members)})
+}
+
+// AddWorkspaceMemberHandler godoc
+// @Summary      Add workspace member
+// @Description  Add a user to a workspace. Any member can invite; adding an existing member is not an error.
+// @Tags         workspaces
+// @Accept       json
+// @Security     ApiKeyAuth
+// @Param        id      path  int                     true  "Workspace ID"
+// @Param        member  body  dto.AddMemberRequest  true  "User to add"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /workspaces/{id}/members [post]
+func (h *Handler) AddWorkspaceMemberHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ManageWorkspaces) {
+		return
+	}
+	id, ok := parseWorkspaceID(c)
+	if !ok {
+		return
+	}
+	var req dto.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal := c.MustGet(principalKey).(auth.Principal)
+	if err := h.workspaces.AddMember(c.Request.Context(), principal, id, req.UserID); err != nil {
+		h.abortWithAuthError(c, "add workspace member", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveWorkspaceMemberHandler godoc
+// @Summary      Remove workspace member
+// @Description  Remove a user from a workspace. Tasks assigned to the user stay in the workspace.
+// @Tags         workspaces
+// @Security     ApiKeyAuth
+// @Param        id       path  int  true  "Workspace ID"
+// @Param        user_id  path  int  true  "User ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /workspaces/{id}/members/{user_id} [delete]
+func (h *Handler) RemoveWorkspaceMemberHandler(c *gin.Context) {
+	if !h.authorize(c, policy.ManageWorkspaces) {
+		return
+	}
+	id, ok := parseWorkspaceID(c)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || userID <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	principal := c.MustGet(principalKey).(auth.Principal)
+	if err := h.workspaces.RemoveMember(c.Request.Context(), principal, id, userID); err != nil {
+		h.abortWithAuthError(c, "remove workspace member", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func parseWorkspaceID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace id"})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+var ErrInvalidScope = errors.New("invalid scope")
+
+// Scope — право, выданное API-ключу или сессии.
+type Scope string
+
+const (
+	ScopeTasksRead  Scope = "tasks:read"
+	ScopeTasksWrite Scope = "tasks:write"
+	// ScopeAdmin включает все остальные права и управление API-ключами.
+	ScopeAdmin Scope = "admin"
+)
+
+func (s Scope) Valid() bool {
+	switch s {
+	case ScopeTasksRead, ScopeTasksWrite, ScopeAdmin:
+		return true
+	}
+	return false
+}
+
+type Scopes []Scope
+
+// Allows учитывает иерархию: admin разрешает всё, tasks:write — ещё и чтение.
+func (s Scopes) Allows(scope Scope) bool {
+	if slices.Contains(s, ScopeAdmin) || slices.Contains(s, scope) {
+		return true
+	}
+	return scope == ScopeTasksRead && slices.Contains(s, ScopeTasksWrite)
+}
+
+// Covers — все ли права other входят в s; ключ нельзя выпустить шире, чем права создателя.
+func (s Scopes) Covers(other Scopes) bool {
+	for _, scope := range other {
+		if !s.Allows(scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// APIKey — долгоживущий ключ для машинных клиентов. Сам ключ не хранится, только его
+// SHA-256; Prefix — открытая часть, по которой ключ находят и показывают в списке.
+type APIKey struct {
+	ID     int
+	UserID int
+	Name   string
+	Prefix string
+	Hash   string
+	Scopes Scopes
+	// WorkspaceID — пространство, которым ограничен ключ; nil — любое пространство владельца.
+	WorkspaceID *int
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// Active — ключ не отозван и не истёк.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Attachment — файл, прикреплённый к задаче. Checksum — SHA-256 содержимого в hex,
+// StorageKey — ключ содержимого в blob-хранилище.
+type Attachment struct {
+	ID          int
+	TaskID      int
+	WorkspaceID int
+	FileName    string
+	ContentType string
+	Size        int64
+	Checksum    string
+	StorageKey  string
+	UploadedBy  *int
+	CreatedAt   time.Time
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"reflect"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate   AuditAction = "create"
+	AuditUpdate   AuditAction = "update"
+	AuditPatch    AuditAction = "patch"
+	AuditStatus   AuditAction = "status"
+	AuditDelete   AuditAction = "delete"
+	AuditRestore  AuditAction = "restore"
+	AuditPurge    AuditAction = "purge"
+	AuditAssign   AuditAction = "assign"
+	AuditTag      AuditAction = "tag"
+	AuditMove     AuditAction = "move"
+	AuditDepend   AuditAction = "dependency"
+	AuditSchedule AuditAction = "schedule"
+	AuditRecur    AuditAction = "recur"
+	AuditImport   AuditAction = "import"
+)
+
+// AuditEntry — одна запись истории задачи.
+type AuditEntry struct {
+	ID        int64
+	TaskID    int
+	Action    AuditAction
+	Actor     string
+	RequestID string
+	Changes   map[string]FieldChange
+	CreatedAt time.Time
+}
+
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// DiffTasks возвращает изменившиеся поля. before == nil означает создание, after == nil — удаление.
+// version и updated_at не попадают в diff: они меняются при любой записи.
+func DiffTasks(before, after *Task) map[string]FieldChange {
+	fields := func(t *Task) map[string]any {
+		if t == nil {
+			return map[string]any{}
+		}
+		return map[string]any{
+			"title":         t.Title,
+			"description":   t.Description,
+			"status":        t.Status,
+			"priority":      t.Priority,
+			"started_at":    t.StartedAt,
+			"completed_at":  t.CompletedAt,
+			"deleted_at":    t.DeletedAt,
+			"created_by":    t.CreatedBy,
+			"assignee_id":   t.AssigneeID,
+			"tags":          t.Tags,
+			"parent_id":     t.ParentID,
+			"blocked_by":    t.BlockedBy,
+			"start_at":      t.StartAt,
+			"due_at":        t.DueAt,
+			"timezone":      t.Timezone,
+			"recurrence":    t.Recurrence,
+			"recurred_from": t.RecurredFrom,
+		}
+	}
+
+	from, to := fields(before), fields(after)
+	changes := make(map[string]FieldChange)
+	for _, name := range []string{"title", "description", "status", "priority", "started_at", "completed_at", "deleted_at", "created_by", "assignee_id", "tags", "parent_id", "blocked_by", "start_at", "due_at", "timezone", "recurrence", "recurred_from"} {
+		a, b := normalizeAuditValue(from[name]), normalizeAuditValue(to[name])
+		if !reflect.DeepEqual(a, b) {
+			changes[name] = FieldChange{From: a, To: b}
+		}
+	}
+	return changes
+}
+
+func normalizeAuditValue(v any) any {
+	switch p := v.(type) {
+	case *time.Time:
+		if p == nil {
+			return nil
+		}
+		return p.UTC()
+	case *int:
+		if p == nil {
+			return nil
+		}
+		return *p
+	case []string:
+		if len(p) == 0 {
+			return nil
+		}
+		return p
+	case []int:
+		if len(p) == 0 {
+			return nil
+		}
+		return p
+	}
+	return v
+}
+
+// HistoryPage — страница истории задачи, новые записи первыми.
+type HistoryPage struct {
+	Entries []AuditEntry
+	HasMore bool
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiffTasksReportsOnlyChangedFields(t *testing.T) {
+	started := time.Date(2025, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	assignee := 7
+	before := &Task{
+		ID:          1,
+		Title:       "Draft",
+		Description: "same",
+		Status:      StatusPending,
+		Priority:    3,
+		Version:     4,
+		UpdatedAt:   started,
+		Tags:        []string{"a"},
+	}
+	after := *before
+	after.Title = "Final"
+	after.Status = StatusInProgress
+	after.StartedAt = &started
+	after.AssigneeID = &assignee
+	after.Tags = []string{"a", "b"}
+	after.Version = 5
+	after.UpdatedAt = started.Add(time.Hour)
+
+	got := DiffTasks(before, &after)
+	want := map[string]FieldChange{
+		"title":       {From: "Draft", To: "Final"},
+		"status":      {From: StatusPending, To: StatusInProgress},
+		"started_at":  {From: nil, To: started.UTC()},
+		"assignee_id": {From: nil, To: 7},
+		"tags":        {From: []string{"a"}, To: []string{"a", "b"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffTasks() = %#v, want %#v", got, want)
+	}
+}
+
+func TestDiffTasksUnchanged(t *testing.T) {
+	due := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	parent := 2
+	task := &Task{Title: "Same", Priority: 2, DueAt: &due, ParentID: &parent, BlockedBy: []int{3}}
+
+	// Те же значения в других указателях и пустой срез вместо nil — не изменения.
+	dueCopy, parentCopy := due.In(time.FixedZone("X", 3600)), parent
+	other := *task
+	other.DueAt, other.ParentID, other.Tags = &dueCopy, &parentCopy, []string{}
+	other.Version, other.UpdatedAt = 9, due
+
+	if got := DiffTasks(task, &other); len(got) != 0 {
+		t.Errorf("DiffTasks() = %#v, want no changes", got)
+	}
+}
+
+func TestDiffTasksCreateAndPurge(t *testing.T) {
+	author := 5
+	task := &Task{
+		Title:     "New",
+		Status:    StatusPending,
+		Priority:  3,
+		CreatedBy: &author,
+		Tags:      []string{"x"},
+	}
+
+	created := DiffTasks(nil, task)
+	// Строковые и числовые поля попадают в diff всегда, даже пустые; указатели и срезы —
+	// только заданные.
+	wantFields := []string{"created_by", "description", "priority", "recurrence", "status", "tags", "timezone", "title"}
+	if got := slices.Sorted(maps.Keys(created)); !slices.Equal(got, wantFields) {
+		t.Fatalf("create diff fields = %v, want %v", got, wantFields)
+	}
+	for name, change := range created {
+		if change.From != nil {
+			t.Errorf("create %s.from = %#v, want nil", name, change.From)
+		}
+	}
+	if created["title"].To != "New" || created["created_by"].To != 5 || created["description"].To != "" {
+		t.Errorf("create diff = %#v", created)
+	}
+
+	purged := DiffTasks(task, nil)
+	if got := slices.Sorted(maps.Keys(purged)); !slices.Equal(got, wantFields) {
+		t.Fatalf("purge diff fields = %v, want %v", got, wantFields)
+	}
+	for name, change := range purged {
+		if change.To != nil || !reflect.DeepEqual(change.From, created[name].To) {
+			t.Errorf("purge %s = %#v, want the create value turned around", name, change)
+		}
+	}
+}
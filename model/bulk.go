@@ -0,0 +1,142 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// MaxBulkTasks — предел задач в одной массовой операции, как и размер страницы списка.
+const MaxBulkTasks = MaxPageSize
+
+var (
+	ErrInvalidBulk = errors.New("invalid bulk operation")
+	// ErrBulkAborted получают задачи атомарной операции, не применённые из-за ошибки у другой задачи.
+	ErrBulkAborted = errors.New("not applied: another task in the batch failed")
+)
+
+type BulkMode string
+
+const (
+	// BulkAtomic применяет изменения ко всем задачам или ни к одной.
+	BulkAtomic BulkMode = "atomic"
+	// BulkBestEffort применяет изменения к тем задачам, к которым можно, и сообщает об остальных.
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+// BulkChange — изменение, применяемое к каждой задаче. Delete не сочетается с остальными полями.
+type BulkChange struct {
+	Status     *TaskStatus
+	Priority   *int
+	AddTags    []string
+	RemoveTags []string
+	Delete     bool
+}
+
+func (c BulkChange) IsEmpty() bool {
+	return c.Status == nil && c.Priority == nil && len(c.AddTags) == 0 && len(c.RemoveTags) == 0 && !c.Delete
+}
+
+// BulkRequest — массовая операция над задачами из IDs или подходящими под Filter.
+type BulkRequest struct {
+	IDs    []int
+	Filter *TaskFilter
+	Change BulkChange
+	Mode   BulkMode
+}
+
+// Normalize проверяет запрос, убирает повторы из IDs и подставляет режим BulkAtomic.
+func (r *BulkRequest) Normalize() error {
+	if (len(r.IDs) == 0) == (r.Filter == nil) {
+		return fmt.Errorf("%w: specify either task ids or a filter", ErrInvalidBulk)
+	}
+	if r.Change.IsEmpty() {
+		return fmt.Errorf("%w: nothing to change", ErrInvalidBulk)
+	}
+	if r.Change.Delete && (r.Change.Status != nil || r.Change.Priority != nil || len(r.Change.AddTags) > 0 || len(r.Change.RemoveTags) > 0) {
+		return fmt.Errorf("%w: delete cannot be combined with other changes", ErrInvalidBulk)
+	}
+	if r.Change.Status != nil && !r.Change.Status.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, *r.Change.Status)
+	}
+	switch r.Mode {
+	case "":
+		r.Mode = BulkAtomic
+	case BulkAtomic, BulkBestEffort:
+	default:
+		return fmt.Errorf("%w: unknown mode %q", ErrInvalidBulk, r.Mode)
+	}
+
+	seen := make(map[int]bool, len(r.IDs))
+	ids := r.IDs[:0:0]
+	for _, id := range r.IDs {
+		if id <= 0 {
+			return fmt.Errorf("%w: invalid task id %d", ErrInvalidBulk, id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > MaxBulkTasks {
+		return fmt.Errorf("%w: at most %d tasks per request", ErrInvalidBulk, MaxBulkTasks)
+	}
+	r.IDs = ids
+	return nil
+}
+
+// BulkItem — подготовленное изменение одной задачи. Task — её новое состояние, Task.Version —
+// версия, на которой изменение рассчитано: если задачу успели поменять, применять его нельзя.
+// AddTags и RemoveTags содержат только теги, которых у задачи действительно нет или есть.
+type BulkItem struct {
+	Task       Task
+	AddTags    []string
+	RemoveTags []string
+	Delete     bool
+	Action     AuditAction
+}
+
+// BulkResult — итог для одной задачи: Task — её состояние после операции (nil для удалённых
+// и неудачных), Changed — было ли что менять.
+type BulkResult struct {
+	ID      int
+	Task    *Task
+	Changed bool
+	Err     error
+}
+
+// BulkReport — итог массовой операции; Results идут в порядке IDs или по ID для фильтра.
+type BulkReport struct {
+	Mode    BulkMode
+	Results []BulkResult
+}
+
+// Failed — число задач, к которым изменение не применилось.
+func (r BulkReport) Failed() int {
+	n := 0
+	for i := range r.Results {
+		if r.Results[i].Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// MergeTags возвращает теги после добавления add и снятия remove, а также те из них,
+// что действительно меняют набор тегов.
+func MergeTags(tags, add, remove []string) (merged, added, removed []string) {
+	for _, name := range remove {
+		if slices.Contains(tags, name) {
+			removed = append(removed, name)
+		}
+	}
+	for _, name := range add {
+		if !slices.Contains(tags, name) {
+			added = append(added, name)
+		}
+	}
+	merged = slices.DeleteFunc(slices.Clone(tags), func(name string) bool { return slices.Contains(removed, name) })
+	merged = append(merged, added...)
+	slices.Sort(merged)
+	return merged, added, removed
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBulkRequestNormalize(t *testing.T) {
+	status := StatusCompleted
+	unknown := TaskStatus("archived")
+	priority := 2
+	change := BulkChange{Priority: &priority}
+	tooMany := make([]int, MaxBulkTasks+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+	atLimit := append(slices.Clone(tooMany[:MaxBulkTasks]), 1, 2, 3)
+
+	tests := []struct {
+		name    string
+		req     BulkRequest
+		wantIDs []int
+		mode    BulkMode
+		err     error
+	}{
+		{name: "defaults to atomic", req: BulkRequest{IDs: []int{1}, Change: change}, wantIDs: []int{1}, mode: BulkAtomic},
+		{name: "keeps best effort", req: BulkRequest{IDs: []int{1}, Change: change, Mode: BulkBestEffort}, wantIDs: []int{1}, mode: BulkBestEffort},
+		{name: "drops duplicate ids keeping order", req: BulkRequest{IDs: []int{3, 1, 3, 2, 1}, Change: change}, wantIDs: []int{3, 1, 2}, mode: BulkAtomic},
+		{name: "duplicates do not count against the limit", req: BulkRequest{IDs: atLimit, Change: change}, wantIDs: tooMany[:MaxBulkTasks], mode: BulkAtomic},
+		{name: "filter instead of ids", req: BulkRequest{Filter: &TaskFilter{}, Change: BulkChange{Delete: true}}, mode: BulkAtomic},
+		{name: "status change", req: BulkRequest{IDs: []int{1}, Change: BulkChange{Status: &status}}, wantIDs: []int{1}, mode: BulkAtomic},
+		{name: "too many ids", req: BulkRequest{IDs: tooMany, Change: change}, err: ErrInvalidBulk},
+		{name: "neither ids nor filter", req: BulkRequest{Change: change}, err: ErrInvalidBulk},
+		{name: "both ids and filter", req: BulkRequest{IDs: []int{1}, Filter: &TaskFilter{}, Change: change}, err: ErrInvalidBulk},
+		{name: "zero id", req: BulkRequest{IDs: []int{1, 0}, Change: change}, err: ErrInvalidBulk},
+		{name: "negative id", req: BulkRequest{IDs: []int{-4}, Change: change}, err: ErrInvalidBulk},
+		{name: "empty change", req: BulkRequest{IDs: []int{1}}, err: ErrInvalidBulk},
+		{name: "delete with other changes", req: BulkRequest{IDs: []int{1}, Change: BulkChange{Delete: true, AddTags: []string{"x"}}}, err: ErrInvalidBulk},
+		{name: "unknown status", req: BulkRequest{IDs: []int{1}, Change: BulkChange{Status: &unknown}}, err: ErrInvalidStatus},
+		{name: "unknown mode", req: BulkRequest{IDs: []int{1}, Change: change, Mode: "partial"}, err: ErrInvalidBulk},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			err := req.Normalize()
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Normalize() = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize() = %v", err)
+			}
+			if !slices.Equal(req.IDs, tt.wantIDs) || req.Mode != tt.mode {
+				t.Errorf("Normalize() ids %v mode %q, want %v %q", req.IDs, req.Mode, tt.wantIDs, tt.mode)
+			}
+		})
+	}
+}
+
+func TestBulkRequestNormalizeKeepsCallerIDs(t *testing.T) {
+	ids := []int{2, 2, 1}
+	req := BulkRequest{IDs: ids, Change: BulkChange{Delete: true}}
+	if err := req.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ids, []int{2, 2, 1}) {
+		t.Errorf("Normalize() modified the caller's slice: %v", ids)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	tests := []struct {
+		name                   string
+		tags, add, remove      []string
+		merged, added, removed []string
+	}{
+		{name: "add to empty", add: []string{"b", "a"}, merged: []string{"a", "b"}, added: []string{"b", "a"}},
+		{name: "add existing", tags: []string{"a"}, add: []string{"a", "c"}, merged: []string{"a", "c"}, added: []string{"c"}},
+		{name: "remove existing", tags: []string{"a", "b"}, remove: []string{"a", "z"}, merged: []string{"b"}, removed: []string{"a"}},
+		{name: "remove missing", tags: []string{"a"}, remove: []string{"z"}, merged: []string{"a"}},
+		{name: "add and remove", tags: []string{"b", "c"}, add: []string{"a"}, remove: []string{"c"}, merged: []string{"a", "b"}, added: []string{"a"}, removed: []string{"c"}},
+		{name: "nothing", tags: []string{"a"}, merged: []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := slices.Clone(tt.tags)
+			merged, added, removed := MergeTags(tags, tt.add, tt.remove)
+			if !slices.Equal(merged, tt.merged) || !slices.Equal(added, tt.added) || !slices.Equal(removed, tt.removed) {
+				t.Errorf("MergeTags() = %v %v %v, want %v %v %v", merged, added, removed, tt.merged, tt.added, tt.removed)
+			}
+			if !slices.Equal(tags, tt.tags) {
+				t.Errorf("MergeTags() modified the input: %v", tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Comment — комментарий к задаче. ReplyTo указывает на комментарий той же задачи,
+// на который это ответ; nil — начало ветки. У удалённого комментария пустой Body.
+type Comment struct {
+	ID          int
+	TaskID      int
+	ReplyTo     *int
+	AuthorID    *int
+	WorkspaceID int
+	Body        string
+	CreatedAt   time.Time
+	EditedAt    *time.Time
+	DeletedAt   *time.Time
+}
+
+// CommentEdit — прежняя версия текста комментария.
+type CommentEdit struct {
+	ID        int
+	CommentID int
+	Body      string
+	EditedBy  *int
+	EditedAt  time.Time
+}
+
+// CommentNode — комментарий с ответами на него.
+type CommentNode struct {
+	Comment
+	Replies []*CommentNode
+}
+
+// BuildCommentThreads раскладывает комментарии задачи по веткам. Ветки и ответы внутри
+// них идут по времени создания. Ответ, чей родитель не попал в comments, становится
+// началом ветки.
+func BuildCommentThreads(comments []Comment) []*CommentNode {
+	sorted := slices.Clone(comments)
+	slices.SortFunc(sorted, func(a, b Comment) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	nodes := make(map[int]*CommentNode, len(sorted))
+	for _, c := range sorted {
+		nodes[c.ID] = &CommentNode{Comment: c, Replies: []*CommentNode{}}
+	}
+	threads := []*CommentNode{}
+	for _, c := range sorted {
+		node := nodes[c.ID]
+		if c.ReplyTo != nil {
+			if parent, ok := nodes[*c.ReplyTo]; ok {
+				parent.Replies = append(parent.Replies, node)
+				continue
+			}
+		}
+		threads = append(threads, node)
+	}
+	return threads
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrDependencyCycle = errors.New("task dependency would create a cycle")
+	// ErrTaskBlocked — задачу нельзя начать или завершить, пока открыты её блокеры.
+	ErrTaskBlocked = errors.New("task is blocked")
+)
+
+// TaskDependencies — связи задачи: кто её блокирует и кого блокирует она.
+type TaskDependencies struct {
+	BlockedBy []Task
+	Blocks    []Task
+}
+
+// BlockerGuard — TransitionGuard, запрещающий переход в in_progress и completed, пока
+// у задачи есть открытые блокеры.
+func BlockerGuard(task *Task, to TaskStatus) error {
+	if (to == StatusInProgress || to == StatusCompleted) && len(task.OpenBlockers) > 0 {
+		return fmt.Errorf("%w: waiting for tasks %v", ErrTaskBlocked, task.OpenBlockers)
+	}
+	return nil
+}
+
+// PlanStep — волна плана: задачи, которые можно делать параллельно после всех предыдущих волн.
+type PlanStep struct {
+	Level int
+	Tasks []Task
+}
+
+// PlanTasks упорядочивает открытые задачи топологически (алгоритм Кана). Учитываются только
+// блокеры из tasks: завершённые и удалённые задачи уже ничего не блокируют. Уровень 0 —
+// задачи, готовые к работе сейчас. Внутри волны задачи идут по ID.
+func PlanTasks(tasks []Task) []PlanStep {
+	byID := make(map[int]*Task, len(tasks))
+	for i := range tasks {
+		byID[tasks[i].ID] = &tasks[i]
+	}
+	pending := make(map[int]int, len(tasks))
+	dependents := make(map[int][]int)
+	for i := range tasks {
+		for _, blocker := range tasks[i].BlockedBy {
+			if _, ok := byID[blocker]; ok {
+				pending[tasks[i].ID]++
+				dependents[blocker] = append(dependents[blocker], tasks[i].ID)
+			}
+		}
+	}
+
+	var wave []int
+	for i := range tasks {
+		if pending[tasks[i].ID] == 0 {
+			wave = append(wave, tasks[i].ID)
+		}
+	}
+
+	var steps []PlanStep
+	planned := 0
+	for len(wave) > 0 {
+		slices.Sort(wave)
+		step := PlanStep{Level: len(steps)}
+		var next []int
+		for _, id := range wave {
+			step.Tasks = append(step.Tasks, *byID[id])
+			for _, dependent := range dependents[id] {
+				if pending[dependent]--; pending[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		planned += len(wave)
+		steps = append(steps, step)
+		wave = next
+	}
+
+	// Циклов в графе быть не может, но если они всё же есть, задачи не теряются,
+	// а попадают в последнюю волну.
+	if planned < len(tasks) {
+		step := PlanStep{Level: len(steps)}
+		for i := range tasks {
+			if pending[tasks[i].ID] > 0 {
+				step.Tasks = append(step.Tasks, tasks[i])
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func planIDs(steps []PlanStep) [][]int {
+	ids := make([][]int, len(steps))
+	for i, step := range steps {
+		if step.Level != i {
+			panic("plan levels are not consecutive")
+		}
+		for _, task := range step.Tasks {
+			ids[i] = append(ids[i], task.ID)
+		}
+	}
+	return ids
+}
+
+func TestPlanTasks(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []Task
+		want  [][]int
+	}{
+		{name: "no tasks", tasks: nil, want: [][]int{}},
+		{
+			name:  "independent tasks are one wave ordered by id",
+			tasks: []Task{{ID: 3}, {ID: 1}, {ID: 2}},
+			want:  [][]int{{1, 2, 3}},
+		},
+		{
+			name:  "chain",
+			tasks: []Task{{ID: 1, BlockedBy: []int{2}}, {ID: 2, BlockedBy: []int{3}}, {ID: 3}},
+			want:  [][]int{{3}, {2}, {1}},
+		},
+		{
+			// 5 и 4 освобождаются в одной волне, хотя их блокеры идут в разном порядке.
+			name: "diamond with ties",
+			tasks: []Task{
+				{ID: 6, BlockedBy: []int{4, 5}},
+				{ID: 5, BlockedBy: []int{2}},
+				{ID: 4, BlockedBy: []int{1}},
+				{ID: 2},
+				{ID: 1},
+			},
+			want: [][]int{{1, 2}, {4, 5}, {6}},
+		},
+		{
+			name:  "task waits for its slowest blocker",
+			tasks: []Task{{ID: 1}, {ID: 2, BlockedBy: []int{1}}, {ID: 3, BlockedBy: []int{1, 2}}},
+			want:  [][]int{{1}, {2}, {3}},
+		},
+		{
+			name:  "blockers outside the list are done",
+			tasks: []Task{{ID: 1, BlockedBy: []int{99}}, {ID: 2, BlockedBy: []int{1, 98}}},
+			want:  [][]int{{1}, {2}},
+		},
+		{
+			name:  "cycle goes to the last wave",
+			tasks: []Task{{ID: 1}, {ID: 2, BlockedBy: []int{3}}, {ID: 3, BlockedBy: []int{2}}, {ID: 4, BlockedBy: []int{1}}},
+			want:  [][]int{{1}, {4}, {2, 3}},
+		},
+		{
+			name:  "task blocked by a cycle is not lost",
+			tasks: []Task{{ID: 1, BlockedBy: []int{2}}, {ID: 2, BlockedBy: []int{1}}, {ID: 3, BlockedBy: []int{1}}},
+			want:  [][]int{{1, 2, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planIDs(PlanTasks(tt.tasks))
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanTasks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockerGuard(t *testing.T) {
+	blocked := &Task{ID: 1, Status: StatusPending, OpenBlockers: []int{2}}
+	free := &Task{ID: 1, Status: StatusPending}
+
+	tests := []struct {
+		name    string
+		task    *Task
+		to      TaskStatus
+		blocked bool
+	}{
+		{"start blocked", blocked, StatusInProgress, true},
+		{"complete blocked", blocked, StatusCompleted, true},
+		{"stop blocked", blocked, StatusPending, false},
+		{"start free", free, StatusInProgress, false},
+		{"complete free", free, StatusCompleted, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := BlockerGuard(tt.task, tt.to)
+			if tt.blocked != errors.Is(err, ErrTaskBlocked) {
+				t.Errorf("BlockerGuard() = %v, want blocked=%v", err, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestWorkflowWithBlockerGuard(t *testing.T) {
+	w := NewWorkflow()
+	w.AddGuard(BlockerGuard)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	task := &Task{ID: 1, Status: StatusPending, OpenBlockers: []int{2}}
+	err := w.Transition(task, StatusInProgress, now)
+	if !errors.Is(err, ErrInvalidTransition) || !errors.Is(err, ErrTaskBlocked) {
+		t.Fatalf("Transition() = %v, want ErrInvalidTransition wrapping ErrTaskBlocked", err)
+	}
+	if task.Status != StatusPending || task.StartedAt != nil {
+		t.Errorf("refused transition changed the task: %+v", task)
+	}
+
+	task.OpenBlockers = nil
+	if err := w.Transition(task, StatusInProgress, now); err != nil {
+		t.Fatalf("Transition() after the blocker closed = %v", err)
+	}
+	if task.Status != StatusInProgress || !task.StartedAt.Equal(now) {
+		t.Errorf("task = %+v", task)
+	}
+}
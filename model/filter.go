@@ -0,0 +1,175 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+var ErrInvalidFilter = errors.New("invalid task filter")
+
+type TaskSortField string
+
+const (
+	SortByCreatedAt TaskSortField = "created_at"
+	SortByUpdatedAt TaskSortField = "updated_at"
+	SortByPriority  TaskSortField = "priority"
+	SortByTitle     TaskSortField = "title"
+	SortByID        TaskSortField = "id"
+)
+
+func (f TaskSortField) Valid() bool {
+	switch f {
+	case SortByCreatedAt, SortByUpdatedAt, SortByPriority, SortByTitle, SortByID:
+		return true
+	}
+	return false
+}
+
+// TaskFilter описывает выборку для списка задач. Пустые поля не ограничивают выборку.
+type TaskFilter struct {
+	Statuses    []TaskStatus
+	PriorityMin *int
+	PriorityMax *int
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	AssigneeID  *int
+	// Unassigned выбирает задачи без исполнителя; не сочетается с AssigneeID.
+	Unassigned bool
+	CreatedBy  *int
+	// Tags — нормализованные имена тегов; TagMatch определяет, нужны все или любой.
+	Tags     []string
+	TagMatch TagMatch
+	// Overdue выбирает незавершённые задачи с прошедшим сроком; DueFrom/DueTo ограничивают срок.
+	Overdue bool
+	DueFrom *time.Time
+	DueTo   *time.Time
+
+	SortBy   TaskSortField
+	SortDesc bool
+
+	// Либо Offset, либо Cursor: keyset-пагинация не сочетается со смещением.
+	Limit  int
+	Offset int
+	Cursor *TaskCursor
+}
+
+// Normalize подставляет значения по умолчанию и проверяет согласованность полей.
+func (f *TaskFilter) Normalize() error {
+	if f.SortBy == "" {
+		f.SortBy = SortByCreatedAt
+		f.SortDesc = true
+	}
+	if !f.SortBy.Valid() {
+		return fmt.Errorf("%w: unknown sort field %q", ErrInvalidFilter, f.SortBy)
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultPageSize
+	}
+	if f.Limit > MaxPageSize {
+		f.Limit = MaxPageSize
+	}
+	if f.Offset < 0 {
+		return fmt.Errorf("%w: offset must not be negative", ErrInvalidFilter)
+	}
+	if f.AssigneeID != nil && f.Unassigned {
+		return fmt.Errorf("%w: assignee and unassigned are mutually exclusive", ErrInvalidFilter)
+	}
+	if f.TagMatch == "" {
+		f.TagMatch = TagMatchAny
+	}
+	if f.TagMatch != TagMatchAny && f.TagMatch != TagMatchAll {
+		return fmt.Errorf("%w: tag_match must be any or all", ErrInvalidFilter)
+	}
+	if f.Cursor != nil && f.Offset > 0 {
+		return fmt.Errorf("%w: cursor and offset are mutually exclusive", ErrInvalidFilter)
+	}
+	if f.Cursor != nil && f.Cursor.Field != f.SortBy {
+		return fmt.Errorf("%w: cursor was issued for sort=%s", ErrInvalidFilter, f.Cursor.Field)
+	}
+	if f.PriorityMin != nil && f.PriorityMax != nil && *f.PriorityMin > *f.PriorityMax {
+		return fmt.Errorf("%w: priority_min is greater than priority_max", ErrInvalidFilter)
+	}
+	return nil
+}
+
+// TaskPage — страница списка задач. Total считается без учёта пагинации; Limit, Offset
+// и SortBy — окно и сортировка выдачи после подстановки значений по умолчанию.
+type TaskPage struct {
+	Tasks   []Task
+	Total   int
+	HasMore bool
+	Limit   int
+	Offset  int
+	SortBy  TaskSortField
+}
+
+// TaskCursor указывает на последнюю отданную задачу: значение поля сортировки и ID для разрешения равенств.
+type TaskCursor struct {
+	Field TaskSortField `json:"f"`
+	Value string        `json:"v"`
+	ID    int           `json:"id"`
+}
+
+func NewTaskCursor(field TaskSortField, task *Task) TaskCursor {
+	c := TaskCursor{Field: field, ID: task.ID}
+	switch field {
+	case SortByCreatedAt:
+		c.Value = task.CreatedAt.Format(time.RFC3339Nano)
+	case SortByUpdatedAt:
+		c.Value = task.UpdatedAt.Format(time.RFC3339Nano)
+	case SortByPriority:
+		c.Value = strconv.Itoa(task.Priority)
+	case SortByTitle:
+		c.Value = task.Title
+	}
+	return c
+}
+
+func (c TaskCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeTaskCursor(s string) (*TaskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+	}
+	var c TaskCursor
+	if err := json.Unmarshal(raw, &c); err != nil || !c.Field.Valid() {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+	}
+	return &c, nil
+}
+
+// SortValue возвращает значение курсора в типе поля сортировки.
+func (c TaskCursor) SortValue() (any, error) {
+	switch c.Field {
+	case SortByCreatedAt, SortByUpdatedAt:
+		t, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+		}
+		return t, nil
+	case SortByPriority:
+		p, err := strconv.Atoi(c.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+		}
+		return p, nil
+	case SortByTitle:
+		return c.Value, nil
+	}
+	return c.ID, nil
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTaskCursorRoundTrip(t *testing.T) {
+	task := &Task{
+		ID:        17,
+		Title:     "Ёлка, \"кавычки\" и _подчёркивание",
+		Priority:  4,
+		CreatedAt: time.Date(2025, 3, 1, 10, 30, 0, 123456789, time.UTC),
+		UpdatedAt: time.Date(2025, 3, 2, 8, 0, 0, 0, time.FixedZone("MSK", 3*60*60)),
+	}
+	tests := []struct {
+		field TaskSortField
+		value any
+	}{
+		{SortByCreatedAt, task.CreatedAt},
+		{SortByUpdatedAt, task.UpdatedAt},
+		{SortByPriority, 4},
+		{SortByTitle, task.Title},
+		{SortByID, 17},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.field), func(t *testing.T) {
+			encoded := NewTaskCursor(tt.field, task).Encode()
+			decoded, err := DecodeTaskCursor(encoded)
+			if err != nil {
+				t.Fatalf("DecodeTaskCursor() = %v", err)
+			}
+			if decoded.Field != tt.field || decoded.ID != task.ID {
+				t.Errorf("decoded = %+v", decoded)
+			}
+			value, err := decoded.SortValue()
+			if err != nil {
+				t.Fatalf("SortValue() = %v", err)
+			}
+			if want, ok := tt.value.(time.Time); ok {
+				if got, _ := value.(time.Time); !got.Equal(want) {
+					t.Errorf("SortValue() = %v, want %v", value, want)
+				}
+				return
+			}
+			if value != tt.value {
+				t.Errorf("SortValue() = %#v, want %#v", value, tt.value)
+			}
+		})
+	}
+}
+
+func TestDecodeTaskCursorRejectsBadInput(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"f":"id","id":1}`))},
+		{"not json", encode("cursor")},
+		{"json array", encode(`[1,2]`)},
+		{"unknown field", encode(`{"f":"status","v":"x","id":1}`)},
+		{"missing field", encode(`{"v":"x","id":1}`)},
+		{"wrong id type", encode(`{"f":"id","id":"1"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeTaskCursor(tt.cursor); !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("DecodeTaskCursor(%q) = %v, want ErrInvalidFilter", tt.cursor, err)
+			}
+		})
+	}
+}
+
+func TestTaskCursorSortValueRejectsBadValue(t *testing.T) {
+	for _, c := range []TaskCursor{
+		{Field: SortByCreatedAt, Value: "yesterday", ID: 1},
+		{Field: SortByUpdatedAt, Value: "", ID: 1},
+		{Field: SortByPriority, Value: "high", ID: 1},
+	} {
+		if _, err := c.SortValue(); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("SortValue(%+v) = %v, want ErrInvalidFilter", c, err)
+		}
+	}
+}
+
+func TestTaskFilterNormalize(t *testing.T) {
+	cursor := func(field TaskSortField) *TaskCursor { return &TaskCursor{Field: field, Value: "1", ID: 1} }
+	intp := func(v int) *int { return &v }
+
+	tests := []struct {
+		name   string
+		filter TaskFilter
+		want   TaskFilter
+		err    bool
+	}{
+		{
+			name:   "defaults",
+			filter: TaskFilter{},
+			want:   TaskFilter{SortBy: SortByCreatedAt, SortDesc: true, Limit: DefaultPageSize, TagMatch: TagMatchAny},
+		},
+		{
+			name:   "explicit sort keeps direction",
+			filter: TaskFilter{SortBy: SortByTitle, Limit: 5},
+			want:   TaskFilter{SortBy: SortByTitle, Limit: 5, TagMatch: TagMatchAny},
+		},
+		{
+			name:   "limit is capped",
+			filter: TaskFilter{SortBy: SortByID, Limit: MaxPageSize + 1},
+			want:   TaskFilter{SortBy: SortByID, Limit: MaxPageSize, TagMatch: TagMatchAny},
+		},
+		{
+			name:   "cursor for the same sort",
+			filter: TaskFilter{SortBy: SortByPriority, Cursor: cursor(SortByPriority)},
+			want:   TaskFilter{SortBy: SortByPriority, Limit: DefaultPageSize, TagMatch: TagMatchAny, Cursor: cursor(SortByPriority)},
+		},
+		{name: "cursor for another sort", filter: TaskFilter{SortBy: SortByTitle, Cursor: cursor(SortByPriority)}, err: true},
+		{name: "cursor for the default sort", filter: TaskFilter{Cursor: cursor(SortByID)}, err: true},
+		{name: "cursor with offset", filter: TaskFilter{SortBy: SortByID, Cursor: cursor(SortByID), Offset: 10}, err: true},
+		{name: "unknown sort", filter: TaskFilter{SortBy: "status"}, err: true},
+		{name: "negative offset", filter: TaskFilter{Offset: -1}, err: true},
+		{name: "assignee and unassigned", filter: TaskFilter{AssigneeID: intp(1), Unassigned: true}, err: true},
+		{name: "unknown tag match", filter: TaskFilter{TagMatch: "some"}, err: true},
+		{name: "inverted priority range", filter: TaskFilter{PriorityMin: intp(4), PriorityMax: intp(2)}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.filter
+			err := f.Normalize()
+			if tt.err {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Fatalf("Normalize() = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize() = %v", err)
+			}
+			if f.SortBy != tt.want.SortBy || f.SortDesc != tt.want.SortDesc || f.Limit != tt.want.Limit ||
+				f.Offset != tt.want.Offset || f.TagMatch != tt.want.TagMatch || (f.Cursor == nil) != (tt.want.Cursor == nil) {
+				t.Errorf("Normalize() = %+v, want %+v", f, tt.want)
+			}
+		})
+	}
+}
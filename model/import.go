@@ -0,0 +1,61 @@
+package model
+
+import "errors"
+
+var ErrInvalidImport = errors.New("invalid import file")
+
+const (
+	// ImportBatchSize — строк в одной пачке COPY: столько задач хранилище держит в памяти.
+	ImportBatchSize = 1000
+	// MaxImportRejections — сколько отклонённых строк перечисляется в отчёте; считаются все.
+	MaxImportRejections = 1000
+)
+
+type ImportFormat string
+
+const (
+	ImportJSONL ImportFormat = "jsonl"
+	ImportCSV   ImportFormat = "csv"
+)
+
+func (f ImportFormat) Valid() bool {
+	return f == ImportJSONL || f == ImportCSV
+}
+
+// ImportRecord — строка файла импорта. Err — строку не удалось разобрать; такая строка
+// не импортируется и попадает в отчёт.
+type ImportRecord struct {
+	Line int
+	Task Task
+	Err  error
+}
+
+// ImportSource отдаёт строки файла импорта по порядку; io.EOF — строки кончились.
+// Прочие ошибки означают, что файл дальше читать нельзя.
+type ImportSource interface {
+	Next() (ImportRecord, error)
+}
+
+// ImportRejection — отклонённая строка файла и причина.
+type ImportRejection struct {
+	Line  int
+	Error string
+}
+
+// ImportReport — итог импорта. Rejections содержит не больше MaxImportRejections строк,
+// Rejected — число всех отклонённых. При DryRun строки только проверяются.
+type ImportReport struct {
+	Total      int
+	Imported   int
+	Rejected   int
+	Rejections []ImportRejection
+	DryRun     bool
+}
+
+// Reject отмечает строку line как отклонённую.
+func (r *ImportReport) Reject(line int, err error) {
+	r.Rejected++
+	if len(r.Rejections) < MaxImportRejections {
+		r.Rejections = append(r.Rejections, ImportRejection{Line: line, Error: err.Error()})
+	}
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Note — заметка в рабочем пространстве. Body хранится в markdown как есть;
+// отображение остаётся на клиенте.
+type Note struct {
+	ID    int
+	Title string
+	Body  string
+	// TaskID — задача, к которой привязана заметка; nil — заметка сама по себе.
+	TaskID      *int
+	WorkspaceID int
+	CreatedBy   *int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NoteFilter — параметры списка заметок.
+type NoteFilter struct {
+	TaskID *int
+	Limit  int
+	Offset int
+}
+
+type NotePage struct {
+	Notes []Note
+	Total int
+}
@@ -0,0 +1,37 @@
+package model
+
+import "errors"
+
+var (
+	ErrInvalidPatch    = errors.New("invalid patch document")
+	ErrPatchTestFailed = errors.New("patch test operation failed")
+	// ErrVersionMismatch — задачу успели изменить после того, как клиент её прочитал.
+	ErrVersionMismatch = errors.New("task version mismatch")
+)
+
+// TaskFields — изменяемые поля задачи; nil означает «поле не указано».
+type TaskFields struct {
+	Title       *string
+	Description *string
+	Priority    *int
+}
+
+func (f TaskFields) IsEmpty() bool {
+	return f.Title == nil && f.Description == nil && f.Priority == nil
+}
+
+// Matches сообщает, совпадают ли указанные поля с полями задачи.
+func (f TaskFields) Matches(task *Task) bool {
+	return (f.Title == nil || *f.Title == task.Title) &&
+		(f.Description == nil || *f.Description == task.Description) &&
+		(f.Priority == nil || *f.Priority == task.Priority)
+}
+
+// TaskPatch — частичное обновление: Set меняет только указанные поля, а Test задаёт
+// значения, которые должны быть у задачи в момент записи (операции test из JSON Patch).
+type TaskPatch struct {
+	Set  TaskFields
+	Test TaskFields
+	// Version — ожидаемая версия задачи (If-Match); 0 — без проверки.
+	Version int
+}
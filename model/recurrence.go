@@ -0,0 +1,308 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+
+type Frequency string
+
+const (
+	FreqDaily   Frequency = "DAILY"
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+	FreqYearly  Frequency = "YEARLY"
+)
+
+const (
+	maxRecurrenceInterval = 1000
+	maxRecurrenceCount    = 1000
+	// recurrenceHorizon ограничивает поиск следующего вхождения: правило, которое не срабатывает
+	// сто лет (например, 31 февраля), считается исчерпанным.
+	recurrenceHorizon = 100 * 366
+)
+
+// WeekdayNum — элемент BYDAY: день недели и, для MONTHLY, его номер в месяце
+// (1 — первый, -1 — последний); 0 — любой такой день.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Recurrence — подмножество RRULE из RFC 5545: FREQ=DAILY|WEEKLY|MONTHLY|YEARLY, INTERVAL,
+// COUNT или UNTIL, BYDAY (для DAILY, WEEKLY и MONTHLY) и BYMONTHDAY (для DAILY и MONTHLY).
+// Первое вхождение — срок задачи (DTSTART), остальные считаются от него в зоне задачи,
+// поэтому время суток сохраняется и при переходе на летнее время.
+type Recurrence struct {
+	Freq     Frequency
+	Interval int
+	// Count — сколько вхождений осталось, включая текущее; 0 — без ограничения.
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+}
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRecurrence разбирает правило вида "FREQ=WEEKLY;BYDAY=MO,TH", префикс "RRULE:" допустим.
+// UNTIL без «Z» и дата без времени относятся к зоне loc; дата означает конец дня.
+func ParseRecurrence(rule string, loc *time.Location) (Recurrence, error) {
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	r := Recurrence{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return Recurrence{}, fmt.Errorf("%w: malformed part %q", ErrInvalidRecurrence, part)
+		}
+		if seen[name] {
+			return Recurrence{}, fmt.Errorf("%w: %s is repeated", ErrInvalidRecurrence, name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			r.Freq = Frequency(value)
+		case "INTERVAL":
+			r.Interval, err = parseBounded(value, 1, maxRecurrenceInterval)
+		case "COUNT":
+			r.Count, err = parseBounded(value, 1, maxRecurrenceCount)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(value, loc)
+			r.Until = &until
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				day, perr := parseBounded(strings.TrimPrefix(v, "-"), 1, 31)
+				if perr != nil {
+					err = perr
+					break
+				}
+				if strings.HasPrefix(v, "-") {
+					day = -day
+				}
+				r.ByMonthDay = append(r.ByMonthDay, day)
+			}
+		default:
+			return Recurrence{}, fmt.Errorf("%w: %s is not supported", ErrInvalidRecurrence, name)
+		}
+		if err != nil {
+			return Recurrence{}, fmt.Errorf("%w: %s: %w", ErrInvalidRecurrence, name, err)
+		}
+	}
+
+	switch r.Freq {
+	case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+	case "":
+		return Recurrence{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRecurrence)
+	default:
+		return Recurrence{}, fmt.Errorf("%w: FREQ=%s is not supported", ErrInvalidRecurrence, r.Freq)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return Recurrence{}, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRecurrence)
+	}
+	if r.Freq == FreqYearly && (len(r.ByDay) > 0 || len(r.ByMonthDay) > 0) {
+		return Recurrence{}, fmt.Errorf("%w: YEARLY does not support BYDAY or BYMONTHDAY", ErrInvalidRecurrence)
+	}
+	if r.Freq == FreqWeekly && len(r.ByMonthDay) > 0 {
+		return Recurrence{}, fmt.Errorf("%w: WEEKLY does not support BYMONTHDAY", ErrInvalidRecurrence)
+	}
+	if r.Freq != FreqMonthly && slices.ContainsFunc(r.ByDay, func(d WeekdayNum) bool { return d.N != 0 }) {
+		return Recurrence{}, fmt.Errorf("%w: numbered BYDAY is only supported with MONTHLY", ErrInvalidRecurrence)
+	}
+	return r, nil
+}
+
+func parseBounded(s string, min, max int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("must be a number between %d and %d", min, max)
+	}
+	return n, nil
+}
+
+func parseUntil(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", s, loc); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("20060102", s, loc)
+	if err != nil {
+		return time.Time{}, errors.New("must be a date (YYYYMMDD) or date-time (YYYYMMDDTHHMMSSZ)")
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+func parseByDay(s string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, v := range strings.Split(s, ",") {
+		if len(v) < 2 {
+			return nil, fmt.Errorf("unknown day %q", v)
+		}
+		day := slices.Index(weekdayCodes, v[len(v)-2:])
+		if day < 0 {
+			return nil, fmt.Errorf("unknown day %q", v)
+		}
+		wd := WeekdayNum{Day: time.Weekday(day)}
+		if num := v[:len(v)-2]; num != "" {
+			n, err := strconv.Atoi(num)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("day number in %q must be 1-5 or -1..-5", v)
+			}
+			wd.N = n
+		}
+		days = append(days, wd)
+	}
+	return days, nil
+}
+
+// String возвращает правило в каноническом виде; UNTIL всегда в UTC.
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = weekdayCodes[d.Day]
+			if d.N != 0 {
+				days[i] = strconv.Itoa(d.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next возвращает первое вхождение позже after и его номер (anchor — вхождение 0).
+// ok == false, если серия закончилась по COUNT или UNTIL.
+func (r Recurrence) Next(anchor, after time.Time, loc *time.Location) (next time.Time, n int, ok bool) {
+	r.scan(anchor, loc, func(i int, t time.Time) bool {
+		if t.After(after) {
+			next, n, ok = t, i, true
+			return false
+		}
+		return true
+	})
+	return next, n, ok
+}
+
+// Occurrences возвращает до limit вхождений начиная с anchor.
+func (r Recurrence) Occurrences(anchor time.Time, loc *time.Location, limit int) []time.Time {
+	list := make([]time.Time, 0, limit)
+	r.scan(anchor, loc, func(_ int, t time.Time) bool {
+		list = append(list, t)
+		return len(list) < limit
+	})
+	return list
+}
+
+// scan перебирает вхождения по порядку, пока visit возвращает true.
+func (r Recurrence) scan(anchor time.Time, loc *time.Location, visit func(n int, t time.Time) bool) {
+	a := anchor.In(loc)
+	n := 0
+	for offset := 0; offset <= recurrenceHorizon; offset++ {
+		if r.Count > 0 && n >= r.Count {
+			return
+		}
+		day := time.Date(a.Year(), a.Month(), a.Day()+offset, 0, 0, 0, 0, time.UTC)
+		if offset > 0 && !r.matches(day, a) {
+			continue
+		}
+		t := time.Date(day.Year(), day.Month(), day.Day(), a.Hour(), a.Minute(), a.Second(), 0, loc)
+		if r.Until != nil && t.After(*r.Until) {
+			return
+		}
+		if !visit(n, t) {
+			return
+		}
+		n++
+	}
+}
+
+// matches проверяет календарную дату day (в UTC, без времени) относительно первого вхождения a.
+func (r Recurrence) matches(day, a time.Time) bool {
+	start := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	switch r.Freq {
+	case FreqDaily:
+		days := int(day.Sub(start).Hours() / 24)
+		return days%r.Interval == 0 && r.matchesDays(day)
+	case FreqWeekly:
+		weeks := int(weekStart(day).Sub(weekStart(start)).Hours() / (24 * 7))
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+		return r.matchesDays(day)
+	case FreqMonthly:
+		months := (day.Year()-start.Year())*12 + int(day.Month()-start.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			return day.Day() == start.Day()
+		}
+		return r.matchesDays(day)
+	case FreqYearly:
+		return (day.Year()-start.Year())%r.Interval == 0 && day.Month() == start.Month() && day.Day() == start.Day()
+	}
+	return false
+}
+
+// matchesDays применяет BYDAY и BYMONTHDAY; пустой список ничего не ограничивает.
+func (r Recurrence) matchesDays(day time.Time) bool {
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if len(r.ByMonthDay) > 0 && !slices.ContainsFunc(r.ByMonthDay, func(d int) bool {
+		return d == day.Day() || d < 0 && last+d+1 == day.Day()
+	}) {
+		return false
+	}
+	if len(r.ByDay) > 0 && !slices.ContainsFunc(r.ByDay, func(d WeekdayNum) bool {
+		switch {
+		case d.Day != day.Weekday():
+			return false
+		case d.N > 0:
+			return (day.Day()-1)/7+1 == d.N
+		case d.N < 0:
+			return (last-day.Day())/7+1 == -d.N
+		}
+		return true
+	}) {
+		return false
+	}
+	return true
+}
+
+// weekStart возвращает понедельник недели day (WKST=MO).
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
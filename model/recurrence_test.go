@@ -0,0 +1,339 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s is not available: %v", name, err)
+	}
+	return loc
+}
+
+func mustParseRule(t *testing.T, rule string, loc *time.Location) Recurrence {
+	t.Helper()
+	r, err := ParseRecurrence(rule, loc)
+	if err != nil {
+		t.Fatalf("ParseRecurrence(%q) = %v", rule, err)
+	}
+	return r
+}
+
+// dates — даты вхождений в зоне loc в виде YYYY-MM-DD.
+func dates(list []time.Time, loc *time.Location) []string {
+	out := make([]string, len(list))
+	for i, t := range list {
+		out[i] = t.In(loc).Format(time.DateOnly)
+	}
+	return out
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRecurrenceOccurrences(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   string
+		anchor string
+		limit  int
+		want   []string
+	}{
+		{
+			name:   "daily with interval",
+			rule:   "FREQ=DAILY;INTERVAL=3",
+			anchor: "2025-01-30",
+			limit:  4,
+			want:   []string{"2025-01-30", "2025-02-02", "2025-02-05", "2025-02-08"},
+		},
+		{
+			name:   "daily on weekdays",
+			rule:   "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			anchor: "2025-01-30",
+			limit:  4,
+			want:   []string{"2025-01-30", "2025-01-31", "2025-02-03", "2025-02-04"},
+		},
+		{
+			name:   "last friday of the month",
+			rule:   "FREQ=MONTHLY;BYDAY=-1FR",
+			anchor: "2025-01-31",
+			limit:  5,
+			want:   []string{"2025-01-31", "2025-02-28", "2025-03-28", "2025-04-25", "2025-05-30"},
+		},
+		{
+			name:   "second tuesday of the month",
+			rule:   "FREQ=MONTHLY;BYDAY=2TU",
+			anchor: "2025-01-14",
+			limit:  3,
+			want:   []string{"2025-01-14", "2025-02-11", "2025-03-11"},
+		},
+		{
+			name:   "last day of the month",
+			rule:   "FREQ=MONTHLY;BYMONTHDAY=-1",
+			anchor: "2025-01-31",
+			limit:  4,
+			want:   []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30"},
+		},
+		{
+			name:   "last day of february in a leap year",
+			rule:   "FREQ=MONTHLY;BYMONTHDAY=-1",
+			anchor: "2024-01-31",
+			limit:  2,
+			want:   []string{"2024-01-31", "2024-02-29"},
+		},
+		{
+			name:   "monthly on the 31st skips short months",
+			rule:   "FREQ=MONTHLY",
+			anchor: "2025-01-31",
+			limit:  6,
+			want:   []string{"2025-01-31", "2025-03-31", "2025-05-31", "2025-07-31", "2025-08-31", "2025-10-31"},
+		},
+		{
+			name:   "every two weeks across the new year",
+			rule:   "FREQ=WEEKLY;INTERVAL=2",
+			anchor: "2025-12-22",
+			limit:  4,
+			want:   []string{"2025-12-22", "2026-01-05", "2026-01-19", "2026-02-02"},
+		},
+		{
+			name:   "every two weeks on two days across the new year",
+			rule:   "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			anchor: "2025-12-22",
+			limit:  5,
+			want:   []string{"2025-12-22", "2025-12-24", "2026-01-05", "2026-01-07", "2026-01-19"},
+		},
+		{
+			name:   "yearly on february 29",
+			rule:   "FREQ=YEARLY",
+			anchor: "2024-02-29",
+			limit:  2,
+			want:   []string{"2024-02-29", "2028-02-29"},
+		},
+		{
+			name:   "count limits the series",
+			rule:   "FREQ=DAILY;COUNT=3",
+			anchor: "2025-01-01",
+			limit:  10,
+			want:   []string{"2025-01-01", "2025-01-02", "2025-01-03"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mustParseRule(t, tt.rule, time.UTC)
+			anchor, err := time.Parse(time.DateOnly, tt.anchor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := dates(r.Occurrences(anchor.Add(9*time.Hour), time.UTC, tt.limit), time.UTC)
+			if !equalStrings(got, tt.want) {
+				t.Errorf("Occurrences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceImpossibleRuleEnds(t *testing.T) {
+	r := mustParseRule(t, "FREQ=MONTHLY;BYMONTHDAY=31;BYDAY=2MO", time.UTC)
+	anchor := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	if _, _, ok := r.Next(anchor, anchor, time.UTC); ok {
+		t.Error("Next() found an occurrence of an impossible rule")
+	}
+}
+
+func TestRecurrenceUntilDateIsEndOfDayInTaskZone(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	r := mustParseRule(t, "FREQ=DAILY;UNTIL=20250310", ny)
+
+	wantUntil := time.Date(2025, 3, 10, 23, 59, 59, 0, ny)
+	if !r.Until.Equal(wantUntil) {
+		t.Fatalf("Until = %v, want %v", r.Until, wantUntil)
+	}
+	// 21:00 10 марта в Нью-Йорке — уже 11 марта в UTC, но ещё в пределах UNTIL.
+	anchor := time.Date(2025, 3, 8, 21, 0, 0, 0, ny)
+	got := dates(r.Occurrences(anchor, ny, 10), ny)
+	if want := []string{"2025-03-08", "2025-03-09", "2025-03-10"}; !equalStrings(got, want) {
+		t.Errorf("Occurrences() = %v, want %v", got, want)
+	}
+
+	utc := mustParseRule(t, "FREQ=DAILY;UNTIL=20250310T235959Z", ny)
+	if got := dates(utc.Occurrences(anchor, ny, 10), ny); !equalStrings(got, []string{"2025-03-08", "2025-03-09"}) {
+		t.Errorf("Occurrences() with UTC UNTIL = %v", got)
+	}
+	local := mustParseRule(t, "FREQ=DAILY;UNTIL=20250309T210000", ny)
+	if got := dates(local.Occurrences(anchor, ny, 10), ny); !equalStrings(got, []string{"2025-03-08", "2025-03-09"}) {
+		t.Errorf("Occurrences() with local UNTIL = %v", got)
+	}
+}
+
+func TestRecurrenceKeepsTimeOfDayAcrossDST(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	tests := []struct {
+		name   string
+		anchor time.Time
+		rule   string
+	}{
+		// Летнее время в 2025 году начинается 30 марта и заканчивается 26 октября.
+		{"spring forward", time.Date(2025, 3, 29, 9, 0, 0, 0, berlin), "FREQ=DAILY"},
+		{"fall back", time.Date(2025, 10, 25, 9, 0, 0, 0, berlin), "FREQ=DAILY"},
+		{"weekly over both changes", time.Date(2025, 3, 24, 9, 0, 0, 0, berlin), "FREQ=WEEKLY;INTERVAL=31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mustParseRule(t, tt.rule, berlin)
+			list := r.Occurrences(tt.anchor, berlin, 3)
+			if len(list) != 3 {
+				t.Fatalf("got %d occurrences", len(list))
+			}
+			for _, occ := range list {
+				local := occ.In(berlin)
+				if local.Hour() != 9 || local.Minute() != 0 {
+					t.Errorf("occurrence %v is not at 09:00 Berlin time", local)
+				}
+			}
+			if _, off0 := list[0].Zone(); func() bool { _, off := list[2].Zone(); return off == off0 }() {
+				t.Errorf("occurrences %v and %v have the same UTC offset, the test does not cross DST", list[0], list[2])
+			}
+		})
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"rrule:freq=weekly;byday=mo,th", "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{"FREQ=MONTHLY;INTERVAL=1;BYDAY=-1FR", "FREQ=MONTHLY;BYDAY=-1FR"},
+		{" FREQ=MONTHLY ; BYMONTHDAY=1,-1 ", "FREQ=MONTHLY;BYMONTHDAY=1,-1"},
+		{"FREQ=YEARLY;INTERVAL=2;COUNT=5", "FREQ=YEARLY;INTERVAL=2;COUNT=5"},
+		{"FREQ=DAILY;UNTIL=20250310T120000Z", "FREQ=DAILY;UNTIL=20250310T120000Z"},
+		{"FREQ=DAILY;UNTIL=20250310", "FREQ=DAILY;UNTIL=20250310T235959Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r := mustParseRule(t, tt.rule, time.UTC)
+			if got := r.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			again := mustParseRule(t, r.String(), time.UTC)
+			if again.String() != r.String() {
+				t.Errorf("canonical form is not stable: %q -> %q", r.String(), again.String())
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceRejects(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=x",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=DAILY;",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=YEARLY;BYDAY=MO",
+	} {
+		t.Run(rule, func(t *testing.T) {
+			if _, err := ParseRecurrence(rule, time.UTC); !errors.Is(err, ErrInvalidRecurrence) {
+				t.Errorf("ParseRecurrence(%q) = %v, want ErrInvalidRecurrence", rule, err)
+			}
+		})
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	due := time.Date(2025, 1, 6, 18, 0, 0, 0, time.UTC)
+	start := due.Add(-2 * time.Hour)
+	task := &Task{
+		ID:         10,
+		Title:      "Weekly report",
+		Priority:   2,
+		Tags:       []string{"report"},
+		StartAt:    &start,
+		DueAt:      &due,
+		Recurrence: "FREQ=DAILY;COUNT=5",
+	}
+
+	// Завершение в срок даёт следующее по порядку вхождение.
+	next, ok := task.NextOccurrence(due.Add(-time.Hour))
+	if !ok {
+		t.Fatal("NextOccurrence() found nothing")
+	}
+	if !next.DueAt.Equal(due.AddDate(0, 0, 1)) || next.Recurrence != "FREQ=DAILY;COUNT=4" {
+		t.Errorf("next due %v rule %q, want %v COUNT=4", next.DueAt, next.Recurrence, due.AddDate(0, 0, 1))
+	}
+	if !next.StartAt.Equal(next.DueAt.Add(-2*time.Hour)) || *next.RecurredFrom != task.ID {
+		t.Errorf("next start %v recurred_from %v", next.StartAt, *next.RecurredFrom)
+	}
+	if next.Status != StatusPending || next.Title != task.Title || next.Priority != 2 || len(next.Tags) != 1 {
+		t.Errorf("next task fields = %+v", next)
+	}
+
+	// Пропущенные вхождения не создаются, но расходуют COUNT.
+	late, ok := task.NextOccurrence(due.AddDate(0, 0, 2).Add(time.Hour))
+	if !ok {
+		t.Fatal("NextOccurrence() after skipped occurrences found nothing")
+	}
+	if !late.DueAt.Equal(due.AddDate(0, 0, 3)) || late.Recurrence != "FREQ=DAILY;COUNT=2" {
+		t.Errorf("late due %v rule %q, want %v COUNT=2", late.DueAt, late.Recurrence, due.AddDate(0, 0, 3))
+	}
+
+	// Последнее вхождение пропущено — серия закончилась.
+	if _, ok := task.NextOccurrence(due.AddDate(0, 0, 4).Add(time.Hour)); ok {
+		t.Error("NextOccurrence() after the last occurrence returned a task")
+	}
+	last := *task
+	last.Recurrence = "FREQ=DAILY;COUNT=1"
+	if _, ok := last.NextOccurrence(due); ok {
+		t.Error("NextOccurrence() with COUNT=1 returned a task")
+	}
+	once := *task
+	once.Recurrence = ""
+	if _, ok := once.NextOccurrence(due); ok {
+		t.Error("NextOccurrence() without a rule returned a task")
+	}
+}
+
+func TestNextOccurrenceInTaskZone(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	due := time.Date(2025, 3, 28, 9, 0, 0, 0, berlin)
+	task := &Task{ID: 1, Title: "Stand-up", DueAt: &due, Timezone: "Europe/Berlin", Recurrence: "FREQ=WEEKLY;BYDAY=FR"}
+
+	next, ok := task.NextOccurrence(due)
+	if !ok {
+		t.Fatal("NextOccurrence() found nothing")
+	}
+	if want := time.Date(2025, 4, 4, 9, 0, 0, 0, berlin); !next.DueAt.Equal(want) {
+		t.Errorf("next due = %v, want %v", next.DueAt.In(berlin), want)
+	}
+	if next.DueAt.Sub(due) != 7*24*time.Hour-time.Hour {
+		t.Errorf("next due is %v after the previous one, want a week minus the DST hour", next.DueAt.Sub(due))
+	}
+}
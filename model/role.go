@@ -0,0 +1,25 @@
+package model
+
+import "errors"
+
+var ErrInvalidRole = errors.New("invalid role")
+
+// Role — уровень доступа пользователя.
+type Role string
+
+const (
+	// RoleViewer только читает задачи.
+	RoleViewer Role = "viewer"
+	// RoleMember создаёт и меняет задачи и выпускает себе API-ключи.
+	RoleMember Role = "member"
+	// RoleAdmin дополнительно удаляет задачи безвозвратно и назначает роли.
+	RoleAdmin Role = "admin"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleViewer, RoleMember, RoleAdmin:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidSchedule = errors.New("invalid task schedule")
+
+// TaskSchedule — плановые даты задачи. Меняется целиком: nil снимает дату.
+type TaskSchedule struct {
+	StartAt  *time.Time
+	DueAt    *time.Time
+	Timezone string
+	// Recurrence — правило повторения (см. Recurrence); повторяется срок задачи.
+	Recurrence string
+}
+
+// Normalize проверяет зону, порядок дат и правило повторения и приводит правило
+// к каноническому виду.
+func (s *TaskSchedule) Normalize() error {
+	loc, err := LoadTimezone(s.Timezone)
+	if err != nil {
+		return err
+	}
+	if s.StartAt != nil && s.DueAt != nil && s.StartAt.After(*s.DueAt) {
+		return fmt.Errorf("%w: start_at is after due_at", ErrInvalidSchedule)
+	}
+	if s.Recurrence == "" {
+		return nil
+	}
+	if s.DueAt == nil {
+		return fmt.Errorf("%w: recurring tasks need due_at", ErrInvalidSchedule)
+	}
+	rule, err := ParseRecurrence(s.Recurrence, loc)
+	if err != nil {
+		return err
+	}
+	s.Recurrence = rule.String()
+	return nil
+}
+
+// Schedule возвращает плановые даты задачи.
+func (t *Task) Schedule() TaskSchedule {
+	return TaskSchedule{StartAt: t.StartAt, DueAt: t.DueAt, Timezone: t.Timezone, Recurrence: t.Recurrence}
+}
+
+// Location возвращает зону задачи; неизвестная или пустая зона даёт UTC.
+func (t *Task) Location() *time.Location {
+	loc, err := LoadTimezone(t.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// NextOccurrence строит следующий экземпляр повторяющейся задачи: первое вхождение правила
+// позже after (пропущенные не создаются). Начало сдвигается вместе со сроком, COUNT
+// уменьшается на число пройденных вхождений. ok == false, если задача не повторяется или
+// серия закончилась.
+func (t *Task) NextOccurrence(after time.Time) (next Task, ok bool) {
+	if t.Recurrence == "" || t.DueAt == nil {
+		return Task{}, false
+	}
+	loc := t.Location()
+	rule, err := ParseRecurrence(t.Recurrence, loc)
+	if err != nil {
+		return Task{}, false
+	}
+	if after.Before(*t.DueAt) {
+		after = *t.DueAt
+	}
+	due, n, ok := rule.Next(*t.DueAt, after, loc)
+	if !ok {
+		return Task{}, false
+	}
+	if rule.Count > 0 {
+		rule.Count -= n
+	}
+
+	next = Task{
+		Title:        t.Title,
+		Description:  t.Description,
+		Status:       StatusPending,
+		Priority:     t.Priority,
+		CreatedBy:    t.CreatedBy,
+		AssigneeID:   t.AssigneeID,
+		ParentID:     t.ParentID,
+		Tags:         t.Tags,
+		DueAt:        &due,
+		Timezone:     t.Timezone,
+		Recurrence:   rule.String(),
+		RecurredFrom: &t.ID,
+	}
+	if t.StartAt != nil {
+		start := due.Add(t.StartAt.Sub(*t.DueAt))
+		next.StartAt = &start
+	}
+	return next, true
+}
+
+// Overdue — срок прошёл, а задача не завершена.
+func (t *Task) Overdue(now time.Time) bool {
+	return t.DueAt != nil && t.Status != StatusCompleted && t.DueAt.Before(now)
+}
+
+// LoadTimezone возвращает IANA-зону по имени; пустое имя означает UTC.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, name)
+	}
+	return loc, nil
+}
+
+// localLayouts — форматы без смещения: такие значения относятся к зоне задачи.
+var localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+
+// ParseScheduleTime разбирает дату задачи. RFC 3339 со смещением берётся как есть, время без
+// смещения — в зоне loc. Дата без времени означает начало дня, а для срока (endOfDay) — его конец.
+func ParseScheduleTime(raw string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, nil
+		}
+	}
+	day, err := time.ParseInLocation(time.DateOnly, raw, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: cannot parse %q as a date", ErrInvalidSchedule, raw)
+	}
+	if endOfDay {
+		return day.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return day, nil
+}
+
+// Reminder — напоминание о приближающемся сроке задачи.
+type Reminder struct {
+	Task Task
+	// Lead — за сколько до срока должно было прийти напоминание.
+	Lead   time.Duration
+	SentAt time.Time
+}
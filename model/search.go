@@ -0,0 +1,71 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+)
+
+var ErrInvalidSearch = errors.New("invalid search query")
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	maxSearchQuery     = 200
+)
+
+// Маркеры совпадений в Title и Snippet результата поиска. Хранилище расставляет их
+// вместо разметки, чтобы текст задачи можно было экранировать при выводе.
+const (
+	HighlightStart = "\x02"
+	HighlightStop  = "\x03"
+)
+
+// TaskSearch — запрос полнотекстового поиска. Query понимает синтаксис websearch:
+// слова, "фразы в кавычках", OR и -исключение. Fuzzy добавляет совпадения с опечатками.
+type TaskSearch struct {
+	Query  string
+	Fuzzy  bool
+	Limit  int
+	Offset int
+}
+
+func (s *TaskSearch) Normalize() error {
+	s.Query = strings.TrimSpace(s.Query)
+	if s.Query == "" {
+		return fmt.Errorf("%w: query is empty", ErrInvalidSearch)
+	}
+	if len([]rune(s.Query)) > maxSearchQuery {
+		return fmt.Errorf("%w: query is longer than %d characters", ErrInvalidSearch, maxSearchQuery)
+	}
+	if s.Limit <= 0 {
+		s.Limit = DefaultSearchLimit
+	}
+	s.Limit = min(s.Limit, MaxSearchLimit)
+	s.Offset = max(s.Offset, 0)
+	return nil
+}
+
+// SearchHit — найденная задача. Title и Snippet — заголовок и фрагменты описания
+// с совпадениями между HighlightStart и HighlightStop.
+type SearchHit struct {
+	Task    Task
+	Rank    float64
+	Title   string
+	Snippet string
+}
+
+// SearchPage — страница результатов, лучшие совпадения первыми. Limit и Offset — окно
+// выдачи после подстановки значений по умолчанию.
+type SearchPage struct {
+	Hits   []SearchHit
+	Total  int
+	Limit  int
+	Offset int
+}
+
+// RenderHighlight экранирует текст для HTML и заменяет маркеры совпадений на <mark>.
+func RenderHighlight(s string) string {
+	return strings.NewReplacer(HighlightStart, "<mark>", HighlightStop, "</mark>").Replace(html.EscapeString(s))
+}
@@ -0,0 +1,49 @@
+package model
+
+import "errors"
+
+// ErrTaskCycle — задачу пытаются сделать подзадачей её самой или её же подзадачи.
+var ErrTaskCycle = errors.New("task cannot be nested under itself or its subtask")
+
+// SubtaskProgress — сводка по подзадачам всех уровней (без задач в корзине).
+type SubtaskProgress struct {
+	Total     int
+	Completed int
+}
+
+// Percent возвращает долю завершённых подзадач в процентах, округлённую вниз.
+func (p SubtaskProgress) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return p.Completed * 100 / p.Total
+}
+
+// TaskNode — задача с подзадачами для представления дерева.
+type TaskNode struct {
+	Task     Task
+	Children []*TaskNode
+}
+
+// BuildTaskTree собирает дерево с корнем rootID из плоского списка задач поддерева.
+// Подзадачи идут в порядке списка; задачи, не связанные с корнем, пропускаются.
+func BuildTaskTree(rootID int, tasks []Task) (*TaskNode, bool) {
+	nodes := make(map[int]*TaskNode, len(tasks))
+	for i := range tasks {
+		nodes[tasks[i].ID] = &TaskNode{Task: tasks[i]}
+	}
+	root, ok := nodes[rootID]
+	if !ok {
+		return nil, false
+	}
+	for i := range tasks {
+		task := &tasks[i]
+		if task.ID == rootID || task.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*task.ParentID]; ok {
+			parent.Children = append(parent.Children, nodes[task.ID])
+		}
+	}
+	return root, true
+}
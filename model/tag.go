@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxTagNameLength = 50
+
+var ErrInvalidTag = errors.New("invalid tag")
+
+// Tag — метка задач в рабочем пространстве. Имя уникально в пространстве.
+type Tag struct {
+	ID          int
+	Name        string
+	WorkspaceID int
+	// TaskCount — сколько живых задач помечено тегом.
+	TaskCount int
+	CreatedAt time.Time
+}
+
+// TagMatch — как фильтр по нескольким тегам сочетает их.
+type TagMatch string
+
+const (
+	// TagMatchAny — у задачи есть хотя бы один из тегов.
+	TagMatchAny TagMatch = "any"
+	// TagMatchAll — у задачи есть все теги.
+	TagMatchAll TagMatch = "all"
+)
+
+// NormalizeTagName приводит имя тега к виду, в котором оно хранится: нижний регистр,
+// одиночные пробелы. Разрешены буквы, цифры, пробел и «-_.:», чтобы имя можно было
+// передать в пути URL.
+func NormalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	if n := utf8.RuneCountInString(name); n == 0 || n > maxTagNameLength {
+		return "", fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidTag, maxTagNameLength)
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" -_.:", r) {
+			return "", fmt.Errorf("%w: name %q contains %q", ErrInvalidTag, name, r)
+		}
+	}
+	return name, nil
+}
+
+// NormalizeTagNames нормализует имена и убирает повторы, сохраняя порядок.
+func NormalizeTagNames(names []string) ([]string, error) {
+	out := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		n, err := NormalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
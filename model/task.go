@@ -8,8 +8,36 @@ type Task struct {
 	Description string
 	Status      TaskStatus
 	Priority    int
+	Version     int
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	DeletedAt   *time.Time
+	// CreatedBy — автор задачи; nil для задач, созданных до появления пользователей.
+	CreatedBy   *int
+	AssigneeID  *int
+	WorkspaceID int
+	// Tags — имена тегов задачи по алфавиту.
+	Tags []string
+	// ParentID — родительская задача, nil для корневых.
+	ParentID *int
+	// BlockedBy — ID задач, которые блокируют эту, по возрастанию.
+	BlockedBy []int
+	// StartAt и DueAt — плановые начало и срок, nil если не заданы.
+	StartAt *time.Time
+	DueAt   *time.Time
+	// Timezone — IANA-зона задачи; пустая строка означает UTC.
+	Timezone string
+	// Recurrence — RRULE повторяющейся задачи; RecurredFrom — предыдущий экземпляр серии.
+	Recurrence   string
+	RecurredFrom *int
+	// Subtasks вычисляется при чтении; изменения потомков увеличивают версию задачи,
+	// чтобы ETag не отдавал старый прогресс.
+	Subtasks SubtaskProgress
+	// OpenBlockers — незавершённые блокеры не из корзины; тоже вычисляется при чтении,
+	// а смена состояния блокера увеличивает версию задачи.
+	OpenBlockers []int
 }
 
 const (
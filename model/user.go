@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+type User struct {
+	ID           int
+	Email        string
+	Name         string
+	PasswordHash string
+	Role         Role
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidStatus     = errors.New("invalid task status")
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
+
+func (s TaskStatus) Valid() bool {
+	switch s {
+	case StatusPending, StatusInProgress, StatusCompleted:
+		return true
+	}
+	return false
+}
+
+// TransitionGuard может запретить переход, вернув ошибку. Ошибка отдаётся клиенту как 409.
+type TransitionGuard func(task *Task, to TaskStatus) error
+
+// Workflow — конечный автомат статусов задачи.
+type Workflow struct {
+	transitions map[TaskStatus][]TaskStatus
+	guards      []TransitionGuard
+}
+
+func NewWorkflow() *Workflow {
+	return &Workflow{
+		transitions: map[TaskStatus][]TaskStatus{
+			StatusPending:    {StatusInProgress},
+			StatusInProgress: {StatusCompleted, StatusPending},
+			StatusCompleted:  {StatusInProgress},
+		},
+	}
+}
+
+func (w *Workflow) AddGuard(g TransitionGuard) {
+	w.guards = append(w.guards, g)
+}
+
+func (w *Workflow) CanTransition(from, to TaskStatus) bool {
+	for _, allowed := range w.transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition переводит задачу в статус to и проставляет started_at/completed_at.
+// Задача не меняется, если переход запрещён.
+func (w *Workflow) Transition(task *Task, to TaskStatus, now time.Time) error {
+	if !to.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, to)
+	}
+	if !w.CanTransition(task.Status, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, task.Status, to)
+	}
+	for _, guard := range w.guards {
+		if err := guard(task, to); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidTransition, err)
+		}
+	}
+
+	switch to {
+	case StatusInProgress:
+		if task.StartedAt == nil {
+			task.StartedAt = &now
+		}
+		task.CompletedAt = nil
+	case StatusCompleted:
+		task.CompletedAt = &now
+	case StatusPending:
+		task.StartedAt = nil
+	}
+	task.Status = to
+	return nil
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// DefaultWorkspaceID — пространство, в которое миграция перенесла задачи, созданные
+// до появления рабочих пространств.
+const DefaultWorkspaceID = 1
+
+// Workspace изолирует задачи одной команды от других.
+type Workspace struct {
+	ID        int
+	Name      string
+	CreatedBy *int
+	CreatedAt time.Time
+}
+
+type WorkspaceMember struct {
+	WorkspaceID int
+	UserID      int
+	CreatedAt   time.Time
+}
@@ -0,0 +1,75 @@
+// Package policy решает, может ли аутентифицированный клиент выполнить действие.
+// Пакет не зависит от gin: правила проверяются на обычном auth.Principal.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"myApi/auth"
+	"myApi/model"
+	"slices"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// Action — операция, на которую проверяются права.
+type Action string
+
+const (
+	ReadTasks     Action = "tasks.read"
+	WriteTasks    Action = "tasks.write"
+	PurgeTasks    Action = "tasks.purge"
+	ReadNotes     Action = "notes.read"
+	WriteNotes    Action = "notes.write"
+	ManageAPIKeys Action = "apikeys.manage"
+	ManageUsers   Action = "users.manage"
+	// ManageWorkspaces — создание пространств и управление их участниками.
+	ManageWorkspaces Action = "workspaces.manage"
+	// ModerateComments — удаление чужих комментариев к задачам.
+	ModerateComments Action = "comments.moderate"
+)
+
+// Policy сочетает два ограничения: роль владельца определяет, что ему вообще можно,
+// а scope'ы API-ключа сужают это для конкретного токена.
+type Policy struct {
+	roles  map[model.Role][]Action
+	scopes map[Action]model.Scope
+}
+
+func New() *Policy {
+	viewer := []Action{ReadTasks, ReadNotes}
+	member := append(slices.Clone(viewer), WriteTasks, WriteNotes, ManageAPIKeys, ManageWorkspaces)
+	admin := append(slices.Clone(member), PurgeTasks, ManageUsers, ModerateComments)
+
+	return &Policy{
+		roles: map[model.Role][]Action{
+			model.RoleViewer: viewer,
+			model.RoleMember: member,
+			model.RoleAdmin:  admin,
+		},
+		scopes: map[Action]model.Scope{
+			ReadTasks:  model.ScopeTasksRead,
+			WriteTasks: model.ScopeTasksWrite,
+			PurgeTasks: model.ScopeTasksWrite,
+			// Заметки — часть содержимого пространства, отдельные scope'ы для них не нужны.
+			ReadNotes:     model.ScopeTasksRead,
+			WriteNotes:    model.ScopeTasksWrite,
+			ManageAPIKeys: model.ScopeAdmin,
+			ManageUsers:   model.ScopeAdmin,
+			// Ключ, выданный CI, не должен приглашать людей в пространство.
+			ManageWorkspaces: model.ScopeAdmin,
+			ModerateComments: model.ScopeTasksWrite,
+		},
+	}
+}
+
+// Authorize возвращает ErrForbidden, если principal не может выполнить action.
+func (p *Policy) Authorize(principal auth.Principal, action Action) error {
+	if !slices.Contains(p.roles[principal.Role], action) {
+		return fmt.Errorf("%w: role %q cannot perform %s", ErrForbidden, principal.Role, action)
+	}
+	if scope, ok := p.scopes[action]; ok && !principal.HasScope(scope) {
+		return fmt.Errorf("%w: token lacks scope %s", ErrForbidden, scope)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"myApi/auth"
+	"myApi/model"
+	"slices"
+	"testing"
+)
+
+var allActions = []Action{
+	ReadTasks, WriteTasks, PurgeTasks, ReadNotes, WriteNotes,
+	ManageAPIKeys, ManageUsers, ManageWorkspaces, ModerateComments,
+}
+
+// Ожидания записаны явно, а не выведены из New, чтобы тест ловил случайные изменения таблиц.
+var roleActions = map[model.Role][]Action{
+	model.RoleViewer: {ReadTasks, ReadNotes},
+	model.RoleMember: {ReadTasks, ReadNotes, WriteTasks, WriteNotes, ManageAPIKeys, ManageWorkspaces},
+	model.RoleAdmin:  allActions,
+}
+
+var scopeSets = []struct {
+	name    string
+	scopes  model.Scopes
+	actions []Action
+}{
+	{"session", model.Scopes{model.ScopeAdmin}, allActions},
+	{"read key", model.Scopes{model.ScopeTasksRead}, []Action{ReadTasks, ReadNotes}},
+	{"write key", model.Scopes{model.ScopeTasksWrite}, []Action{ReadTasks, ReadNotes, WriteTasks, WriteNotes, PurgeTasks, ModerateComments}},
+	{"read+write key", model.Scopes{model.ScopeTasksRead, model.ScopeTasksWrite}, []Action{ReadTasks, ReadNotes, WriteTasks, WriteNotes, PurgeTasks, ModerateComments}},
+	{"admin key", model.Scopes{model.ScopeAdmin}, allActions},
+	{"key without scopes", nil, nil},
+}
+
+func TestAuthorize(t *testing.T) {
+	p := New()
+	for _, role := range []model.Role{model.RoleViewer, model.RoleMember, model.RoleAdmin} {
+		for _, set := range scopeSets {
+			principal := auth.Principal{UserID: 1, Role: role, Scopes: set.scopes}
+			if set.name != "session" {
+				principal.APIKeyID = 10
+			}
+			for _, action := range allActions {
+				want := slices.Contains(roleActions[role], action) && slices.Contains(set.actions, action)
+				t.Run(fmt.Sprintf("%s/%s/%s", role, set.name, action), func(t *testing.T) {
+					err := p.Authorize(principal, action)
+					if want && err != nil {
+						t.Errorf("Authorize() = %v, want allowed", err)
+					}
+					if !want && !errors.Is(err, ErrForbidden) {
+						t.Errorf("Authorize() = %v, want ErrForbidden", err)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestAuthorizeExamples(t *testing.T) {
+	p := New()
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		action    Action
+		allowed   bool
+	}{
+		{"viewer cannot create, update or delete tasks", auth.Principal{Role: model.RoleViewer, Scopes: model.Scopes{model.ScopeAdmin}}, WriteTasks, false},
+		{"viewer reads tasks", auth.Principal{Role: model.RoleViewer, Scopes: model.Scopes{model.ScopeAdmin}}, ReadTasks, true},
+		{"member cannot purge", auth.Principal{Role: model.RoleMember, Scopes: model.Scopes{model.ScopeAdmin}}, PurgeTasks, false},
+		{"admin with read-only key cannot write", auth.Principal{Role: model.RoleAdmin, APIKeyID: 1, Scopes: model.Scopes{model.ScopeTasksRead}}, WriteTasks, false},
+		{"admin with write key cannot manage users", auth.Principal{Role: model.RoleAdmin, APIKeyID: 1, Scopes: model.Scopes{model.ScopeTasksWrite}}, ManageUsers, false},
+		{"viewer with admin key is still a viewer", auth.Principal{Role: model.RoleViewer, APIKeyID: 1, Scopes: model.Scopes{model.ScopeAdmin}}, WriteNotes, false},
+		{"unknown role gets nothing", auth.Principal{Role: "guest", Scopes: model.Scopes{model.ScopeAdmin}}, ReadTasks, false},
+		{"empty principal gets nothing", auth.Principal{}, ReadTasks, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Authorize(tt.principal, tt.action)
+			if tt.allowed && err != nil {
+				t.Errorf("Authorize() = %v, want allowed", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrForbidden) {
+				t.Errorf("Authorize() = %v, want ErrForbidden", err)
+			}
+		})
+	}
+}
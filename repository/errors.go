@@ -0,0 +1,25 @@
+// Package repository содержит ошибки, общие для всех реализаций хранилищ.
+package repository
+
+import "errors"
+
+var (
+	ErrDatabaseUnavailable = errors.New("database connection not available")
+	ErrTaskNotFound        = errors.New("task not found")
+	ErrParentNotFound      = errors.New("parent task not found")
+	ErrBlockerNotFound     = errors.New("blocking task not found")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserExists          = errors.New("user already exists")
+	ErrTokenNotFound       = errors.New("token not found")
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrNoteNotFound        = errors.New("note not found")
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrAttachmentNotFound  = errors.New("attachment not found")
+	ErrTagNotFound         = errors.New("tag not found")
+	ErrTagExists           = errors.New("tag already exists")
+	ErrWorkspaceNotFound   = errors.New("workspace not found")
+	// ErrOccurrenceExists — следующий экземпляр повторяющейся задачи уже создан.
+	ErrOccurrenceExists = errors.New("next occurrence already exists")
+	// ErrNoWorkspace — запрос к задачам без выбранного рабочего пространства.
+	ErrNoWorkspace = errors.New("workspace is not selected")
+)
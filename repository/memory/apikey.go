@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"sync"
+	"time"
+)
+
+type APIKeyRepository struct {
+	mu     sync.Mutex
+	keys   map[int]entity.APIKeyEntity
+	nextID int
+	now    func() time.Time
+}
+
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		keys:   make(map[int]entity.APIKeyEntity),
+		nextID: 1,
+		now:    time.Now,
+	}
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key model.APIKey) (entity.APIKeyEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scopes := make([]string, len(key.Scopes))
+	for i, s := range key.Scopes {
+		scopes[i] = string(s)
+	}
+
+	e := entity.APIKeyEntity{
+		ID:          r.nextID,
+		UserID:      key.UserID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Hash:        key.Hash,
+		Scopes:      scopes,
+		WorkspaceID: key.WorkspaceID,
+		ExpiresAt:   key.ExpiresAt,
+		CreatedAt:   r.now(),
+	}
+	r.keys[e.ID] = e
+	r.nextID++
+	return e, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (entity.APIKeyEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			return key, nil
+		}
+	}
+	return entity.APIKeyEntity{}, repository.ErrAPIKeyNotFound
+}
+
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context, userID int) ([]entity.APIKeyEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := []entity.APIKeyEntity{}
+	for _, key := range r.keys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b entity.APIKeyEntity) int { return a.ID - b.ID })
+	return keys, nil
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, userID, id int) (entity.APIKeyEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok || key.UserID != userID {
+		return entity.APIKeyEntity{}, repository.ErrAPIKeyNotFound
+	}
+	if key.RevokedAt == nil {
+		now := r.now()
+		key.RevokedAt = &now
+		r.keys[id] = key
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, id int, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key, ok := r.keys[id]; ok {
+		key.LastUsedAt = &at
+		r.keys[id] = key
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"sync"
+	"time"
+)
+
+type AttachmentRepository struct {
+	mu          sync.RWMutex
+	attachments map[int]entity.AttachmentEntity
+	nextID      int
+	now         func() time.Time
+}
+
+func NewAttachmentRepository() *AttachmentRepository {
+	return &AttachmentRepository{
+		attachments: make(map[int]entity.AttachmentEntity),
+		nextID:      1,
+		now:         time.Now,
+	}
+}
+
+func (r *AttachmentRepository) ListAttachments(ctx context.Context, taskID int) ([]entity.AttachmentEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var attachments []entity.AttachmentEntity
+	for _, a := range r.attachments {
+		if a.TaskID == taskID && a.WorkspaceID == ws {
+			attachments = append(attachments, a)
+		}
+	}
+	slices.SortFunc(attachments, func(a, b entity.AttachmentEntity) int { return cmp.Compare(a.ID, b.ID) })
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) GetAttachment(ctx context.Context, taskID, id int) (entity.AttachmentEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.lookup(ctx, taskID, id)
+}
+
+func (r *AttachmentRepository) CreateAttachment(ctx context.Context, attachment model.Attachment) (entity.AttachmentEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.AttachmentEntity{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e := *entity.AttachmentFromModel(&attachment)
+	e.ID = r.nextID
+	e.WorkspaceID = ws
+	e.CreatedAt = r.now()
+	r.attachments[e.ID] = e
+	r.nextID++
+	return e, nil
+}
+
+func (r *AttachmentRepository) DeleteAttachment(ctx context.Context, taskID, id int) (entity.AttachmentEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, err := r.lookup(ctx, taskID, id)
+	if err != nil {
+		return entity.AttachmentEntity{}, err
+	}
+	delete(r.attachments, id)
+	return e, nil
+}
+
+func (r *AttachmentRepository) lookup(ctx context.Context, taskID, id int) (entity.AttachmentEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.AttachmentEntity{}, err
+	}
+	e, ok := r.attachments[id]
+	if !ok || e.TaskID != taskID || e.WorkspaceID != ws {
+		return entity.AttachmentEntity{}, repository.ErrAttachmentNotFound
+	}
+	return e, nil
+}
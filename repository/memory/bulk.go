@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+)
+
+// ApplyBulk применяет подготовленные изменения под одной блокировкой. Задачи проверяются
+// до первой записи, поэтому в атомарном режиме неудача не оставляет частичных изменений.
+func (t *TaskRepository) ApplyBulk(ctx context.Context, items []model.BulkItem, atomic bool) ([]entity.BulkResultEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	results := make([]entity.BulkResultEntity, len(items))
+	failed := false
+	for i, item := range items {
+		e, ok := t.lookup(ctx, item.Task.ID)
+		switch {
+		case !ok || e.DeletedAt != nil:
+			results[i].Err = repository.ErrTaskNotFound
+		case e.Version != item.Task.Version:
+			results[i].Err = model.ErrVersionMismatch
+		}
+		failed = failed || results[i].Err != nil
+	}
+	if failed && atomic {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = model.ErrBulkAborted
+			}
+		}
+		return results, nil
+	}
+
+	now := t.now()
+	// Предков и зависимые задачи меняем после всех задач: их версии уже сверены выше.
+	var (
+		parents  []*int
+		blockers []int
+	)
+	for i, item := range items {
+		if results[i].Err != nil {
+			continue
+		}
+		e := t.tasks[item.Task.ID]
+		before := e
+		if item.Delete {
+			e.DeletedAt = &now
+		} else {
+			for _, name := range item.AddTags {
+				if _, ok := t.tagByName(ws, name); !ok {
+					t.createTag(ws, name)
+				}
+			}
+			e.Status = string(item.Task.Status)
+			e.StartedAt = item.Task.StartedAt
+			e.CompletedAt = item.Task.CompletedAt
+			e.Priority = item.Task.Priority
+			e.Tags = item.Task.Tags
+			e.UpdatedAt = now
+		}
+		e.Version++
+		t.tasks[e.ID] = e
+		t.record(ctx, item.Action, &before, &e)
+		if item.Delete || completionChanged(before.Status, e.Status) {
+			parents = append(parents, e.ParentID)
+			blockers = append(blockers, e.ID)
+		}
+	}
+	t.bumpAncestors(parents...)
+	t.bumpDependents(blockers...)
+	for i := range items {
+		if results[i].Err == nil {
+			results[i].Task = t.withComputed(t.tasks[items[i].Task.ID])
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"errors"
+	"myApi/model"
+	"testing"
+)
+
+func TestApplyBulkStaleVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		atomic   bool
+		errs     []error
+		priority []int
+	}{
+		{"atomic writes nothing", true, []error{model.ErrBulkAborted, model.ErrVersionMismatch}, []int{3, 3}},
+		{"best effort writes the fresh task", false, []error{nil, model.ErrVersionMismatch}, []int{1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, ctx := newTestRepo(t)
+			repo.Seed([]model.Task{{ID: 1, Title: "Fresh"}, {ID: 2, Title: "Stale", Version: 2}})
+
+			// Изменения рассчитаны на версии 1; вторую задачу уже успели поменять.
+			items := []model.BulkItem{
+				{Task: model.Task{ID: 1, Title: "Fresh", Priority: 1, Version: 1}, Action: model.AuditPatch},
+				{Task: model.Task{ID: 2, Title: "Stale", Priority: 1, Version: 1}, Action: model.AuditPatch},
+			}
+			results, err := repo.ApplyBulk(ctx, items, tt.atomic)
+			if err != nil {
+				t.Fatalf("ApplyBulk() = %v", err)
+			}
+			for i, r := range results {
+				if !errors.Is(r.Err, tt.errs[i]) {
+					t.Errorf("result %d err = %v, want %v", i, r.Err, tt.errs[i])
+				}
+			}
+			for i, want := range tt.priority {
+				task, err := repo.GetTaskById(ctx, i+1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if task.Priority != want {
+					t.Errorf("task %d priority = %d, want %d", task.ID, task.Priority, want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyBulkCompletesParentAndChild(t *testing.T) {
+	repo, ctx := newTreeRepo(t)
+	// Ребёнок идёт раньше родителя: новая версия предка не должна сорвать сверку родителя.
+	items := []model.BulkItem{
+		{Task: model.Task{ID: 2, Title: "Child", Status: model.StatusCompleted, Priority: 3, Version: 1}, Action: model.AuditStatus},
+		{Task: model.Task{ID: 1, Title: "Root", Status: model.StatusCompleted, Priority: 3, Version: 1}, Action: model.AuditStatus},
+	}
+	results, err := repo.ApplyBulk(ctx, items, true)
+	if err != nil {
+		t.Fatalf("ApplyBulk() = %v", err)
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Fatalf("result %d err = %v", i, r.Err)
+		}
+	}
+	// Корень изменён сам и как предок; в ответе — его текущая версия.
+	root, err := repo.GetTaskById(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[1].Task.Version != root.Version || root.Version != 3 {
+		t.Errorf("root version in result %d, stored %d, want 3", results[1].Task.Version, root.Version)
+	}
+	if got := root.ToModel().Subtasks; got != (model.SubtaskProgress{Total: 3, Completed: 1}) {
+		t.Errorf("root progress = %+v, want 1/3", got)
+	}
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"sync"
+	"time"
+)
+
+type CommentRepository struct {
+	mu         sync.RWMutex
+	comments   map[int]entity.CommentEntity
+	edits      []entity.CommentEditEntity
+	nextID     int
+	nextEditID int
+	now        func() time.Time
+}
+
+func NewCommentRepository() *CommentRepository {
+	return &CommentRepository{
+		comments:   make(map[int]entity.CommentEntity),
+		nextID:     1,
+		nextEditID: 1,
+		now:        time.Now,
+	}
+}
+
+func (r *CommentRepository) ListComments(ctx context.Context, taskID int) ([]entity.CommentEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var comments []entity.CommentEntity
+	for _, c := range r.comments {
+		if c.TaskID == taskID && c.WorkspaceID == ws {
+			comments = append(comments, c)
+		}
+	}
+	slices.SortFunc(comments, func(a, b entity.CommentEntity) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return comments, nil
+}
+
+func (r *CommentRepository) GetComment(ctx context.Context, taskID, id int) (entity.CommentEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.lookup(ctx, taskID, id)
+}
+
+func (r *CommentRepository) CreateComment(ctx context.Context, comment model.Comment) (entity.CommentEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.CommentEntity{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e := *entity.CommentFromModel(&comment)
+	e.ID = r.nextID
+	e.WorkspaceID = ws
+	e.CreatedAt = r.now()
+	e.EditedAt = nil
+	e.DeletedAt = nil
+	r.comments[e.ID] = e
+	r.nextID++
+	return e, nil
+}
+
+func (r *CommentRepository) EditComment(ctx context.Context, taskID, id int, body string, editedBy *int) (entity.CommentEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, err := r.lookup(ctx, taskID, id)
+	if err != nil {
+		return entity.CommentEntity{}, err
+	}
+	if e.DeletedAt != nil {
+		return entity.CommentEntity{}, repository.ErrCommentNotFound
+	}
+
+	now := r.now()
+	r.edits = append(r.edits, entity.CommentEditEntity{
+		ID:        r.nextEditID,
+		CommentID: id,
+		Body:      e.Body,
+		EditedBy:  editedBy,
+		EditedAt:  now,
+	})
+	r.nextEditID++
+	e.Body = body
+	e.EditedAt = &now
+	r.comments[id] = e
+	return e, nil
+}
+
+func (r *CommentRepository) DeleteComment(ctx context.Context, taskID, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, err := r.lookup(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+	if e.DeletedAt != nil {
+		return repository.ErrCommentNotFound
+	}
+	now := r.now()
+	e.Body = ""
+	e.DeletedAt = &now
+	r.comments[id] = e
+	r.edits = slices.DeleteFunc(r.edits, func(edit entity.CommentEditEntity) bool { return edit.CommentID == id })
+	return nil
+}
+
+func (r *CommentRepository) GetCommentEdits(ctx context.Context, taskID, id int) ([]entity.CommentEditEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, err := r.lookup(ctx, taskID, id); err != nil {
+		return nil, err
+	}
+	var edits []entity.CommentEditEntity
+	for _, edit := range r.edits {
+		if edit.CommentID == id {
+			edits = append(edits, edit)
+		}
+	}
+	return edits, nil
+}
+
+func (r *CommentRepository) lookup(ctx context.Context, taskID, id int) (entity.CommentEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.CommentEntity{}, err
+	}
+	e, ok := r.comments[id]
+	if !ok || e.TaskID != taskID || e.WorkspaceID != ws {
+		return entity.CommentEntity{}, repository.ErrCommentNotFound
+	}
+	return e, nil
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+)
+
+func (t *TaskRepository) AddDependency(ctx context.Context, id, blockerID, version int) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil || (version != 0 && version != e.Version) {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	if blocker, ok := t.lookup(ctx, blockerID); !ok || blocker.DeletedAt != nil {
+		return entity.TaskEntity{}, repository.ErrBlockerNotFound
+	}
+	if t.dependsOn(blockerID, id) {
+		return entity.TaskEntity{}, model.ErrDependencyCycle
+	}
+
+	blockedBy := append(slices.Clone(e.BlockedBy), blockerID)
+	slices.Sort(blockedBy)
+	return t.setBlockers(ctx, e, slices.Compact(blockedBy)), nil
+}
+
+func (t *TaskRepository) RemoveDependency(ctx context.Context, id, blockerID, version int) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil || (version != 0 && version != e.Version) {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	blockedBy := slices.DeleteFunc(slices.Clone(e.BlockedBy), func(b int) bool { return b == blockerID })
+	return t.setBlockers(ctx, e, blockedBy), nil
+}
+
+func (t *TaskRepository) GetDependencies(ctx context.Context, id int) (blockers, dependents []entity.TaskEntity, err error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil {
+		return nil, nil, repository.ErrTaskNotFound
+	}
+	for _, b := range e.BlockedBy {
+		if blocker, ok := t.tasks[b]; ok && blocker.DeletedAt == nil {
+			blockers = append(blockers, blocker)
+		}
+	}
+	for _, other := range t.tasks {
+		if other.DeletedAt == nil && slices.Contains(other.BlockedBy, id) {
+			dependents = append(dependents, other)
+		}
+	}
+	slices.SortFunc(dependents, func(a, b entity.TaskEntity) int { return a.ID - b.ID })
+	t.fillComputed(blockers)
+	t.fillComputed(dependents)
+	return blockers, dependents, nil
+}
+
+func (t *TaskRepository) GetOpenTasks(ctx context.Context) ([]entity.TaskEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var tasks []entity.TaskEntity
+	for _, e := range t.tasks {
+		if e.WorkspaceID == ws && e.DeletedAt == nil && e.Status != string(model.StatusCompleted) {
+			tasks = append(tasks, e)
+		}
+	}
+	slices.SortFunc(tasks, func(a, b entity.TaskEntity) int { return a.ID - b.ID })
+	t.fillComputed(tasks)
+	return tasks, nil
+}
+
+// dependsOn сообщает, ждёт ли задача id задачу blockerID, прямо или через другие задачи.
+// Задачи в корзине учитываются, как и в postgresql.
+func (t *TaskRepository) dependsOn(id, blockerID int) bool {
+	seen := map[int]bool{}
+	for queue := []int{id}; len(queue) > 0; queue = queue[1:] {
+		current := queue[0]
+		if current == blockerID {
+			return true
+		}
+		if seen[current] {
+			continue
+		}
+		seen[current] = true
+		queue = append(queue, t.tasks[current].BlockedBy...)
+	}
+	return false
+}
+
+// bumpDependents увеличивает версию задач, которые ждут одну из blockerIDs: их открытые
+// блокеры вычисляемые, а ETag зависит только от версии. Вызывается под t.mu в том же
+// изменении, что и блокер.
+func (t *TaskRepository) bumpDependents(blockerIDs ...int) {
+	for id, e := range t.tasks {
+		if slices.ContainsFunc(e.BlockedBy, func(b int) bool { return slices.Contains(blockerIDs, b) }) {
+			e.Version++
+			t.tasks[id] = e
+		}
+	}
+}
+
+// setBlockers сохраняет новый список блокеров с новой версией и записью в истории.
+func (t *TaskRepository) setBlockers(ctx context.Context, e entity.TaskEntity, blockedBy []int) entity.TaskEntity {
+	before := e
+	e.BlockedBy = blockedBy
+	e.Version++
+	e.UpdatedAt = t.now()
+	t.tasks[e.ID] = e
+	t.record(ctx, model.AuditDepend, &before, &e)
+	return t.withComputed(e)
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"myApi/model"
+	"testing"
+)
+
+func TestBlockerChangesBumpDependents(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{{ID: 1, Title: "Blocker"}, {ID: 2, Title: "Waits"}, {ID: 3, Title: "Also waits"}, {ID: 4, Title: "Unrelated"}})
+	for _, id := range []int{2, 3} {
+		if _, err := repo.AddDependency(ctx, id, 1, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	openBlockers := func(id int) []int {
+		t.Helper()
+		e, err := repo.GetTaskById(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e.OpenBlockers
+	}
+
+	// Начало работы не меняет открытые блокеры.
+	before := versions(repo)
+	setStatus(t, repo, ctx, 1, model.StatusInProgress)
+	checkBumped(t, before, versions(repo), 1)
+
+	steps := []struct {
+		name   string
+		change func() error
+		open   bool
+	}{
+		{"complete", func() error { setStatus(t, repo, ctx, 1, model.StatusCompleted); return nil }, false},
+		{"reopen", func() error { setStatus(t, repo, ctx, 1, model.StatusPending); return nil }, true},
+		{"trash", func() error { return repo.DeleteTask(ctx, 1) }, false},
+		{"restore", func() error { _, err := repo.RestoreTask(ctx, 1); return err }, true},
+	}
+	for _, step := range steps {
+		before := versions(repo)
+		if err := step.change(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		checkBumped(t, before, versions(repo), 1, 2, 3)
+		if got := openBlockers(2); (len(got) == 1) != step.open {
+			t.Errorf("%s: open blockers = %v, want blocked: %v", step.name, got, step.open)
+		}
+	}
+
+	if err := repo.DeleteTask(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	before = versions(repo)
+	if err := repo.PurgeTask(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	delete(before, 1)
+	checkBumped(t, before, versions(repo), 2, 3)
+	if e, _ := repo.GetTaskById(ctx, 3); len(e.BlockedBy) != 0 {
+		t.Errorf("blocked_by after purge = %v, want none", e.BlockedBy)
+	}
+}
+
+func TestApplyBulkBumpsDependents(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{{ID: 1, Title: "Blocker", Status: model.StatusInProgress}, {ID: 2, Title: "Waits"}})
+	if _, err := repo.AddDependency(ctx, 2, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Зависимая задача идёт в той же операции после блокера и должна пройти сверку версии.
+	items := []model.BulkItem{
+		{Task: model.Task{ID: 1, Title: "Blocker", Status: model.StatusCompleted, Priority: 3, Version: 1}, Action: model.AuditStatus},
+		{Task: model.Task{ID: 2, Title: "Waits", Status: model.StatusPending, Priority: 1, Version: 2}, Action: model.AuditPatch},
+	}
+	results, err := repo.ApplyBulk(ctx, items, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Fatalf("result %d err = %v", i, r.Err)
+		}
+	}
+	if got := results[1].Task; got.Version != 4 || len(got.OpenBlockers) != 0 {
+		t.Errorf("dependent in result: version %d, open blockers %v; want 4 and none", got.Version, got.OpenBlockers)
+	}
+}
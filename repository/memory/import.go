@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+)
+
+// ImportTasks сначала читает все пачки, затем добавляет задачи под одной блокировкой,
+// чтобы ошибка чтения не оставила импорт наполовину сделанным.
+func (t *TaskRepository) ImportTasks(ctx context.Context, next func() ([]model.Task, error)) (int, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var tasks []model.Task
+	for {
+		batch, err := next()
+		if err != nil {
+			return 0, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		tasks = append(tasks, batch...)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i := range tasks {
+		e := *entity.FromModel(&tasks[i])
+		e.ID = t.nextID
+		e.WorkspaceID = ws
+		e.DeletedAt = nil
+		e.Version = 1
+		for _, name := range e.Tags {
+			if _, ok := t.tagByName(ws, name); !ok {
+				t.createTag(ws, name)
+			}
+		}
+		t.tasks[e.ID] = e
+		t.nextID++
+		t.record(ctx, model.AuditImport, nil, &e)
+	}
+
+	t.logger.Info("Tasks imported", "count", len(tasks))
+	return len(tasks), nil
+}
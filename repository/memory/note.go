@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"sync"
+	"time"
+)
+
+type NoteRepository struct {
+	mu     sync.RWMutex
+	notes  map[int]entity.NoteEntity
+	nextID int
+	now    func() time.Time
+}
+
+func NewNoteRepository() *NoteRepository {
+	return &NoteRepository{
+		notes:  make(map[int]entity.NoteEntity),
+		nextID: 1,
+		now:    time.Now,
+	}
+}
+
+func (r *NoteRepository) ListNotes(ctx context.Context, filter model.NoteFilter) (entity.NotePage, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.NotePage{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := []entity.NoteEntity{}
+	for _, n := range r.notes {
+		if n.WorkspaceID != ws {
+			continue
+		}
+		if filter.TaskID != nil && (n.TaskID == nil || *n.TaskID != *filter.TaskID) {
+			continue
+		}
+		matched = append(matched, n)
+	}
+	slices.SortFunc(matched, func(a, b entity.NoteEntity) int {
+		return cmp.Or(b.UpdatedAt.Compare(a.UpdatedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	page := entity.NotePage{Notes: []entity.NoteEntity{}, Total: len(matched)}
+	if filter.Offset < len(matched) {
+		end := min(filter.Offset+filter.Limit, len(matched))
+		page.Notes = append(page.Notes, matched[filter.Offset:end]...)
+	}
+	return page, nil
+}
+
+func (r *NoteRepository) GetNote(ctx context.Context, id int) (entity.NoteEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.lookup(ctx, id)
+}
+
+func (r *NoteRepository) CreateNote(ctx context.Context, note model.Note) (entity.NoteEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.NoteEntity{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	e := *entity.NoteFromModel(&note)
+	e.ID = r.nextID
+	e.WorkspaceID = ws
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	r.notes[e.ID] = e
+	r.nextID++
+	return e, nil
+}
+
+func (r *NoteRepository) UpdateNote(ctx context.Context, note model.Note) (entity.NoteEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, err := r.lookup(ctx, note.ID)
+	if err != nil {
+		return entity.NoteEntity{}, err
+	}
+	e.Title = note.Title
+	e.Body = note.Body
+	e.TaskID = note.TaskID
+	e.UpdatedAt = r.now()
+	r.notes[e.ID] = e
+	return e, nil
+}
+
+func (r *NoteRepository) DeleteNote(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.lookup(ctx, id); err != nil {
+		return err
+	}
+	delete(r.notes, id)
+	return nil
+}
+
+func (r *NoteRepository) lookup(ctx context.Context, id int) (entity.NoteEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.NoteEntity{}, err
+	}
+	e, ok := r.notes[id]
+	if !ok || e.WorkspaceID != ws {
+		return entity.NoteEntity{}, repository.ErrNoteNotFound
+	}
+	return e, nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"time"
+)
+
+func (t *TaskRepository) ScheduleTask(ctx context.Context, id int, schedule model.TaskSchedule, version int) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil || (version != 0 && version != e.Version) {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+
+	before := e
+	if !sameTime(e.DueAt, schedule.DueAt) {
+		e.RemindedAt = nil
+	}
+	e.StartAt = schedule.StartAt
+	e.DueAt = schedule.DueAt
+	e.Timezone = schedule.Timezone
+	e.Recurrence = schedule.Recurrence
+	e.Version++
+	e.UpdatedAt = t.now()
+	t.tasks[id] = e
+	t.record(ctx, model.AuditSchedule, &before, &e)
+	return t.withComputed(e), nil
+}
+
+// ClaimDueReminders отмечает напоминание отправленным для живых незавершённых задач всех
+// пространств со сроком в (from, to], у которых его ещё не было, и возвращает их по сроку.
+func (t *TaskRepository) ClaimDueReminders(ctx context.Context, from, to time.Time, limit int) ([]entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var tasks []entity.TaskEntity
+	for _, e := range t.tasks {
+		if e.DeletedAt == nil && e.Status != string(model.StatusCompleted) && e.RemindedAt == nil &&
+			e.DueAt != nil && e.DueAt.After(from) && !e.DueAt.After(to) {
+			tasks = append(tasks, e)
+		}
+	}
+	slices.SortFunc(tasks, func(a, b entity.TaskEntity) int {
+		if c := a.DueAt.Compare(*b.DueAt); c != 0 {
+			return c
+		}
+		return a.ID - b.ID
+	})
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	now := t.now()
+	for i := range tasks {
+		tasks[i].RemindedAt = &now
+		t.tasks[tasks[i].ID] = tasks[i]
+	}
+	t.fillComputed(tasks)
+	return tasks, nil
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func (t *TaskRepository) CreateOccurrence(ctx context.Context, prevID int, task model.Task) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, ok := t.lookup(ctx, prevID)
+	if !ok {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	for _, e := range t.tasks {
+		if e.RecurredFrom != nil && *e.RecurredFrom == prevID {
+			return entity.TaskEntity{}, repository.ErrOccurrenceExists
+		}
+	}
+
+	now := t.now()
+	e := *entity.FromModel(&task)
+	e.ID = t.nextID
+	e.WorkspaceID = prev.WorkspaceID
+	e.Tags = slices.Clone(prev.Tags)
+	e.RecurredFrom = &prev.ID
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	e.Version = 1
+	t.tasks[e.ID] = e
+	t.nextID++
+	t.record(ctx, model.AuditRecur, nil, &e)
+	t.bumpAncestors(e.ParentID)
+
+	t.logger.Info("Next occurrence created", "task_id", e.ID, "recurred_from", prevID)
+	return t.withComputed(e), nil
+}
@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// snippetWords — длина фрагмента описания в словах, как MaxWords у ts_headline.
+	snippetWords = 25
+	// trigramThreshold — минимальное сходство слова с опечаткой, как у pg_trgm.
+	trigramThreshold = 0.5
+)
+
+// SearchTasks — упрощённый поиск для работы без PostgreSQL. Слово запроса совпадает со
+// словом задачи, если у них общее начало (грубая замена стемминга), а с Fuzzy — ещё и при
+// близких триграммах. Найтись должны все слова; операторы websearch не поддерживаются.
+func (t *TaskRepository) SearchTasks(ctx context.Context, search model.TaskSearch) (entity.SearchPage, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.SearchPage{}, err
+	}
+	terms := searchWords(search.Query)
+
+	page := entity.SearchPage{Hits: []entity.SearchHitEntity{}}
+	if len(terms) == 0 {
+		return page, nil
+	}
+
+	t.mu.RLock()
+	var (
+		tasks []entity.TaskEntity
+		hits  []entity.SearchHitEntity
+	)
+	for _, task := range t.tasks {
+		if task.WorkspaceID != ws || task.DeletedAt != nil {
+			continue
+		}
+		title, titleScores := matchText(task.Title, terms, search.Fuzzy)
+		desc, descScores := matchText(task.Description, terms, search.Fuzzy)
+		rank := 0.0
+		for i := range terms {
+			score := max(titleScores[i], 0.4*descScores[i])
+			if score == 0 {
+				rank = 0
+				break
+			}
+			rank += score
+		}
+		if rank == 0 {
+			continue
+		}
+		tasks = append(tasks, task)
+		hits = append(hits, entity.SearchHitEntity{
+			Rank:    rank / float64(len(terms)),
+			Title:   title.render(0, len(title.words)),
+			Snippet: desc.snippet(),
+		})
+	}
+	t.fillComputed(tasks)
+	t.mu.RUnlock()
+
+	for i := range hits {
+		hits[i].Task = tasks[i]
+	}
+	slices.SortFunc(hits, func(a, b entity.SearchHitEntity) int {
+		return cmp.Or(cmp.Compare(b.Rank, a.Rank), cmp.Compare(a.Task.ID, b.Task.ID))
+	})
+
+	page.Total = len(hits)
+	if search.Offset < len(hits) {
+		page.Hits = hits[search.Offset:min(search.Offset+search.Limit, len(hits))]
+	}
+	return page, nil
+}
+
+func searchWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// markedText — текст, разбитый на слова, с отметками совпавших слов.
+type markedText struct {
+	text  string
+	words []wordSpan
+}
+
+type wordSpan struct {
+	start, end int
+	hit        bool
+}
+
+// matchText отмечает в text слова, совпавшие с terms. scores[i] — 1 за точное совпадение
+// i-го слова запроса, 0.3 за совпадение с опечаткой, 0 — слово не нашлось.
+func matchText(text string, terms []string, fuzzy bool) (markedText, []float64) {
+	m := markedText{text: text}
+	start := -1
+	for i, r := range text + " " {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			m.words = append(m.words, wordSpan{start: start, end: i})
+			start = -1
+		}
+	}
+
+	scores := make([]float64, len(terms))
+	for w := range m.words {
+		word := strings.ToLower(text[m.words[w].start:m.words[w].end])
+		for i, term := range terms {
+			score := 0.0
+			switch {
+			case sameStem([]rune(term), []rune(word)):
+				score = 1
+			case fuzzy && trigramSimilarity(term, word) >= trigramThreshold:
+				score = 0.3
+			}
+			if score > 0 {
+				m.words[w].hit = true
+				scores[i] = max(scores[i], score)
+			}
+		}
+	}
+	return m, scores
+}
+
+// render возвращает текст слов [from, to) с маркерами вокруг совпадений.
+func (m markedText) render(from, to int) string {
+	if len(m.words) == 0 {
+		return m.text
+	}
+	if from >= to {
+		return ""
+	}
+	var b strings.Builder
+	pos := m.words[from].start
+	if from == 0 {
+		pos = 0
+	}
+	for _, w := range m.words[from:to] {
+		b.WriteString(m.text[pos:w.start])
+		if w.hit {
+			b.WriteString(model.HighlightStart + m.text[w.start:w.end] + model.HighlightStop)
+		} else {
+			b.WriteString(m.text[w.start:w.end])
+		}
+		pos = w.end
+	}
+	if to == len(m.words) {
+		b.WriteString(m.text[pos:])
+	}
+	return b.String()
+}
+
+// snippet — фрагмент вокруг первого совпадения, без совпадений — начало текста.
+func (m markedText) snippet() string {
+	first := slices.IndexFunc(m.words, func(w wordSpan) bool { return w.hit })
+	from := max(first-snippetWords/3, 0)
+	to := min(from+snippetWords, len(m.words))
+	return strings.TrimSpace(m.render(from, to))
+}
+
+// sameStem сравнивает слова по общему началу: слово запроса может быть началом слова
+// задачи («crash» и «crashes»), а слова от четырёх букв — расходиться в окончаниях
+// до двух букв («задача» и «задачи»).
+func sameStem(term, word []rune) bool {
+	n := 0
+	for n < len(term) && n < len(word) && term[n] == word[n] {
+		n++
+	}
+	if n == len(term) {
+		return len(word)-n <= 3
+	}
+	return n >= 4 && len(term)-n <= 2 && len(word)-n <= 2
+}
+
+// trigramSimilarity считает сходство как pg_trgm similarity: доля общих триграмм
+// слов, дополненных двумя пробелами в начале и одним в конце.
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	common := 0
+	for g := range ta {
+		if tb[g] {
+			common++
+		}
+	}
+	total := len(ta) + len(tb) - common
+	if total == 0 {
+		return 0
+	}
+	return float64(common) / float64(total)
+}
+
+func trigrams(s string) map[string]bool {
+	r := []rune("  " + s + " ")
+	set := make(map[string]bool, len(r))
+	for i := 0; i+3 <= len(r); i++ {
+		set[string(r[i:i+3])] = true
+	}
+	return set
+}
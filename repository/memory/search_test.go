@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"myApi/model"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// searchHits выполняет поиск с подставленными значениями по умолчанию, как сервис, и
+// возвращает ID найденных задач и заголовки с описаниями, где совпадения размечены <mark>.
+func searchHits(t *testing.T, repo *TaskRepository, ctx context.Context, search model.TaskSearch) (ids []int, titles, snippets []string) {
+	t.Helper()
+	if err := search.Normalize(); err != nil {
+		t.Fatalf("Normalize() = %v", err)
+	}
+	page, err := repo.SearchTasks(ctx, search)
+	if err != nil {
+		t.Fatalf("SearchTasks(%q) = %v", search.Query, err)
+	}
+	ids = []int{}
+	for _, hit := range page.Hits {
+		ids = append(ids, hit.Task.ID)
+		titles = append(titles, model.RenderHighlight(hit.Title))
+		snippets = append(snippets, model.RenderHighlight(hit.Snippet))
+	}
+	return ids, titles, snippets
+}
+
+func newSearchRepo(t *testing.T) (*TaskRepository, context.Context) {
+	t.Helper()
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{
+		{ID: 1, Title: "Login crash", Description: "App crashes on login when the password is empty."},
+		{ID: 2, Title: "Fix typo", Description: "The login page shows a crash dialog."},
+		{ID: 3, Title: "Crash reporter"},
+		{ID: 4, Title: "Trashed crash"},
+		{ID: 5, Title: "Crash elsewhere", WorkspaceID: model.DefaultWorkspaceID + 1},
+		{ID: 6, Title: "Dashboard widgets"},
+		{ID: 7, Title: "Broken dashbord", Description: "Charts <b>flicker</b> & vanish."},
+	})
+	if err := repo.DeleteTask(ctx, 4); err != nil {
+		t.Fatal(err)
+	}
+	return repo, ctx
+}
+
+func TestSearchRanking(t *testing.T) {
+	repo, ctx := newSearchRepo(t)
+	tests := []struct {
+		name  string
+		query string
+		want  []int
+	}{
+		// Совпадение в заголовке весит больше, чем в описании; при равном ранге — по ID.
+		{"title before description", "crash", []int{1, 3, 2}},
+		{"all words must match", "login crash", []int{1, 2}},
+		{"word form", "crashes", []int{1, 3, 2}},
+		{"case and punctuation", "  LOGIN, crash!", []int{1, 2}},
+		{"no match", "deploy", []int{}},
+		{"only separators", "!!!", []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, _ := searchHits(t, repo, ctx, model.TaskSearch{Query: tt.query})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SearchTasks(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	page, err := repo.SearchTasks(ctx, model.TaskSearch{Query: "crash", Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 3 || len(page.Hits) != 1 || page.Hits[0].Task.ID != 3 {
+		t.Errorf("second page = %d hits of %d, want task 3 of 3", len(page.Hits), page.Total)
+	}
+	if page.Hits[0].Rank != 1 {
+		t.Errorf("rank of a title match = %v, want 1", page.Hits[0].Rank)
+	}
+}
+
+func TestSearchHighlights(t *testing.T) {
+	repo, ctx := newSearchRepo(t)
+
+	_, titles, snippets := searchHits(t, repo, ctx, model.TaskSearch{Query: "crash"})
+	wantTitles := []string{"Login <mark>crash</mark>", "<mark>Crash</mark> reporter", "Fix typo"}
+	if !slices.Equal(titles, wantTitles) {
+		t.Errorf("titles = %q, want %q", titles, wantTitles)
+	}
+	wantSnippets := []string{
+		"App <mark>crashes</mark> on login when the password is empty.",
+		"",
+		"The login page shows a <mark>crash</mark> dialog.",
+	}
+	if !slices.Equal(snippets, wantSnippets) {
+		t.Errorf("snippets = %q, want %q", snippets, wantSnippets)
+	}
+
+	// Текст задачи экранируется, разметкой становятся только маркеры совпадений.
+	_, titles, snippets = searchHits(t, repo, ctx, model.TaskSearch{Query: "flicker"})
+	if len(titles) != 1 || titles[0] != "Broken dashbord" ||
+		snippets[0] != "Charts &lt;b&gt;<mark>flicker</mark>&lt;/b&gt; &amp; vanish." {
+		t.Errorf("escaped hit = %q %q", titles, snippets)
+	}
+}
+
+func TestSearchSnippetWindow(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	words := make([]string, 60)
+	for i := range words {
+		words[i] = fmt.Sprintf("w%d", i)
+	}
+	words[40] = "needle"
+	repo.Seed([]model.Task{
+		{ID: 1, Title: "Long text", Description: strings.Join(words, " ")},
+		{ID: 2, Title: "Needle in title", Description: strings.Join(words[:30], " ")},
+	})
+
+	_, _, snippets := searchHits(t, repo, ctx, model.TaskSearch{Query: "needle"})
+	// Фрагмент начинается за треть окна до первого совпадения и занимает snippetWords слов.
+	from := 40 - snippetWords/3
+	want := strings.Join(words[from:from+snippetWords], " ")
+	want = strings.Replace(want, "needle", "<mark>needle</mark>", 1)
+	if snippets[1] != want {
+		t.Errorf("snippet = %q, want %q", snippets[1], want)
+	}
+	// Без совпадений в описании фрагмент — его начало.
+	if want := strings.Join(words[:snippetWords], " "); snippets[0] != want {
+		t.Errorf("snippet without a match = %q, want %q", snippets[0], want)
+	}
+}
+
+func TestSearchFuzzy(t *testing.T) {
+	repo, ctx := newSearchRepo(t)
+	tests := []struct {
+		name  string
+		query string
+		fuzzy bool
+		want  []int
+	}{
+		{"exact only", "dashboard", false, []int{6}},
+		// Точное совпадение ранжируется выше совпадения с опечаткой.
+		{"typo in the task", "dashboard", true, []int{6, 7}},
+		{"typo in the query", "dqshboard", true, []int{6}},
+		{"typo in the query without fuzzy", "dqshboard", false, []int{}},
+		{"too different", "dshbrd", true, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, _ := searchHits(t, repo, ctx, model.TaskSearch{Query: tt.query, Fuzzy: tt.fuzzy})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SearchTasks(%q, fuzzy %v) = %v, want %v", tt.query, tt.fuzzy, got, tt.want)
+			}
+		})
+	}
+
+	page, err := repo.SearchTasks(ctx, model.TaskSearch{Query: "dashboard", Fuzzy: true, Limit: 10})
+	if err != nil || len(page.Hits) != 2 {
+		t.Fatalf("SearchTasks() = %+v, %v", page, err)
+	}
+	if exact, typo := page.Hits[0].Rank, page.Hits[1].Rank; exact <= typo || typo <= 0 {
+		t.Errorf("ranks = %v, %v, want exact above typo", exact, typo)
+	}
+	if got := model.RenderHighlight(page.Hits[1].Title); got != "Broken <mark>dashbord</mark>" {
+		t.Errorf("fuzzy title = %q", got)
+	}
+}
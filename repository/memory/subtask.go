@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+)
+
+func (t *TaskRepository) MoveTask(ctx context.Context, id int, parentID *int, version int) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil || (version != 0 && version != e.Version) {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	if parentID != nil {
+		parent, ok := t.lookup(ctx, *parentID)
+		if !ok || parent.DeletedAt != nil {
+			return entity.TaskEntity{}, repository.ErrParentNotFound
+		}
+		if t.isAncestor(id, parent.ID) {
+			return entity.TaskEntity{}, model.ErrTaskCycle
+		}
+	}
+
+	before := e
+	e.ParentID = parentID
+	e.Version++
+	e.UpdatedAt = t.now()
+	t.tasks[id] = e
+	t.record(ctx, model.AuditMove, &before, &e)
+	t.bumpAncestors(before.ParentID, parentID)
+	return t.withComputed(e), nil
+}
+
+func (t *TaskRepository) GetSubtasks(ctx context.Context, id int) ([]entity.TaskEntity, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if e, ok := t.lookup(ctx, id); !ok || e.DeletedAt != nil {
+		return nil, repository.ErrTaskNotFound
+	}
+	var tasks []entity.TaskEntity
+	for _, e := range t.tasks {
+		if e.ParentID != nil && *e.ParentID == id && e.DeletedAt == nil {
+			tasks = append(tasks, e)
+		}
+	}
+	slices.SortFunc(tasks, func(a, b entity.TaskEntity) int { return a.ID - b.ID })
+	t.fillComputed(tasks)
+	return tasks, nil
+}
+
+// GetTaskTree возвращает корень и его живые подзадачи по уровням, как postgresql.
+func (t *TaskRepository) GetTaskTree(ctx context.Context, id int) ([]entity.TaskEntity, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	root, ok := t.lookup(ctx, id)
+	if !ok || root.DeletedAt != nil {
+		return nil, repository.ErrTaskNotFound
+	}
+	children := t.children()
+	tasks := []entity.TaskEntity{root}
+	for level := []int{id}; len(level) > 0; {
+		var next []int
+		for _, parent := range level {
+			next = append(next, children[parent]...)
+		}
+		slices.Sort(next)
+		for _, child := range next {
+			tasks = append(tasks, t.tasks[child])
+		}
+		level = next
+	}
+	t.fillComputed(tasks)
+	return tasks, nil
+}
+
+// isAncestor сообщает, совпадает ли ancestorID с id или одним из его предков. Как и
+// в postgresql, цепочка проходит и через задачи в корзине.
+func (t *TaskRepository) isAncestor(ancestorID, id int) bool {
+	for steps := 0; steps <= len(t.tasks); steps++ {
+		if id == ancestorID {
+			return true
+		}
+		e, ok := t.tasks[id]
+		if !ok || e.ParentID == nil {
+			return false
+		}
+		id = *e.ParentID
+	}
+	return false
+}
+
+// bumpAncestors увеличивает версию задач parentIDs и всех их предков, каждой один раз.
+// Прогресс подзадач у них вычисляемый, а ETag зависит только от версии, поэтому без этого
+// кэш отдавал бы старый прогресс. updated_at и история не меняются: сама задача та же.
+// Вызывается под t.mu в том же изменении, что и задача-потомок.
+func (t *TaskRepository) bumpAncestors(parentIDs ...*int) {
+	seen := map[int]bool{}
+	for _, parentID := range parentIDs {
+		for id := parentID; id != nil && !seen[*id]; {
+			e, ok := t.tasks[*id]
+			if !ok {
+				break
+			}
+			seen[e.ID] = true
+			e.Version++
+			t.tasks[e.ID] = e
+			id = e.ParentID
+		}
+	}
+}
+
+// withComputed возвращает e с вычисляемыми полями; вызывается под t.mu.
+func (t *TaskRepository) withComputed(e entity.TaskEntity) entity.TaskEntity {
+	tasks := []entity.TaskEntity{e}
+	t.fillComputed(tasks)
+	return tasks[0]
+}
+
+// fillComputed заполняет то, что postgresql считает подзапросами в taskColumns: счётчики
+// живых подзадач всех уровней и открытые блокеры. Вызывается под t.mu.
+func (t *TaskRepository) fillComputed(tasks []entity.TaskEntity) {
+	children := t.children()
+	for i := range tasks {
+		tasks[i].OpenBlockers = nil
+		for _, id := range tasks[i].BlockedBy {
+			if b, ok := t.tasks[id]; ok && b.DeletedAt == nil && b.Status != string(model.StatusCompleted) {
+				tasks[i].OpenBlockers = append(tasks[i].OpenBlockers, id)
+			}
+		}
+
+		total, completed := 0, 0
+		for queue := slices.Clone(children[tasks[i].ID]); len(queue) > 0; queue = queue[1:] {
+			child := t.tasks[queue[0]]
+			total++
+			if child.Status == string(model.StatusCompleted) {
+				completed++
+			}
+			queue = append(queue, children[child.ID]...)
+		}
+		tasks[i].SubtasksTotal, tasks[i].SubtasksCompleted = total, completed
+	}
+}
+
+// children — ID живых подзадач первого уровня по родителю.
+func (t *TaskRepository) children() map[int][]int {
+	children := make(map[int][]int)
+	for _, e := range t.tasks {
+		if e.ParentID != nil && e.DeletedAt == nil {
+			children[*e.ParentID] = append(children[*e.ParentID], e.ID)
+		}
+	}
+	return children
+}
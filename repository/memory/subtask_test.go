@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"testing"
+)
+
+// newTreeRepo строит дерево 1 → {2, 4}, 2 → {3}: задача 3 — внучка задачи 1.
+func newTreeRepo(t *testing.T) (*TaskRepository, context.Context) {
+	t.Helper()
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{
+		{ID: 1, Title: "Root"},
+		{ID: 2, Title: "Child", ParentID: ptr(1)},
+		{ID: 3, Title: "Grandchild", ParentID: ptr(2)},
+		{ID: 4, Title: "Second child", ParentID: ptr(1)},
+	})
+	return repo, ctx
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// setStatus переводит задачу в status, как это делает сервис.
+func setStatus(t *testing.T, repo *TaskRepository, ctx context.Context, id int, status model.TaskStatus) {
+	t.Helper()
+	e, err := repo.GetTaskById(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := e.ToModel()
+	from := task.Status
+	task.Status = status
+	if _, err := repo.UpdateTaskStatus(ctx, *task, from); err != nil {
+		t.Fatalf("UpdateTaskStatus(%d, %s) = %v", id, status, err)
+	}
+}
+
+// versions возвращает текущие версии задач по ID, включая задачи в корзине.
+func versions(repo *TaskRepository) map[int]int {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	v := make(map[int]int, len(repo.tasks))
+	for id, e := range repo.tasks {
+		v[id] = e.Version
+	}
+	return v
+}
+
+// checkBumped проверяет, что версии задач bumped выросли ровно на 1, а остальных не изменились.
+func checkBumped(t *testing.T, before, after map[int]int, bumped ...int) {
+	t.Helper()
+	for id, v := range before {
+		want := v
+		if slices.Contains(bumped, id) {
+			want++
+		}
+		if after[id] != want {
+			t.Errorf("task %d version %d -> %d, want %d", id, v, after[id], want)
+		}
+	}
+}
+
+func progress(t *testing.T, repo *TaskRepository, ctx context.Context, id int) model.SubtaskProgress {
+	t.Helper()
+	e, err := repo.GetTaskById(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e.ToModel().Subtasks
+}
+
+func TestMoveTaskRejectsCycles(t *testing.T) {
+	repo, ctx := newTreeRepo(t)
+	if err := repo.DeleteTask(ctx, 4); err != nil {
+		t.Fatal(err)
+	}
+	before := versions(repo)
+
+	tests := []struct {
+		name     string
+		id       int
+		parentID int
+		wantErr  error
+	}{
+		{"onto itself", 1, 1, model.ErrTaskCycle},
+		{"under its child", 1, 2, model.ErrTaskCycle},
+		{"under its grandchild", 1, 3, model.ErrTaskCycle},
+		{"middle under its child", 2, 3, model.ErrTaskCycle},
+		{"under a missing task", 2, 99, repository.ErrParentNotFound},
+		{"under a trashed task", 2, 4, repository.ErrParentNotFound},
+	}
+	for _, tt := range tests {
+		if _, err := repo.MoveTask(ctx, tt.id, &tt.parentID, 0); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: MoveTask(%d, %d) = %v, want %v", tt.name, tt.id, tt.parentID, err, tt.wantErr)
+		}
+	}
+	// Отклонённые перемещения ничего не меняют.
+	checkBumped(t, before, versions(repo))
+}
+
+func TestTaskTreeThreeLevels(t *testing.T) {
+	repo, ctx := newTreeRepo(t)
+	tree, err := repo.GetTaskTree(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Корень, затем уровни по глубине, внутри уровня по ID.
+	if got := taskIDs(tree); !slices.Equal(got, []int{1, 2, 4, 3}) {
+		t.Errorf("GetTaskTree(1) = %v, want [1 2 4 3]", got)
+	}
+	subtasks, err := repo.GetSubtasks(ctx, 1)
+	if err != nil || !slices.Equal(taskIDs(subtasks), []int{2, 4}) {
+		t.Errorf("GetSubtasks(1) = %v, %v, want [2 4]", taskIDs(subtasks), err)
+	}
+	want := map[int]model.SubtaskProgress{1: {Total: 3}, 2: {Total: 1}, 3: {}, 4: {}}
+	for _, e := range tree {
+		if got := e.ToModel().Subtasks; got != want[e.ID] {
+			t.Errorf("task %d subtasks = %+v, want %+v", e.ID, got, want[e.ID])
+		}
+	}
+}
+
+func TestSubtaskProgressRollsUp(t *testing.T) {
+	repo, ctx := newTreeRepo(t)
+
+	// Начало работы не меняет прогресс, поэтому предки остаются с прежней версией.
+	before := versions(repo)
+	setStatus(t, repo, ctx, 3, model.StatusInProgress)
+	checkBumped(t, before, versions(repo), 3)
+
+	before = versions(repo)
+	setStatus(t, repo, ctx, 3, model.StatusCompleted)
+	checkBumped(t, before, versions(repo), 1, 2, 3)
+	if got := progress(t, repo, ctx, 1); got != (model.SubtaskProgress{Total: 3, Completed: 1}) {
+		t.Errorf("root progress after completing the grandchild = %+v, want 1/3", got)
+	}
+	if got := progress(t, repo, ctx, 2); got != (model.SubtaskProgress{Total: 1, Completed: 1}) {
+		t.Errorf("child progress after completing the grandchild = %+v, want 1/1", got)
+	}
+
+	before = versions(repo)
+	if err := repo.DeleteTask(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	checkBumped(t, before, versions(repo), 1, 2, 3)
+	if got := progress(t, repo, ctx, 1); got != (model.SubtaskProgress{Total: 2}) {
+		t.Errorf("root progress after trashing the grandchild = %+v, want 0/2", got)
+	}
+	if got := progress(t, repo, ctx, 2); got != (model.SubtaskProgress{}) {
+		t.Errorf("child progress after trashing the grandchild = %+v, want 0/0", got)
+	}
+
+	before = versions(repo)
+	if _, err := repo.RestoreTask(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	checkBumped(t, before, versions(repo), 1, 2, 3)
+	if got := progress(t, repo, ctx, 1); got != (model.SubtaskProgress{Total: 3, Completed: 1}) {
+		t.Errorf("root progress after restoring the grandchild = %+v, want 1/3", got)
+	}
+
+	// Повторное открытие тоже меняет прогресс.
+	before = versions(repo)
+	setStatus(t, repo, ctx, 3, model.StatusPending)
+	checkBumped(t, before, versions(repo), 1, 2, 3)
+}
+
+func TestMoveTaskBumpsOldAndNewAncestors(t *testing.T) {
+	repo, ctx := newTreeRepo(t)
+	repo.Seed([]model.Task{{ID: 5, Title: "Other root"}})
+
+	// Общий предок 1 получает новую версию один раз.
+	before := versions(repo)
+	if _, err := repo.MoveTask(ctx, 3, ptr(4), 0); err != nil {
+		t.Fatal(err)
+	}
+	checkBumped(t, before, versions(repo), 1, 2, 3, 4)
+
+	before = versions(repo)
+	if _, err := repo.MoveTask(ctx, 4, ptr(5), 0); err != nil {
+		t.Fatal(err)
+	}
+	checkBumped(t, before, versions(repo), 1, 4, 5)
+	if got := progress(t, repo, ctx, 1); got != (model.SubtaskProgress{Total: 1}) {
+		t.Errorf("old root progress = %+v, want 0/1", got)
+	}
+	if got := progress(t, repo, ctx, 5); got != (model.SubtaskProgress{Total: 2}) {
+		t.Errorf("new root progress = %+v, want 0/2", got)
+	}
+
+	before = versions(repo)
+	if _, err := repo.MoveTask(ctx, 4, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	checkBumped(t, before, versions(repo), 4, 5)
+}
+
+func TestCreateSubtaskBumpsAncestors(t *testing.T) {
+	repo, ctx := newTreeRepo(t)
+	before := versions(repo)
+	created, err := repo.CreateTask(ctx, model.Task{Title: "Great-grandchild", ParentID: ptr(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBumped(t, before, versions(repo), 1, 2, 3)
+	if got := progress(t, repo, ctx, 1); got != (model.SubtaskProgress{Total: 4}) {
+		t.Errorf("root progress = %+v, want 0/4", got)
+	}
+
+	// Удаление из корзины делает подзадачи корневыми и меняет их версию.
+	if err := repo.DeleteTask(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	before = versions(repo)
+	if err := repo.PurgeTask(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	delete(before, 3)
+	checkBumped(t, before, versions(repo), created.ID)
+	if e, _ := repo.GetTaskById(ctx, created.ID); e.ParentID != nil {
+		t.Errorf("parent after purge = %d, want none", *e.ParentID)
+	}
+}
@@ -0,0 +1,198 @@
+package memory
+
+import (
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"strings"
+)
+
+// Теги хранятся в TaskRepository так же, как в postgresql: у задачи — имена тегов,
+// и переименование или слияние переписывает их у всех задач пространства.
+
+func (t *TaskRepository) ListTags(ctx context.Context) ([]entity.TagEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	tags := []entity.TagEntity{}
+	for _, tag := range t.tags {
+		if tag.WorkspaceID != ws {
+			continue
+		}
+		tag.TaskCount = t.countTagged(tag)
+		tags = append(tags, tag)
+	}
+	slices.SortFunc(tags, func(a, b entity.TagEntity) int { return strings.Compare(a.Name, b.Name) })
+	return tags, nil
+}
+
+func (t *TaskRepository) CreateTag(ctx context.Context, name string) (entity.TagEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.TagEntity{}, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.tagByName(ws, name); ok {
+		return entity.TagEntity{}, repository.ErrTagExists
+	}
+	return t.createTag(ws, name), nil
+}
+
+func (t *TaskRepository) RenameTag(ctx context.Context, id int, name string) (entity.TagEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tag, ok := t.lookupTag(ctx, id)
+	if !ok {
+		return entity.TagEntity{}, repository.ErrTagNotFound
+	}
+	if other, ok := t.tagByName(tag.WorkspaceID, name); ok && other.ID != id {
+		return entity.TagEntity{}, repository.ErrTagExists
+	}
+
+	old := tag.Name
+	tag.Name = name
+	t.tags[id] = tag
+	t.retag(ctx, tag.WorkspaceID, old, func(tags []string) []string {
+		return append(removeTags(tags, old), name)
+	})
+	tag.TaskCount = t.countTagged(tag)
+	return tag, nil
+}
+
+func (t *TaskRepository) MergeTags(ctx context.Context, sourceID, targetID int) (entity.TagEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	source, ok := t.lookupTag(ctx, sourceID)
+	if !ok {
+		return entity.TagEntity{}, repository.ErrTagNotFound
+	}
+	target, ok := t.lookupTag(ctx, targetID)
+	if !ok {
+		return entity.TagEntity{}, repository.ErrTagNotFound
+	}
+
+	delete(t.tags, sourceID)
+	t.retag(ctx, source.WorkspaceID, source.Name, func(tags []string) []string {
+		return append(removeTags(tags, source.Name), target.Name)
+	})
+	target.TaskCount = t.countTagged(target)
+	return target, nil
+}
+
+func (t *TaskRepository) DeleteTag(ctx context.Context, id int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tag, ok := t.lookupTag(ctx, id)
+	if !ok {
+		return repository.ErrTagNotFound
+	}
+
+	delete(t.tags, id)
+	t.retag(ctx, tag.WorkspaceID, tag.Name, func(tags []string) []string {
+		return removeTags(tags, tag.Name)
+	})
+	return nil
+}
+
+func (t *TaskRepository) AttachTags(ctx context.Context, id int, names []string, version int) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil || (version != 0 && version != e.Version) {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	for _, name := range names {
+		if _, ok := t.tagByName(e.WorkspaceID, name); !ok {
+			t.createTag(e.WorkspaceID, name)
+		}
+	}
+	return t.setTags(ctx, e, append(removeTags(e.Tags, names...), names...)), nil
+}
+
+func (t *TaskRepository) DetachTags(ctx context.Context, id int, names []string, version int) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil || (version != 0 && version != e.Version) {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	return t.setTags(ctx, e, removeTags(e.Tags, names...)), nil
+}
+
+// retag заменяет теги через change у всех задач пространства с тегом name.
+func (t *TaskRepository) retag(ctx context.Context, ws int, name string, change func([]string) []string) {
+	for _, e := range t.tasks {
+		if e.WorkspaceID == ws && slices.Contains(e.Tags, name) {
+			t.setTags(ctx, e, change(e.Tags))
+		}
+	}
+}
+
+// setTags сохраняет новый список тегов задачи с новой версией и записью в истории.
+func (t *TaskRepository) setTags(ctx context.Context, e entity.TaskEntity, tags []string) entity.TaskEntity {
+	before := e
+	slices.Sort(tags)
+	e.Tags = slices.Compact(tags)
+	e.Version++
+	e.UpdatedAt = t.now()
+	t.tasks[e.ID] = e
+	t.record(ctx, model.AuditTag, &before, &e)
+	return t.withComputed(e)
+}
+
+// countTagged — число живых задач с тегом.
+func (t *TaskRepository) countTagged(tag entity.TagEntity) int {
+	n := 0
+	for _, e := range t.tasks {
+		if e.WorkspaceID == tag.WorkspaceID && e.DeletedAt == nil && slices.Contains(e.Tags, tag.Name) {
+			n++
+		}
+	}
+	return n
+}
+
+func (t *TaskRepository) createTag(ws int, name string) entity.TagEntity {
+	tag := entity.TagEntity{ID: t.nextTagID, WorkspaceID: ws, Name: name, CreatedAt: t.now()}
+	t.tags[tag.ID] = tag
+	t.nextTagID++
+	return tag
+}
+
+func (t *TaskRepository) tagByName(ws int, name string) (entity.TagEntity, bool) {
+	for _, tag := range t.tags {
+		if tag.WorkspaceID == ws && tag.Name == name {
+			return tag, true
+		}
+	}
+	return entity.TagEntity{}, false
+}
+
+func (t *TaskRepository) lookupTag(ctx context.Context, id int) (entity.TagEntity, bool) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.TagEntity{}, false
+	}
+	tag, ok := t.tags[id]
+	return tag, ok && tag.WorkspaceID == ws
+}
+
+// removeTags возвращает копию tags без names; исходный срез не меняется, потому что
+// его разделяют копии задачи в истории.
+func removeTags(tags []string, names ...string) []string {
+	return slices.DeleteFunc(slices.Clone(tags), func(tag string) bool { return slices.Contains(names, tag) })
+}
@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"myApi/reqctx"
+	"slices"
+	"testing"
+)
+
+// newTaggedRepo создаёт задачи 1–4 с тегами через AttachTags, чтобы появились и сами теги.
+func newTaggedRepo(t *testing.T) (*TaskRepository, context.Context) {
+	t.Helper()
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{{ID: 1, Title: "Bug"}, {ID: 2, Title: "Urgent bug"}, {ID: 3, Title: "Feature"}, {ID: 4, Title: "Plain"}})
+	for id, tags := range map[int][]string{1: {"bug"}, 2: {"bug", "urgent"}, 3: {"feature", "urgent"}} {
+		if _, err := repo.AttachTags(ctx, id, tags, 0); err != nil {
+			t.Fatalf("AttachTags(%d) = %v", id, err)
+		}
+	}
+	return repo, ctx
+}
+
+func tagID(t *testing.T, repo *TaskRepository, ctx context.Context, name string) int {
+	t.Helper()
+	tags, err := repo.ListTags(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range tags {
+		if tag.Name == name {
+			return tag.ID
+		}
+	}
+	t.Fatalf("tag %q not found", name)
+	return 0
+}
+
+func taskTags(t *testing.T, repo *TaskRepository, ctx context.Context, id int) entity.TaskEntity {
+	t.Helper()
+	e, err := repo.GetTaskById(ctx, id)
+	if err != nil {
+		t.Fatalf("GetTaskById(%d) = %v", id, err)
+	}
+	return e
+}
+
+func TestListTagsCountsLiveTasks(t *testing.T) {
+	repo, ctx := newTaggedRepo(t)
+	if err := repo.DeleteTask(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	tags, err := repo.ListTags(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int{}
+	var names []string
+	for _, tag := range tags {
+		got[tag.Name] = tag.TaskCount
+		names = append(names, tag.Name)
+	}
+	if !slices.Equal(names, []string{"bug", "feature", "urgent"}) {
+		t.Errorf("ListTags() = %v, want sorted by name", names)
+	}
+	if got["bug"] != 2 || got["feature"] != 0 || got["urgent"] != 1 {
+		t.Errorf("task counts = %v, want bug 2, feature 0, urgent 1", got)
+	}
+	other := reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID+1)
+	if tags, err := repo.ListTags(other); err != nil || len(tags) != 0 {
+		t.Errorf("ListTags(other workspace) = %v, %v", tags, err)
+	}
+}
+
+func TestRenameTag(t *testing.T) {
+	repo, ctx := newTaggedRepo(t)
+	bug := tagID(t, repo, ctx, "bug")
+	before := taskTags(t, repo, ctx, 2)
+
+	if _, err := repo.RenameTag(ctx, bug, "urgent"); !errors.Is(err, repository.ErrTagExists) {
+		t.Errorf("RenameTag() onto an existing name = %v, want ErrTagExists", err)
+	}
+	if _, err := repo.RenameTag(ctx, 999, "defect"); !errors.Is(err, repository.ErrTagNotFound) {
+		t.Errorf("RenameTag() of a missing tag = %v, want ErrTagNotFound", err)
+	}
+	other := reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID+1)
+	if _, err := repo.RenameTag(other, bug, "defect"); !errors.Is(err, repository.ErrTagNotFound) {
+		t.Errorf("RenameTag() from another workspace = %v, want ErrTagNotFound", err)
+	}
+
+	renamed, err := repo.RenameTag(ctx, bug, "defect")
+	if err != nil {
+		t.Fatalf("RenameTag() = %v", err)
+	}
+	if renamed.ID != bug || renamed.Name != "defect" || renamed.TaskCount != 2 {
+		t.Errorf("renamed = %+v", renamed)
+	}
+	after := taskTags(t, repo, ctx, 2)
+	if !slices.Equal(after.Tags, []string{"defect", "urgent"}) {
+		t.Errorf("task tags = %v, want [defect urgent]", after.Tags)
+	}
+	// Задача изменилась, поэтому кэш по версии должен устареть.
+	if after.Version != before.Version+1 {
+		t.Errorf("version = %d, want %d", after.Version, before.Version+1)
+	}
+	if untouched := taskTags(t, repo, ctx, 3); !slices.Equal(untouched.Tags, []string{"feature", "urgent"}) {
+		t.Errorf("untouched task tags = %v", untouched.Tags)
+	}
+	// Повторное переименование в то же имя не конфликтует само с собой.
+	if _, err := repo.RenameTag(ctx, bug, "defect"); err != nil {
+		t.Errorf("RenameTag() to the same name = %v", err)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	repo, ctx := newTaggedRepo(t)
+	bug, urgent := tagID(t, repo, ctx, "bug"), tagID(t, repo, ctx, "urgent")
+	versions := map[int]int{}
+	for id := 1; id <= 4; id++ {
+		versions[id] = taskTags(t, repo, ctx, id).Version
+	}
+
+	if _, err := repo.MergeTags(ctx, bug, 999); !errors.Is(err, repository.ErrTagNotFound) {
+		t.Errorf("MergeTags() into a missing tag = %v, want ErrTagNotFound", err)
+	}
+	// Задача 2 уже с обоими тегами: после слияния тег на ней остаётся один.
+	merged, err := repo.MergeTags(ctx, bug, urgent)
+	if err != nil {
+		t.Fatalf("MergeTags() = %v", err)
+	}
+	if merged.ID != urgent || merged.Name != "urgent" || merged.TaskCount != 3 {
+		t.Errorf("merged = %+v, want urgent on 3 tasks", merged)
+	}
+
+	tests := []struct {
+		id      int
+		tags    []string
+		changed bool
+	}{
+		{1, []string{"urgent"}, true},
+		{2, []string{"urgent"}, true},
+		{3, []string{"feature", "urgent"}, false},
+		{4, nil, false},
+	}
+	for _, tt := range tests {
+		e := taskTags(t, repo, ctx, tt.id)
+		if !slices.Equal(e.Tags, tt.tags) {
+			t.Errorf("task %d tags = %v, want %v", tt.id, e.Tags, tt.tags)
+		}
+		if changed := e.Version != versions[tt.id]; changed != tt.changed {
+			t.Errorf("task %d version %d -> %d, want changed: %v", tt.id, versions[tt.id], e.Version, tt.changed)
+		}
+	}
+	if _, err := repo.RenameTag(ctx, bug, "bug2"); !errors.Is(err, repository.ErrTagNotFound) {
+		t.Errorf("source tag after merge = %v, want ErrTagNotFound", err)
+	}
+	history, err := repo.GetTaskHistory(ctx, 2, 1, 0)
+	if err != nil || len(history) != 1 || history[0].Action != string(model.AuditTag) {
+		t.Errorf("history = %+v, %v, want a tag record", history, err)
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	repo, ctx := newTaggedRepo(t)
+	urgent := tagID(t, repo, ctx, "urgent")
+	before := taskTags(t, repo, ctx, 3)
+
+	if err := repo.DeleteTag(ctx, urgent); err != nil {
+		t.Fatalf("DeleteTag() = %v", err)
+	}
+	if err := repo.DeleteTag(ctx, urgent); !errors.Is(err, repository.ErrTagNotFound) {
+		t.Errorf("DeleteTag() twice = %v, want ErrTagNotFound", err)
+	}
+	after := taskTags(t, repo, ctx, 3)
+	if !slices.Equal(after.Tags, []string{"feature"}) || after.Version != before.Version+1 {
+		t.Errorf("task 3 = tags %v version %d, want [feature] version %d", after.Tags, after.Version, before.Version+1)
+	}
+	if e := taskTags(t, repo, ctx, 2); !slices.Equal(e.Tags, []string{"bug"}) {
+		t.Errorf("task 2 tags = %v, want [bug]", e.Tags)
+	}
+	tags, _ := repo.ListTags(ctx)
+	for _, tag := range tags {
+		if tag.Name == "urgent" {
+			t.Error("deleted tag is still listed")
+		}
+	}
+}
+
+func TestTagFilters(t *testing.T) {
+	repo, ctx := newTaggedRepo(t)
+	tests := []struct {
+		name  string
+		tags  []string
+		match model.TagMatch
+		want  []int
+	}{
+		{"any of one", []string{"bug"}, model.TagMatchAny, []int{1, 2}},
+		{"any of two", []string{"bug", "feature"}, model.TagMatchAny, []int{1, 2, 3}},
+		{"all of one", []string{"urgent"}, model.TagMatchAll, []int{2, 3}},
+		{"all of two", []string{"bug", "urgent"}, model.TagMatchAll, []int{2}},
+		{"all with no match", []string{"bug", "feature"}, model.TagMatchAll, []int{}},
+		{"unknown tag", []string{"missing"}, model.TagMatchAny, []int{}},
+		{"default is any", []string{"feature", "bug"}, "", []int{1, 2, 3}},
+		{"no tags", nil, model.TagMatchAll, []int{1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := normalized(t, model.TaskFilter{Tags: tt.tags, TagMatch: tt.match, SortBy: model.SortByID})
+			page, err := repo.GetAllTasks(ctx, filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := taskIDs(page.Tasks); !slices.Equal(got, tt.want) || page.Total != len(tt.want) {
+				t.Errorf("GetAllTasks() = %v (total %d), want %v", got, page.Total, tt.want)
+			}
+		})
+	}
+}
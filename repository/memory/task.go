@@ -0,0 +1,498 @@
+// Package memory — хранилище задач в памяти процесса для локального запуска и
+// интеграционных тестов без PostgreSQL. Данные теряются при перезапуске.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"myApi/reqctx"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TaskRepository struct {
+	mu        sync.RWMutex
+	tasks     map[int]entity.TaskEntity
+	nextID    int
+	audit     []entity.AuditEntity
+	tags      map[int]entity.TagEntity
+	nextTagID int
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func NewTaskRepository(logger *slog.Logger) *TaskRepository {
+	return &TaskRepository{
+		tasks:     make(map[int]entity.TaskEntity),
+		nextID:    1,
+		tags:      make(map[int]entity.TagEntity),
+		nextTagID: 1,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Seed добавляет задачи как есть, сохраняя их ID. Пустые created_at/updated_at
+// заполняются так, чтобы порядок задач в seed совпадал с порядком по времени создания.
+func (t *TaskRepository) Seed(tasks []model.Task) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	base := t.now().Add(-time.Duration(len(tasks)) * time.Minute)
+	for i, task := range tasks {
+		e := *entity.FromModel(&task)
+		if e.ID == 0 {
+			e.ID = t.nextID
+		}
+		if e.Priority == 0 {
+			e.Priority = 3
+		}
+		if e.Version == 0 {
+			e.Version = 1
+		}
+		if e.WorkspaceID == 0 {
+			e.WorkspaceID = model.DefaultWorkspaceID
+		}
+		if e.CreatedAt.IsZero() {
+			e.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		}
+		if e.UpdatedAt.IsZero() {
+			e.UpdatedAt = e.CreatedAt
+		}
+		t.tasks[e.ID] = e
+		if e.ID >= t.nextID {
+			t.nextID = e.ID + 1
+		}
+	}
+	t.logger.Info("In-memory task storage seeded", "count", len(tasks))
+}
+
+func (t *TaskRepository) GetAllTasks(ctx context.Context, filter model.TaskFilter) (entity.TaskPage, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.TaskPage{}, err
+	}
+
+	t.mu.RLock()
+	now := t.now()
+	var tasks []entity.TaskEntity
+	for _, task := range t.tasks {
+		if task.WorkspaceID == ws && task.DeletedAt == nil && matchesFilter(&task, filter, now) {
+			tasks = append(tasks, task)
+		}
+	}
+	t.fillComputed(tasks)
+	t.mu.RUnlock()
+
+	total := len(tasks)
+	slices.SortFunc(tasks, func(a, b entity.TaskEntity) int {
+		return compareTasks(&a, &b, filter)
+	})
+
+	if filter.Cursor != nil {
+		pivot, err := cursorPivot(*filter.Cursor)
+		if err != nil {
+			return entity.TaskPage{}, err
+		}
+		idx, _ := slices.BinarySearchFunc(tasks, pivot, func(a, b entity.TaskEntity) int {
+			return compareTasks(&a, &b, filter)
+		})
+		for idx < len(tasks) && compareTasks(&tasks[idx], &pivot, filter) <= 0 {
+			idx++
+		}
+		tasks = tasks[idx:]
+	}
+
+	page := entity.TaskPage{Total: total}
+	if filter.Offset >= len(tasks) {
+		return page, nil
+	}
+	tasks = tasks[filter.Offset:]
+	if len(tasks) > filter.Limit {
+		tasks = tasks[:filter.Limit]
+		page.HasMore = true
+	}
+	page.Tasks = tasks
+	return page, nil
+}
+
+func (t *TaskRepository) CreateTask(ctx context.Context, task model.Task) (entity.TaskEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.TaskEntity{}, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	e := *entity.FromModel(&task)
+	e.ID = t.nextID
+	e.WorkspaceID = ws
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	e.DeletedAt = nil
+	e.Version = 1
+	t.tasks[e.ID] = e
+	t.nextID++
+	t.record(ctx, model.AuditCreate, nil, &e)
+	t.bumpAncestors(e.ParentID)
+
+	t.logger.Info("Task created successfully", "task_id", e.ID, "title", e.Title)
+	return e, nil
+}
+
+func (t *TaskRepository) UpdateTask(ctx context.Context, task model.Task) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, task.ID)
+	if !ok || e.DeletedAt != nil || (task.Version != 0 && task.Version != e.Version) {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	before := e
+	e.Title = task.Title
+	e.Description = task.Description
+	e.Priority = task.Priority
+	e.Version++
+	e.UpdatedAt = t.now()
+	t.tasks[e.ID] = e
+	t.record(ctx, model.AuditUpdate, &before, &e)
+	return t.withComputed(e), nil
+}
+
+func (t *TaskRepository) PatchTask(ctx context.Context, id int, patch model.TaskPatch) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	if (patch.Version != 0 && patch.Version != e.Version) ||
+		(patch.Test.Title != nil && *patch.Test.Title != e.Title) ||
+		(patch.Test.Description != nil && *patch.Test.Description != e.Description) ||
+		(patch.Test.Priority != nil && *patch.Test.Priority != e.Priority) {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+
+	before := e
+	if patch.Set.Title != nil {
+		e.Title = *patch.Set.Title
+	}
+	if patch.Set.Description != nil {
+		e.Description = *patch.Set.Description
+	}
+	if patch.Set.Priority != nil {
+		e.Priority = *patch.Set.Priority
+	}
+	e.Version++
+	e.UpdatedAt = t.now()
+	t.tasks[id] = e
+	t.record(ctx, model.AuditPatch, &before, &e)
+	return t.withComputed(e), nil
+}
+
+func (t *TaskRepository) UpdateTaskStatus(ctx context.Context, task model.Task, from model.TaskStatus) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, task.ID)
+	if !ok || e.DeletedAt != nil || e.Status != string(from) || e.Version != task.Version {
+		return entity.TaskEntity{}, fmt.Errorf("%w: task status changed concurrently", model.ErrInvalidTransition)
+	}
+	before := e
+	e.Status = string(task.Status)
+	e.StartedAt = task.StartedAt
+	e.CompletedAt = task.CompletedAt
+	e.Version++
+	e.UpdatedAt = t.now()
+	t.tasks[e.ID] = e
+	t.record(ctx, model.AuditStatus, &before, &e)
+	if completionChanged(before.Status, e.Status) {
+		t.bumpAncestors(e.ParentID)
+		t.bumpDependents(e.ID)
+	}
+	return t.withComputed(e), nil
+}
+
+func (t *TaskRepository) AssignTask(ctx context.Context, id int, assigneeID *int, version int) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil || (version != 0 && version != e.Version) {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	before := e
+	e.AssigneeID = assigneeID
+	e.Version++
+	e.UpdatedAt = t.now()
+	t.tasks[id] = e
+	t.record(ctx, model.AuditAssign, &before, &e)
+	return t.withComputed(e), nil
+}
+
+func (t *TaskRepository) GetTaskById(ctx context.Context, id int) (entity.TaskEntity, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	return t.withComputed(e), nil
+}
+
+func (t *TaskRepository) GetDeletedTasks(ctx context.Context) ([]entity.TaskEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	var tasks []entity.TaskEntity
+	for _, e := range t.tasks {
+		if e.WorkspaceID == ws && e.DeletedAt != nil {
+			tasks = append(tasks, e)
+		}
+	}
+	t.fillComputed(tasks)
+	t.mu.RUnlock()
+
+	slices.SortFunc(tasks, func(a, b entity.TaskEntity) int {
+		return cmp.Or(b.DeletedAt.Compare(*a.DeletedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return tasks, nil
+}
+
+func (t *TaskRepository) DeleteTask(ctx context.Context, id int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt != nil {
+		return repository.ErrTaskNotFound
+	}
+	before := e
+	now := t.now()
+	e.DeletedAt = &now
+	e.Version++
+	t.tasks[id] = e
+	t.record(ctx, model.AuditDelete, &before, &e)
+	t.bumpAncestors(e.ParentID)
+	t.bumpDependents(id)
+	return nil
+}
+
+func (t *TaskRepository) RestoreTask(ctx context.Context, id int) (entity.TaskEntity, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt == nil {
+		return entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	before := e
+	e.DeletedAt = nil
+	e.Version++
+	t.tasks[id] = e
+	t.record(ctx, model.AuditRestore, &before, &e)
+	t.bumpAncestors(e.ParentID)
+	t.bumpDependents(id)
+	return t.withComputed(e), nil
+}
+
+func (t *TaskRepository) PurgeTask(ctx context.Context, id int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.lookup(ctx, id)
+	if !ok || e.DeletedAt == nil {
+		return repository.ErrTaskNotFound
+	}
+	delete(t.tasks, id)
+	t.record(ctx, model.AuditPurge, &e, nil)
+
+	// Внешние ключи md.tasks: подзадачи становятся корневыми, зависимости удаляются,
+	// следующий экземпляр серии теряет ссылку на удалённый. Подзадачи и зависимые задачи
+	// меняют родителя или блокеры, поэтому получают новую версию.
+	for other, o := range t.tasks {
+		if o.ParentID != nil && *o.ParentID == id {
+			o.ParentID = nil
+			o.Version++
+		}
+		if o.RecurredFrom != nil && *o.RecurredFrom == id {
+			o.RecurredFrom = nil
+		}
+		if slices.Contains(o.BlockedBy, id) {
+			o.BlockedBy = slices.DeleteFunc(slices.Clone(o.BlockedBy), func(b int) bool { return b == id })
+			o.Version++
+		}
+		t.tasks[other] = o
+	}
+	return nil
+}
+
+func (t *TaskRepository) GetTaskHistory(ctx context.Context, taskID int, limit int, beforeID int64) ([]entity.AuditEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var entries []entity.AuditEntity
+	for i := len(t.audit) - 1; i >= 0 && len(entries) < limit; i-- {
+		a := t.audit[i]
+		if a.TaskID == taskID && a.WorkspaceID == ws && (beforeID == 0 || a.ID < beforeID) {
+			entries = append(entries, a)
+		}
+	}
+	return entries, nil
+}
+
+// record добавляет запись истории; вызывается под t.mu.
+func (t *TaskRepository) record(ctx context.Context, action model.AuditAction, before, after *entity.TaskEntity) {
+	var from, to *model.Task
+	taskID, ws := 0, 0
+	if before != nil {
+		from, taskID, ws = before.ToModel(), before.ID, before.WorkspaceID
+	}
+	if after != nil {
+		to, taskID, ws = after.ToModel(), after.ID, after.WorkspaceID
+	}
+	t.audit = append(t.audit, entity.AuditEntity{
+		ID:          int64(len(t.audit) + 1),
+		TaskID:      taskID,
+		WorkspaceID: ws,
+		Action:      string(action),
+		Actor:       reqctx.Actor(ctx),
+		RequestID:   reqctx.RequestID(ctx),
+		Changes:     model.DiffTasks(from, to),
+		CreatedAt:   t.now(),
+	})
+}
+
+// lookup находит задачу в пространстве запроса; вызывается под t.mu.
+// completionChanged сообщает, завершилась задача или открылась снова: от этого зависят
+// прогресс её предков и открытые блокеры зависимых задач.
+func completionChanged(from, to string) bool {
+	return (from == string(model.StatusCompleted)) != (to == string(model.StatusCompleted))
+}
+
+func (t *TaskRepository) lookup(ctx context.Context, id int) (entity.TaskEntity, bool) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.TaskEntity{}, false
+	}
+	e, ok := t.tasks[id]
+	return e, ok && e.WorkspaceID == ws
+}
+
+func workspaceID(ctx context.Context) (int, error) {
+	id, ok := reqctx.Workspace(ctx)
+	if !ok {
+		return 0, repository.ErrNoWorkspace
+	}
+	return id, nil
+}
+
+// matchesFilter проверяет задачу по фильтру; now — момент, относительно которого задача просрочена.
+func matchesFilter(e *entity.TaskEntity, f model.TaskFilter, now time.Time) bool {
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, model.TaskStatus(e.Status)) {
+		return false
+	}
+	if f.PriorityMin != nil && e.Priority < *f.PriorityMin {
+		return false
+	}
+	if f.PriorityMax != nil && e.Priority > *f.PriorityMax {
+		return false
+	}
+	if !inRange(e.CreatedAt, f.CreatedFrom, f.CreatedTo) || !inRange(e.UpdatedAt, f.UpdatedFrom, f.UpdatedTo) {
+		return false
+	}
+	if f.AssigneeID != nil && (e.AssigneeID == nil || *e.AssigneeID != *f.AssigneeID) {
+		return false
+	}
+	if f.Unassigned && e.AssigneeID != nil {
+		return false
+	}
+	if f.CreatedBy != nil && (e.CreatedBy == nil || *e.CreatedBy != *f.CreatedBy) {
+		return false
+	}
+	if f.Overdue && !e.ToModel().Overdue(now) {
+		return false
+	}
+	if (f.DueFrom != nil || f.DueTo != nil) && (e.DueAt == nil || !inRange(*e.DueAt, f.DueFrom, f.DueTo)) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		has := func(name string) bool { return slices.Contains(e.Tags, name) }
+		if f.TagMatch == model.TagMatchAll {
+			return !slices.ContainsFunc(f.Tags, func(name string) bool { return !has(name) })
+		}
+		return slices.ContainsFunc(f.Tags, has)
+	}
+	return true
+}
+
+// inRange проверяет полуинтервал [from, to), как и SQL-реализация.
+func inRange(t time.Time, from, to *time.Time) bool {
+	if from != nil && t.Before(*from) {
+		return false
+	}
+	if to != nil && !t.Before(*to) {
+		return false
+	}
+	return true
+}
+
+// compareTasks упорядочивает задачи так же, как ORDER BY <поле>, id в postgresql.TaskRepository;
+// заголовки там тоже сравниваются побайтно, через COLLATE "C".
+func compareTasks(a, b *entity.TaskEntity, f model.TaskFilter) int {
+	var c int
+	switch f.SortBy {
+	case model.SortByCreatedAt:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	case model.SortByUpdatedAt:
+		c = a.UpdatedAt.Compare(b.UpdatedAt)
+	case model.SortByPriority:
+		c = cmp.Compare(a.Priority, b.Priority)
+	case model.SortByTitle:
+		c = strings.Compare(a.Title, b.Title)
+	}
+	c = cmp.Or(c, cmp.Compare(a.ID, b.ID))
+	if f.SortDesc {
+		return -c
+	}
+	return c
+}
+
+// cursorPivot строит фиктивную задачу с ключом сортировки из курсора.
+func cursorPivot(c model.TaskCursor) (entity.TaskEntity, error) {
+	pivot := entity.TaskEntity{ID: c.ID}
+	value, err := c.SortValue()
+	if err != nil {
+		return pivot, err
+	}
+	switch c.Field {
+	case model.SortByCreatedAt:
+		pivot.CreatedAt = value.(time.Time)
+	case model.SortByUpdatedAt:
+		pivot.UpdatedAt = value.(time.Time)
+	case model.SortByPriority:
+		pivot.Priority = value.(int)
+	case model.SortByTitle:
+		pivot.Title = value.(string)
+	}
+	return pivot, nil
+}
@@ -0,0 +1,531 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"myApi/reqctx"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestRepo(t *testing.T) (*TaskRepository, context.Context) {
+	t.Helper()
+	return NewTaskRepository(slog.New(slog.DiscardHandler)), reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID)
+}
+
+// normalized подставляет в фильтр значения по умолчанию, как это делает сервис перед
+// обращением к хранилищу.
+func normalized(t *testing.T, filter model.TaskFilter) model.TaskFilter {
+	t.Helper()
+	if err := filter.Normalize(); err != nil {
+		t.Fatalf("Normalize() = %v", err)
+	}
+	return filter
+}
+
+func TestConcurrentPatchWithVersion(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{{ID: 1, Title: "Shared"}})
+
+	const writers = 50
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			title := fmt.Sprintf("Writer %d", i)
+			_, err := repo.PatchTask(ctx, 1, model.TaskPatch{Set: model.TaskFields{Title: &title}, Version: 1})
+			switch {
+			case err == nil:
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			case !errors.Is(err, repository.ErrTaskNotFound):
+				t.Errorf("PatchTask() = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Fatalf("%d patches of version 1 succeeded, want exactly 1", succeeded)
+	}
+	task, err := repo.GetTaskById(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Version != 2 {
+		t.Errorf("version = %d, want 2", task.Version)
+	}
+}
+
+func TestConcurrentPatchRetriesLoseNoUpdates(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{{ID: 1, Title: "Counter", Priority: 1}})
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			desc := fmt.Sprintf("writer %d", i)
+			// Оптимистичная блокировка: перечитываем версию, пока запись не пройдёт.
+			for {
+				current, err := repo.GetTaskById(ctx, 1)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				_, err = repo.PatchTask(ctx, 1, model.TaskPatch{
+					Set:     model.TaskFields{Description: &desc},
+					Version: current.Version,
+				})
+				if err == nil {
+					return
+				}
+				if !errors.Is(err, repository.ErrTaskNotFound) {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	task, err := repo.GetTaskById(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Version != 1+writers {
+		t.Errorf("version = %d, want %d", task.Version, 1+writers)
+	}
+	history, err := repo.GetTaskHistory(ctx, 1, 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != writers {
+		t.Errorf("history has %d entries, want %d", len(history), writers)
+	}
+}
+
+func TestConcurrentUpdateTaskStatus(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{{ID: 1, Title: "Race", Status: model.StatusPending}})
+
+	const workers = 50
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			now := time.Now()
+			task := model.Task{ID: 1, Status: model.StatusInProgress, StartedAt: &now, Version: 1}
+			_, err := repo.UpdateTaskStatus(ctx, task, model.StatusPending)
+			switch {
+			case err == nil:
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			case !errors.Is(err, model.ErrInvalidTransition):
+				t.Errorf("UpdateTaskStatus() = %v", err)
+			}
+		}()
+	}
+	// Параллельно с переходами идут правки полей: они тоже меняют версию.
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			desc := "touched"
+			_, _ = repo.PatchTask(ctx, 1, model.TaskPatch{Set: model.TaskFields{Description: &desc}, Version: 1})
+		}()
+	}
+	wg.Wait()
+
+	task, err := repo.GetTaskById(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Version != 2 {
+		t.Errorf("version = %d, want 2: exactly one write of version 1 must win", task.Version)
+	}
+	if succeeded > 1 {
+		t.Errorf("%d transitions succeeded, want at most 1", succeeded)
+	}
+	if (succeeded == 1) != (task.Status == string(model.StatusInProgress)) {
+		t.Errorf("status = %s after %d successful transitions", task.Status, succeeded)
+	}
+}
+
+// paginationSeed — задачи с повторяющимися значениями всех полей сортировки, чтобы
+// страницы резались посреди равных значений.
+func paginationSeed() []model.Task {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	titles := []string{"alpha", "Alpha", "beta", "beta", "Ёлка", "ёлка", "zeta", "Zeta", "alpha"}
+	tasks := make([]model.Task, 0, 40)
+	for i := range 40 {
+		tasks = append(tasks, model.Task{
+			ID:        i + 1,
+			Title:     titles[i%len(titles)],
+			Priority:  1 + i%3,
+			CreatedAt: base.Add(time.Duration(i/4) * time.Hour),
+			UpdatedAt: base.Add(time.Duration(i%5) * time.Minute),
+		})
+	}
+	return tasks
+}
+
+func TestCursorPaginationVisitsEveryTaskOnce(t *testing.T) {
+	fields := []model.TaskSortField{model.SortByCreatedAt, model.SortByUpdatedAt, model.SortByPriority, model.SortByTitle, model.SortByID}
+	for _, field := range fields {
+		for _, desc := range []bool{false, true} {
+			for _, limit := range []int{1, 3, 7, 40} {
+				t.Run(fmt.Sprintf("%s/desc=%v/limit=%d", field, desc, limit), func(t *testing.T) {
+					repo, ctx := newTestRepo(t)
+					seed := paginationSeed()
+					repo.Seed(seed)
+
+					var got []int
+					var cursor *model.TaskCursor
+					for pages := 0; ; pages++ {
+						if pages > len(seed) {
+							t.Fatal("pagination does not terminate")
+						}
+						filter := model.TaskFilter{SortBy: field, SortDesc: desc, Limit: limit, Cursor: cursor}
+						page, err := repo.GetAllTasks(ctx, normalized(t, filter))
+						if err != nil {
+							t.Fatal(err)
+						}
+						if page.Total != len(seed) {
+							t.Fatalf("total = %d, want %d", page.Total, len(seed))
+						}
+						for _, e := range page.Tasks {
+							got = append(got, e.ID)
+						}
+						if !page.HasMore {
+							break
+						}
+						// Курсор проходит тот же путь, что и в API: кодируется и разбирается.
+						last := page.Tasks[len(page.Tasks)-1].ToModel()
+						decoded, err := model.DecodeTaskCursor(model.NewTaskCursor(field, last).Encode())
+						if err != nil {
+							t.Fatal(err)
+						}
+						cursor = decoded
+					}
+
+					want := expectedOrder(seed, field, desc)
+					if !slices.Equal(got, want) {
+						t.Errorf("pages visited %v, want %v", got, want)
+					}
+				})
+			}
+		}
+	}
+}
+
+// expectedOrder сортирует задачи независимо от compareTasks: по полю, затем по ID.
+func expectedOrder(tasks []model.Task, field model.TaskSortField, desc bool) []int {
+	sorted := slices.Clone(tasks)
+	slices.SortFunc(sorted, func(a, b model.Task) int {
+		var c int
+		switch field {
+		case model.SortByCreatedAt:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		case model.SortByUpdatedAt:
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		case model.SortByPriority:
+			c = cmp.Compare(a.Priority, b.Priority)
+		case model.SortByTitle:
+			// Побайтное сравнение, как COLLATE "C" в postgresql.
+			c = strings.Compare(a.Title, b.Title)
+		}
+		c = cmp.Or(c, cmp.Compare(a.ID, b.ID))
+		if desc {
+			return -c
+		}
+		return c
+	})
+	ids := make([]int, len(sorted))
+	for i := range sorted {
+		ids[i] = sorted[i].ID
+	}
+	return ids
+}
+
+func TestCursorPaginationSkipsTasksDeletedBetweenPages(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	repo.Seed(paginationSeed())
+
+	filter := model.TaskFilter{SortBy: model.SortByPriority, Limit: 10}
+	first, err := repo.GetAllTasks(ctx, normalized(t, filter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := first.Tasks[len(first.Tasks)-1].ToModel()
+	// Удаление уже отданной задачи не должно сдвигать следующую страницу.
+	if err := repo.DeleteTask(ctx, first.Tasks[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	cursor := model.NewTaskCursor(model.SortByPriority, last)
+	filter.Cursor = &cursor
+	second, err := repo.GetAllTasks(ctx, normalized(t, filter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := expectedOrder(paginationSeed(), model.SortByPriority, false)[10:20]
+	var got []int
+	for _, e := range second.Tasks {
+		got = append(got, e.ID)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("second page = %v, want %v", got, want)
+	}
+}
+
+func TestPurgeTaskClearsLinks(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	purged := 1
+	repo.Seed([]model.Task{
+		{ID: purged, Title: "Purged"},
+		{ID: 2, Title: "Child", ParentID: &purged},
+		{ID: 3, Title: "Next occurrence", RecurredFrom: &purged},
+		{ID: 4, Title: "Blocked", BlockedBy: []int{purged, 5}},
+		{ID: 5, Title: "Other blocker"},
+	})
+
+	if err := repo.PurgeTask(ctx, purged); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Fatalf("PurgeTask(live task) = %v, want ErrTaskNotFound", err)
+	}
+	if err := repo.DeleteTask(ctx, purged); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.PurgeTask(ctx, purged); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.GetTaskById(ctx, purged); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("GetTaskById(purged) = %v, want ErrTaskNotFound", err)
+	}
+	get := func(id int) entity.TaskEntity {
+		t.Helper()
+		e, err := repo.GetTaskById(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	if child := get(2); child.ParentID != nil {
+		t.Errorf("child parent_id = %d, want nil", *child.ParentID)
+	}
+	if next := get(3); next.RecurredFrom != nil {
+		t.Errorf("recurred_from = %d, want nil", *next.RecurredFrom)
+	}
+	if blocked := get(4); !slices.Equal(blocked.BlockedBy, []int{5}) {
+		t.Errorf("blocked_by = %v, want [5]", blocked.BlockedBy)
+	}
+	if err := repo.PurgeTask(ctx, purged); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("second PurgeTask() = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestTasksAreIsolatedByWorkspace(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{{ID: 1, Title: "Default workspace"}})
+	other := reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID+1)
+
+	if _, err := repo.GetTaskById(other, 1); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("GetTaskById(other workspace) = %v, want ErrTaskNotFound", err)
+	}
+	if _, err := repo.GetAllTasks(context.Background(), normalized(t, model.TaskFilter{})); !errors.Is(err, repository.ErrNoWorkspace) {
+		t.Errorf("GetAllTasks(no workspace) = %v, want ErrNoWorkspace", err)
+	}
+	page, err := repo.GetAllTasks(ctx, normalized(t, model.TaskFilter{}))
+	if err != nil || page.Total != 1 {
+		t.Errorf("GetAllTasks(default) = %d tasks, %v", page.Total, err)
+	}
+}
+
+func TestTrashHidesTasks(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{{ID: 1, Title: "Kept"}, {ID: 2, Title: "First trashed"}, {ID: 3, Title: "Second trashed"}})
+	clock := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return clock }
+
+	for _, id := range []int{2, 3} {
+		if err := repo.DeleteTask(ctx, id); err != nil {
+			t.Fatalf("DeleteTask(%d) = %v", id, err)
+		}
+		clock = clock.Add(time.Minute)
+	}
+	if err := repo.DeleteTask(ctx, 2); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("DeleteTask() of a trashed task = %v, want ErrTaskNotFound", err)
+	}
+
+	if _, err := repo.GetTaskById(ctx, 2); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("GetTaskById() of a trashed task = %v, want ErrTaskNotFound", err)
+	}
+	page, err := repo.GetAllTasks(ctx, normalized(t, model.TaskFilter{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 1 || len(page.Tasks) != 1 || page.Tasks[0].ID != 1 {
+		t.Errorf("GetAllTasks() = %d tasks, want only the kept one", page.Total)
+	}
+
+	trash, err := repo.GetDeletedTasks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Корзина — последние удалённые первыми.
+	if got := taskIDs(trash); !slices.Equal(got, []int{3, 2}) {
+		t.Errorf("GetDeletedTasks() = %v, want [3 2]", got)
+	}
+	if trash[1].DeletedAt == nil || !trash[1].DeletedAt.Equal(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("deleted_at = %v", trash[1].DeletedAt)
+	}
+	other := reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID+1)
+	if trash, err := repo.GetDeletedTasks(other); err != nil || len(trash) != 0 {
+		t.Errorf("GetDeletedTasks(other workspace) = %v, %v", taskIDs(trash), err)
+	}
+}
+
+func TestRestoreTask(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{{ID: 1, Title: "Task"}})
+
+	if _, err := repo.RestoreTask(ctx, 1); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("RestoreTask() of a live task = %v, want ErrTaskNotFound", err)
+	}
+	if err := repo.DeleteTask(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := repo.RestoreTask(ctx, 1)
+	if err != nil {
+		t.Fatalf("RestoreTask() = %v", err)
+	}
+	// Удаление и восстановление — две записи, каждая меняет версию.
+	if restored.DeletedAt != nil || restored.Version != 3 {
+		t.Errorf("restored deleted_at %v version %d, want nil 3", restored.DeletedAt, restored.Version)
+	}
+	if _, err := repo.GetTaskById(ctx, 1); err != nil {
+		t.Errorf("GetTaskById() after restore = %v", err)
+	}
+	if trash, _ := repo.GetDeletedTasks(ctx); len(trash) != 0 {
+		t.Errorf("trash after restore = %v", taskIDs(trash))
+	}
+	history, err := repo.GetTaskHistory(ctx, 1, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Action != string(model.AuditRestore) || history[1].Action != string(model.AuditDelete) {
+		t.Errorf("history = %+v", history)
+	}
+}
+
+func TestPurgeTask(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	repo.Seed([]model.Task{{ID: 1, Title: "Task"}})
+
+	if err := repo.PurgeTask(ctx, 1); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("PurgeTask() of a live task = %v, want ErrTaskNotFound", err)
+	}
+	if err := repo.DeleteTask(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.PurgeTask(ctx, 1); err != nil {
+		t.Fatalf("PurgeTask() = %v", err)
+	}
+	if trash, _ := repo.GetDeletedTasks(ctx); len(trash) != 0 {
+		t.Errorf("trash after purge = %v", taskIDs(trash))
+	}
+	if _, err := repo.RestoreTask(ctx, 1); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("RestoreTask() after purge = %v, want ErrTaskNotFound", err)
+	}
+	if err := repo.PurgeTask(ctx, 1); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("PurgeTask() twice = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestDueFilters(t *testing.T) {
+	repo, ctx := newTestRepo(t)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return now }
+	at := func(d time.Duration) *time.Time { return ptr(now.Add(d)) }
+	repo.Seed([]model.Task{
+		{ID: 1, Title: "Late", DueAt: at(-time.Hour)},
+		{ID: 2, Title: "Done late", DueAt: at(-time.Hour), Status: model.StatusCompleted},
+		{ID: 3, Title: "Due soon", DueAt: at(time.Hour), Status: model.StatusInProgress},
+		{ID: 4, Title: "No due date"},
+		{ID: 5, Title: "Due right now", DueAt: at(0)},
+		{ID: 6, Title: "Trashed", DueAt: at(-2 * time.Hour)},
+	})
+	if err := repo.DeleteTask(ctx, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   []int
+	}{
+		{"no filter", model.TaskFilter{}, []int{1, 2, 3, 4, 5}},
+		// Срок, наступивший ровно сейчас, ещё не прошёл.
+		{"overdue", model.TaskFilter{Overdue: true}, []int{1}},
+		{"due range is half-open", model.TaskFilter{DueFrom: at(-time.Hour), DueTo: at(time.Hour)}, []int{1, 2, 5}},
+		{"due from", model.TaskFilter{DueFrom: at(0)}, []int{3, 5}},
+		{"due to", model.TaskFilter{DueTo: at(0)}, []int{1, 2}},
+		{"empty range", model.TaskFilter{DueFrom: at(2 * time.Hour)}, []int{}},
+		{"overdue within range", model.TaskFilter{Overdue: true, DueFrom: at(-90 * time.Minute), DueTo: at(0)}, []int{1}},
+		{"overdue outside range", model.TaskFilter{Overdue: true, DueTo: at(-time.Hour)}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.SortBy = model.SortByID
+			page, err := repo.GetAllTasks(ctx, normalized(t, tt.filter))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := taskIDs(page.Tasks); !slices.Equal(got, tt.want) || page.Total != len(tt.want) {
+				t.Errorf("GetAllTasks() = %v (total %d), want %v", got, page.Total, tt.want)
+			}
+		})
+	}
+
+	// Просрочка считается по текущему времени, а не по моменту записи.
+	now = now.Add(2 * time.Hour)
+	page, err := repo.GetAllTasks(ctx, normalized(t, model.TaskFilter{Overdue: true, SortBy: model.SortByID}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := taskIDs(page.Tasks); !slices.Equal(got, []int{1, 3, 5}) {
+		t.Errorf("overdue two hours later = %v, want [1 3 5]", got)
+	}
+}
+
+func taskIDs(tasks []entity.TaskEntity) []int {
+	ids := make([]int, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	return ids
+}
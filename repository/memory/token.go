@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"myApi/db/entity"
+	"myApi/repository"
+	"sync"
+	"time"
+)
+
+type TokenRepository struct {
+	mu      sync.Mutex
+	refresh map[string]entity.RefreshTokenEntity
+	revoked map[string]time.Time
+	now     func() time.Time
+}
+
+func NewTokenRepository() *TokenRepository {
+	return &TokenRepository{
+		refresh: make(map[string]entity.RefreshTokenEntity),
+		revoked: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (r *TokenRepository) SaveRefreshToken(ctx context.Context, jti string, userID int, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refresh[jti] = entity.RefreshTokenEntity{
+		ID:        jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		CreatedAt: r.now(),
+	}
+	return nil
+}
+
+func (r *TokenRepository) RevokeRefreshToken(ctx context.Context, jti string) (entity.RefreshTokenEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.refresh[jti]
+	if !ok {
+		return entity.RefreshTokenEntity{}, repository.ErrTokenNotFound
+	}
+	prev := token
+	if token.RevokedAt == nil {
+		now := r.now()
+		token.RevokedAt = &now
+		r.refresh[jti] = token
+	}
+	return prev, nil
+}
+
+func (r *TokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for jti, token := range r.refresh {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			r.refresh[jti] = token
+		}
+	}
+	return nil
+}
+
+func (r *TokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for id, exp := range r.revoked {
+		if exp.Before(now) {
+			delete(r.revoked, id)
+		}
+	}
+	r.revoked[jti] = expiresAt
+	return nil
+}
+
+func (r *TokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.revoked[jti]
+	return ok, nil
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"sync"
+	"time"
+)
+
+type UserRepository struct {
+	mu         sync.RWMutex
+	users      map[int]entity.UserEntity
+	nextID     int
+	workspaces *WorkspaceRepository
+	now        func() time.Time
+}
+
+// NewUserRepository создаёт хранилище пользователей; личные пространства при регистрации
+// создаются в workspaces.
+func NewUserRepository(workspaces *WorkspaceRepository) *UserRepository {
+	return &UserRepository{
+		users:      make(map[int]entity.UserEntity),
+		nextID:     1,
+		workspaces: workspaces,
+		now:        time.Now,
+	}
+}
+
+func (u *UserRepository) CreateUser(ctx context.Context, user model.User) (entity.UserEntity, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.insertUser(user)
+}
+
+// CreateUserWithWorkspace добавляет пользователя и его пространство, держа обе блокировки,
+// чтобы пользователь не появился без пространства.
+func (u *UserRepository) CreateUserWithWorkspace(ctx context.Context, user model.User, workspaceName string) (entity.UserEntity, entity.WorkspaceEntity, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.workspaces.mu.Lock()
+	defer u.workspaces.mu.Unlock()
+
+	created, err := u.insertUser(user)
+	if err != nil {
+		return entity.UserEntity{}, entity.WorkspaceEntity{}, err
+	}
+	return created, u.workspaces.insertWorkspace(workspaceName, created.ID), nil
+}
+
+// insertUser вызывается под u.mu.
+func (u *UserRepository) insertUser(user model.User) (entity.UserEntity, error) {
+	for _, existing := range u.users {
+		if existing.Email == user.Email {
+			return entity.UserEntity{}, repository.ErrUserExists
+		}
+	}
+
+	// Пока администраторов нет, новый пользователь становится администратором.
+	role := model.RoleAdmin
+	for _, existing := range u.users {
+		if existing.Role == string(model.RoleAdmin) {
+			role = user.Role
+			break
+		}
+	}
+
+	now := u.now()
+	e := entity.UserEntity{
+		ID:           u.nextID,
+		Email:        user.Email,
+		Name:         user.Name,
+		PasswordHash: user.PasswordHash,
+		Role:         string(role),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	u.users[e.ID] = e
+	u.nextID++
+	return e, nil
+}
+
+func (u *UserRepository) GetUserByEmail(ctx context.Context, email string) (entity.UserEntity, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	for _, e := range u.users {
+		if e.Email == email {
+			return e, nil
+		}
+	}
+	return entity.UserEntity{}, repository.ErrUserNotFound
+}
+
+func (u *UserRepository) GetUserByID(ctx context.Context, id int) (entity.UserEntity, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	e, ok := u.users[id]
+	if !ok {
+		return entity.UserEntity{}, repository.ErrUserNotFound
+	}
+	return e, nil
+}
+
+func (u *UserRepository) ListUsers(ctx context.Context) ([]entity.UserEntity, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := slices.Collect(maps.Values(u.users))
+	slices.SortFunc(users, func(a, b entity.UserEntity) int { return a.ID - b.ID })
+	return users, nil
+}
+
+func (u *UserRepository) SetUserRole(ctx context.Context, id int, role model.Role) (entity.UserEntity, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	e, ok := u.users[id]
+	if !ok {
+		return entity.UserEntity{}, repository.ErrUserNotFound
+	}
+	e.Role = string(role)
+	e.UpdatedAt = u.now()
+	u.users[id] = e
+	return e, nil
+}
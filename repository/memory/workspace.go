@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"sync"
+	"time"
+)
+
+type WorkspaceRepository struct {
+	mu         sync.RWMutex
+	workspaces map[int]entity.WorkspaceEntity
+	members    map[int]map[int]time.Time
+	nextID     int
+	now        func() time.Time
+}
+
+// NewWorkspaceRepository создаёт хранилище с пространством Default, куда Seed кладёт задачи.
+func NewWorkspaceRepository() *WorkspaceRepository {
+	r := &WorkspaceRepository{
+		workspaces: make(map[int]entity.WorkspaceEntity),
+		members:    make(map[int]map[int]time.Time),
+		nextID:     model.DefaultWorkspaceID + 1,
+		now:        time.Now,
+	}
+	r.workspaces[model.DefaultWorkspaceID] = entity.WorkspaceEntity{
+		ID:        model.DefaultWorkspaceID,
+		Name:      "Default",
+		CreatedAt: r.now(),
+	}
+	r.members[model.DefaultWorkspaceID] = make(map[int]time.Time)
+	return r
+}
+
+func (r *WorkspaceRepository) CreateWorkspace(ctx context.Context, name string, ownerID int) (entity.WorkspaceEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.insertWorkspace(name, ownerID), nil
+}
+
+// insertWorkspace вызывается под r.mu.
+func (r *WorkspaceRepository) insertWorkspace(name string, ownerID int) entity.WorkspaceEntity {
+	now := r.now()
+	ws := entity.WorkspaceEntity{
+		ID:        r.nextID,
+		Name:      name,
+		CreatedBy: &ownerID,
+		CreatedAt: now,
+	}
+	r.workspaces[ws.ID] = ws
+	r.members[ws.ID] = map[int]time.Time{ownerID: now}
+	r.nextID++
+	return ws
+}
+
+func (r *WorkspaceRepository) GetWorkspace(ctx context.Context, id int) (entity.WorkspaceEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ws, ok := r.workspaces[id]
+	if !ok {
+		return entity.WorkspaceEntity{}, repository.ErrWorkspaceNotFound
+	}
+	return ws, nil
+}
+
+func (r *WorkspaceRepository) ListUserWorkspaces(ctx context.Context, userID int) ([]entity.WorkspaceEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	workspaces := []entity.WorkspaceEntity{}
+	for id, members := range r.members {
+		if _, ok := members[userID]; ok {
+			workspaces = append(workspaces, r.workspaces[id])
+		}
+	}
+	slices.SortFunc(workspaces, func(a, b entity.WorkspaceEntity) int { return a.ID - b.ID })
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) IsMember(ctx context.Context, workspaceID, userID int) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.members[workspaceID][userID]
+	return ok, nil
+}
+
+func (r *WorkspaceRepository) ListMembers(ctx context.Context, workspaceID int) ([]entity.WorkspaceMemberEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := []entity.WorkspaceMemberEntity{}
+	for userID, at := range r.members[workspaceID] {
+		members = append(members, entity.WorkspaceMemberEntity{WorkspaceID: workspaceID, UserID: userID, CreatedAt: at})
+	}
+	slices.SortFunc(members, func(a, b entity.WorkspaceMemberEntity) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.UserID, b.UserID))
+	})
+	return members, nil
+}
+
+// AddMember не проверяет, что пользователь существует: это делает сервис.
+func (r *WorkspaceRepository) AddMember(ctx context.Context, workspaceID, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	members, ok := r.members[workspaceID]
+	if !ok {
+		return repository.ErrWorkspaceNotFound
+	}
+	if _, ok := members[userID]; !ok {
+		members[userID] = r.now()
+	}
+	return nil
+}
+
+func (r *WorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.members[workspaceID][userID]; !ok {
+		return repository.ErrUserNotFound
+	}
+	delete(r.members[workspaceID], userID)
+	return nil
+}
@@ -0,0 +1,161 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/db"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const apiKeyColumns = "id, user_id, name, prefix, key_hash, scopes, workspace_id, expires_at, last_used_at, revoked_at, created_at"
+
+// lastUsedPrecision — last_used_at обновляется не чаще раза в минуту, чтобы частые
+// запросы CI не превращались в поток UPDATE.
+const lastUsedPrecision = time.Minute
+
+type APIKeyRepository struct {
+	dbPool *db.Pool
+	logger *slog.Logger
+}
+
+func NewAPIKeyRepository(dbPool *db.Pool, logger *slog.Logger) *APIKeyRepository {
+	return &APIKeyRepository{
+		dbPool: dbPool,
+		logger: logger,
+	}
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key model.APIKey) (entity.APIKeyEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.APIKeyEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	scopes := make([]string, len(key.Scopes))
+	for i, s := range key.Scopes {
+		scopes[i] = string(s)
+	}
+
+	query := `
+		INSERT INTO md.api_keys (user_id, name, prefix, key_hash, scopes, workspace_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + apiKeyColumns
+
+	created, err := scanAPIKey(pool.QueryRow(ctx, query, key.UserID, key.Name, key.Prefix, key.Hash, scopes, key.WorkspaceID, key.ExpiresAt))
+	if err != nil {
+		r.logger.Error("Failed to create api key", "user_id", key.UserID, "error", err)
+		return entity.APIKeyEntity{}, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	r.logger.Info("API key created", "id", created.ID, "user_id", created.UserID, "prefix", created.Prefix)
+	return created, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (entity.APIKeyEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.APIKeyEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	key, err := scanAPIKey(pool.QueryRow(ctx, "SELECT "+apiKeyColumns+" FROM md.api_keys WHERE prefix = $1", prefix))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.APIKeyEntity{}, repository.ErrAPIKeyNotFound
+		}
+		return entity.APIKeyEntity{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return key, nil
+}
+
+// ListAPIKeys возвращает все ключи пользователя, включая отозванные и истёкшие.
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context, userID int) ([]entity.APIKeyEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	rows, err := pool.Query(ctx, "SELECT "+apiKeyColumns+" FROM md.api_keys WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []entity.APIKeyEntity{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает ключ пользователя; повторный отзыв сохраняет исходное время.
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, userID, id int) (entity.APIKeyEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.APIKeyEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		UPDATE md.api_keys
+		SET revoked_at = COALESCE(revoked_at, now())
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(pool.QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.APIKeyEntity{}, repository.ErrAPIKeyNotFound
+		}
+		return entity.APIKeyEntity{}, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	r.logger.Info("API key revoked", "id", id, "user_id", userID)
+	return key, nil
+}
+
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, id int, at time.Time) error {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+
+	_, err := pool.Exec(ctx,
+		"UPDATE md.api_keys SET last_used_at = $2 WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3)",
+		id, at, at.Add(-lastUsedPrecision),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update api key usage: %w", err)
+	}
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (entity.APIKeyEntity, error) {
+	var key entity.APIKeyEntity
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.Hash,
+		&key.Scopes,
+		&key.WorkspaceID,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	return key, err
+}
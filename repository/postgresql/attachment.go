@@ -0,0 +1,161 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/db"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const attachmentColumns = "id, task_id, workspace_id, file_name, content_type, size, checksum, storage_key, uploaded_by, created_at"
+
+type AttachmentRepository struct {
+	dbPool *db.Pool
+	logger *slog.Logger
+	rls    bool
+}
+
+func NewAttachmentRepository(dbPool *db.Pool, logger *slog.Logger) *AttachmentRepository {
+	return &AttachmentRepository{
+		dbPool: dbPool,
+		logger: logger,
+	}
+}
+
+// EnableRowLevelSecurity — см. TaskRepository.EnableRowLevelSecurity.
+func (r *AttachmentRepository) EnableRowLevelSecurity() {
+	r.rls = true
+}
+
+// ListAttachments возвращает вложения задачи в порядке загрузки.
+func (r *AttachmentRepository) ListAttachments(ctx context.Context, taskID int) ([]entity.AttachmentEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	var attachments []entity.AttachmentEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		rows, err := q.Query(ctx, `
+			SELECT `+attachmentColumns+` FROM md.task_attachments
+			WHERE task_id = $1 AND workspace_id = $2
+			ORDER BY id`, taskID, ws)
+		if err != nil {
+			return err
+		}
+		attachments, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.AttachmentEntity, error) {
+			return scanAttachment(row)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, attachmentError("list attachments", err)
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) GetAttachment(ctx context.Context, taskID, id int) (entity.AttachmentEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.AttachmentEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	var attachment entity.AttachmentEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		var err error
+		attachment, err = scanAttachment(q.QueryRow(ctx,
+			"SELECT "+attachmentColumns+" FROM md.task_attachments WHERE id = $1 AND task_id = $2 AND workspace_id = $3",
+			id, taskID, ws))
+		return err
+	})
+	if err != nil {
+		return entity.AttachmentEntity{}, attachmentError("get attachment", err)
+	}
+	return attachment, nil
+}
+
+func (r *AttachmentRepository) CreateAttachment(ctx context.Context, attachment model.Attachment) (entity.AttachmentEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.AttachmentEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		INSERT INTO md.task_attachments (task_id, workspace_id, file_name, content_type, size, checksum, storage_key, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + attachmentColumns
+
+	var created entity.AttachmentEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		var err error
+		created, err = scanAttachment(q.QueryRow(ctx, query,
+			attachment.TaskID, ws, attachment.FileName, attachment.ContentType, attachment.Size,
+			attachment.Checksum, attachment.StorageKey, attachment.UploadedBy))
+		return err
+	})
+	if err != nil {
+		return entity.AttachmentEntity{}, attachmentError("create attachment", err)
+	}
+
+	r.logger.Info("Attachment created", "attachment_id", created.ID, "task_id", created.TaskID, "size", created.Size)
+	return created, nil
+}
+
+// DeleteAttachment удаляет запись и возвращает её, чтобы вызывающий убрал содержимое из хранилища.
+func (r *AttachmentRepository) DeleteAttachment(ctx context.Context, taskID, id int) (entity.AttachmentEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.AttachmentEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	var deleted entity.AttachmentEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		var err error
+		deleted, err = scanAttachment(q.QueryRow(ctx,
+			"DELETE FROM md.task_attachments WHERE id = $1 AND task_id = $2 AND workspace_id = $3 RETURNING "+attachmentColumns,
+			id, taskID, ws))
+		return err
+	})
+	if err != nil {
+		return entity.AttachmentEntity{}, attachmentError("delete attachment", err)
+	}
+
+	r.logger.Info("Attachment deleted", "attachment_id", id, "task_id", taskID)
+	return deleted, nil
+}
+
+// attachmentError приводит ошибки драйвера к ошибкам пакета repository.
+func attachmentError(op string, err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return repository.ErrAttachmentNotFound
+	case errors.Is(err, repository.ErrNoWorkspace):
+		return err
+	case isForeignKeyViolation(err):
+		return repository.ErrTaskNotFound
+	}
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
+
+func scanAttachment(row pgx.Row) (entity.AttachmentEntity, error) {
+	var a entity.AttachmentEntity
+	err := row.Scan(
+		&a.ID,
+		&a.TaskID,
+		&a.WorkspaceID,
+		&a.FileName,
+		&a.ContentType,
+		&a.Size,
+		&a.Checksum,
+		&a.StorageKey,
+		&a.UploadedBy,
+		&a.CreatedAt,
+	)
+	return a, err
+}
@@ -0,0 +1,117 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"myApi/reqctx"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// execAudited выполняет query (… RETURNING taskColumns) и пишет запись в md.task_audit
+// в одной транзакции. id — изменяемая задача (0 при создании): её прежнее состояние
+// читается с блокировкой строки. Если строки нет или она в другом пространстве,
+// возвращается pgx.ErrNoRows, как от QueryRow.
+func (t *TaskRepository) execAudited(ctx context.Context, pool *pgxpool.Pool, id int, action model.AuditAction, query string, args ...any) (entity.TaskEntity, error) {
+	return t.modifyAudited(ctx, pool, id, action, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		return scanTask(tx.QueryRow(ctx, query, args...))
+	})
+}
+
+// modifyAudited — то же, что execAudited, но изменение задаёт modify: оно может сделать
+// несколько запросов и должно вернуть итоговое состояние задачи.
+func (t *TaskRepository) modifyAudited(ctx context.Context, pool *pgxpool.Pool, id int, action model.AuditAction, modify func(tx pgx.Tx, ws int) (entity.TaskEntity, error)) (entity.TaskEntity, error) {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.TaskEntity{}, err
+	}
+
+	var result entity.TaskEntity
+	err = pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if t.rls {
+			if err := setWorkspace(ctx, tx, ws); err != nil {
+				return err
+			}
+		}
+
+		var before *model.Task
+		if id != 0 {
+			current, err := scanTask(tx.QueryRow(ctx,
+				"SELECT "+taskColumns+" FROM md.tasks WHERE id = $1 AND workspace_id = $2 FOR UPDATE", id, ws))
+			if err != nil {
+				return err
+			}
+			before = current.ToModel()
+		}
+
+		var err error
+		result, err = modify(tx, ws)
+		if err != nil {
+			return err
+		}
+
+		after := result.ToModel()
+		if action == model.AuditPurge {
+			after = nil
+		}
+		return insertAudit(ctx, tx, result.ID, ws, action, before, after)
+	})
+	return result, err
+}
+
+func insertAudit(ctx context.Context, tx pgx.Tx, taskID, ws int, action model.AuditAction, before, after *model.Task) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO md.task_audit (task_id, workspace_id, action, actor, request_id, changes)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		taskID,
+		ws,
+		action,
+		reqctx.Actor(ctx),
+		reqctx.RequestID(ctx),
+		model.DiffTasks(before, after),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// GetTaskHistory возвращает историю задачи, новые записи первыми. beforeID > 0 продолжает
+// выдачу с записей старше указанной. История доступна и для задач, удалённых из корзины.
+func (t *TaskRepository) GetTaskHistory(ctx context.Context, taskID int, limit int, beforeID int64) ([]entity.AuditEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		SELECT id, task_id, action, actor, request_id, changes, created_at
+		FROM md.task_audit
+		WHERE task_id = $1 AND workspace_id = $4 AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3`
+
+	var entries []entity.AuditEntity
+	err := t.inWorkspace(ctx, pool, func(db querier, ws int) error {
+		rows, err := db.Query(ctx, query, taskID, beforeID, limit, ws)
+		if err != nil {
+			t.logger.Error("Failed to query task history", "task_id", taskID, "error", err)
+			return fmt.Errorf("failed to get task history: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			e := entity.AuditEntity{WorkspaceID: ws}
+			if err := rows.Scan(&e.ID, &e.TaskID, &e.Action, &e.Actor, &e.RequestID, &e.Changes, &e.CreatedAt); err != nil {
+				return fmt.Errorf("failed to scan audit entry: %w", err)
+			}
+			entries = append(entries, e)
+		}
+		return rows.Err()
+	})
+	return entries, err
+}
@@ -0,0 +1,167 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// errBulkFailed прерывает транзакцию атомарной операции после первой неудачной задачи.
+var errBulkFailed = errors.New("bulk change failed")
+
+// ApplyBulk применяет подготовленные изменения в одной транзакции. Каждая задача меняется
+// в своей точке сохранения: в best_effort неудача откатывает только её, в атомарном
+// режиме — всю транзакцию. Неудачами задачи считаются лишь отсутствие задачи и смена
+// версии; прочие ошибки отменяют операцию целиком.
+func (t *TaskRepository) ApplyBulk(ctx context.Context, items []model.BulkItem, atomic bool) ([]entity.BulkResultEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]entity.BulkResultEntity, len(items))
+	failed := 0
+	err = pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if t.rls {
+			if err := setWorkspace(ctx, tx, ws); err != nil {
+				return err
+			}
+		}
+		// Предков и зависимые задачи меняем после всех задач, иначе задача из той же
+		// операции не прошла бы сверку версии.
+		var (
+			parents  []*int
+			blockers []int
+		)
+		for i := range items {
+			var before entity.TaskEntity
+			err := pgx.BeginFunc(ctx, tx, func(sp pgx.Tx) error {
+				var err error
+				before, results[i].Task, err = applyBulkItem(ctx, sp, ws, items[i])
+				return err
+			})
+			if err == nil {
+				if items[i].Delete || completionChanged(before.Status, results[i].Task.Status) {
+					parents = append(parents, results[i].Task.ParentID)
+					blockers = append(blockers, results[i].Task.ID)
+				}
+				continue
+			}
+			if !errors.Is(err, repository.ErrTaskNotFound) && !errors.Is(err, model.ErrVersionMismatch) {
+				return fmt.Errorf("task %d: %w", items[i].Task.ID, err)
+			}
+			results[i] = entity.BulkResultEntity{Err: err}
+			failed++
+			if atomic {
+				return errBulkFailed
+			}
+		}
+		return refreshBulkResults(ctx, tx, ws, results, parents, blockers)
+	})
+	if errors.Is(err, errBulkFailed) {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = entity.BulkResultEntity{Err: model.ErrBulkAborted}
+			}
+		}
+		t.logger.Info("Bulk change rolled back", "tasks", len(items))
+		return results, nil
+	}
+	if err != nil {
+		t.logger.Error("Failed to apply bulk change", "tasks", len(items), "error", err)
+		return nil, fmt.Errorf("failed to apply bulk change: %w", err)
+	}
+
+	t.logger.Info("Bulk change applied", "tasks", len(items)-failed, "failed", failed)
+	return results, nil
+}
+
+// refreshBulkResults увеличивает версии предков и зависимых задач изменённых задач
+// и перечитывает результаты: изменённая задача сама может оказаться одной из них.
+func refreshBulkResults(ctx context.Context, tx pgx.Tx, ws int, results []entity.BulkResultEntity, parents []*int, blockers []int) error {
+	if len(blockers) == 0 {
+		return nil
+	}
+	if err := bumpAncestors(ctx, tx, ws, parents...); err != nil {
+		return err
+	}
+	if err := bumpDependents(ctx, tx, ws, blockers...); err != nil {
+		return err
+	}
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		task, err := scanTask(tx.QueryRow(ctx, "SELECT "+taskColumns+" FROM md.tasks WHERE id = $1", results[i].Task.ID))
+		if err != nil {
+			return err
+		}
+		results[i].Task = task
+	}
+	return nil
+}
+
+// applyBulkItem меняет одну задачу и пишет запись в историю. Прежнее состояние читается
+// с блокировкой строки и сверяется с версией, на которой рассчитано изменение; оно
+// возвращается вместе с новым.
+func applyBulkItem(ctx context.Context, tx pgx.Tx, ws int, item model.BulkItem) (before, after entity.TaskEntity, err error) {
+	id := item.Task.ID
+	current, err := scanTask(tx.QueryRow(ctx,
+		"SELECT "+taskColumns+" FROM md.tasks WHERE id = $1 AND workspace_id = $2 FOR UPDATE", id, ws))
+	if errors.Is(err, pgx.ErrNoRows) || err == nil && current.DeletedAt != nil {
+		return current, entity.TaskEntity{}, repository.ErrTaskNotFound
+	}
+	if err != nil {
+		return current, entity.TaskEntity{}, err
+	}
+	if current.Version != item.Task.Version {
+		return current, entity.TaskEntity{}, model.ErrVersionMismatch
+	}
+
+	if item.Delete {
+		updated, err := scanTask(tx.QueryRow(ctx, `
+			UPDATE md.tasks
+			SET deleted_at = now(), version = version + 1
+			WHERE id = $1
+			RETURNING `+taskColumns, id))
+		if err != nil {
+			return current, entity.TaskEntity{}, err
+		}
+		return current, updated, insertAudit(ctx, tx, id, ws, item.Action, current.ToModel(), updated.ToModel())
+	}
+
+	if len(item.AddTags) > 0 {
+		if err := attachTags(ctx, tx, id, ws, item.AddTags); err != nil {
+			return current, entity.TaskEntity{}, err
+		}
+	}
+	if len(item.RemoveTags) > 0 {
+		if err := detachTags(ctx, tx, id, ws, item.RemoveTags); err != nil {
+			return current, entity.TaskEntity{}, err
+		}
+	}
+	updated, err := scanTask(tx.QueryRow(ctx, `
+		UPDATE md.tasks
+		SET status = $2, started_at = $3, completed_at = $4, priority = $5, version = version + 1, updated_at = now()
+		WHERE id = $1
+		RETURNING `+taskColumns,
+		id,
+		item.Task.Status,
+		item.Task.StartedAt,
+		item.Task.CompletedAt,
+		item.Task.Priority,
+	))
+	if err != nil {
+		return current, entity.TaskEntity{}, err
+	}
+	return current, updated, insertAudit(ctx, tx, id, ws, item.Action, current.ToModel(), updated.ToModel())
+}
@@ -0,0 +1,252 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/db"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const commentColumns = "id, task_id, reply_to, author_id, workspace_id, body, created_at, edited_at, deleted_at"
+
+type CommentRepository struct {
+	dbPool *db.Pool
+	logger *slog.Logger
+	rls    bool
+}
+
+func NewCommentRepository(dbPool *db.Pool, logger *slog.Logger) *CommentRepository {
+	return &CommentRepository{
+		dbPool: dbPool,
+		logger: logger,
+	}
+}
+
+// EnableRowLevelSecurity — см. TaskRepository.EnableRowLevelSecurity.
+func (r *CommentRepository) EnableRowLevelSecurity() {
+	r.rls = true
+}
+
+// ListComments возвращает все комментарии задачи, включая заглушки удалённых, по времени создания.
+func (r *CommentRepository) ListComments(ctx context.Context, taskID int) ([]entity.CommentEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	var comments []entity.CommentEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		rows, err := q.Query(ctx, `
+			SELECT `+commentColumns+` FROM md.task_comments
+			WHERE task_id = $1 AND workspace_id = $2
+			ORDER BY created_at, id`, taskID, ws)
+		if err != nil {
+			return err
+		}
+		comments, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.CommentEntity, error) {
+			return scanComment(row)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, commentError("list comments", err)
+	}
+	return comments, nil
+}
+
+func (r *CommentRepository) GetComment(ctx context.Context, taskID, id int) (entity.CommentEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.CommentEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	var comment entity.CommentEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		var err error
+		comment, err = scanComment(q.QueryRow(ctx,
+			"SELECT "+commentColumns+" FROM md.task_comments WHERE id = $1 AND task_id = $2 AND workspace_id = $3",
+			id, taskID, ws))
+		return err
+	})
+	if err != nil {
+		return entity.CommentEntity{}, commentError("get comment", err)
+	}
+	return comment, nil
+}
+
+func (r *CommentRepository) CreateComment(ctx context.Context, comment model.Comment) (entity.CommentEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.CommentEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		INSERT INTO md.task_comments (task_id, reply_to, author_id, workspace_id, body)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + commentColumns
+
+	var created entity.CommentEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		var err error
+		created, err = scanComment(q.QueryRow(ctx, query, comment.TaskID, comment.ReplyTo, comment.AuthorID, ws, comment.Body))
+		return err
+	})
+	if err != nil {
+		return entity.CommentEntity{}, commentError("create comment", err)
+	}
+
+	r.logger.Info("Comment created", "comment_id", created.ID, "task_id", created.TaskID)
+	return created, nil
+}
+
+// EditComment заменяет текст комментария, сохраняя прежний в md.task_comment_edits.
+// Удалённый комментарий править нельзя.
+func (r *CommentRepository) EditComment(ctx context.Context, taskID, id int, body string, editedBy *int) (entity.CommentEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.CommentEntity{}, repository.ErrDatabaseUnavailable
+	}
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.CommentEntity{}, err
+	}
+
+	var updated entity.CommentEntity
+	err = pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if r.rls {
+			if err := setWorkspace(ctx, tx, ws); err != nil {
+				return err
+			}
+		}
+		var previous string
+		err := tx.QueryRow(ctx, `
+			SELECT body FROM md.task_comments
+			WHERE id = $1 AND task_id = $2 AND workspace_id = $3 AND deleted_at IS NULL
+			FOR UPDATE`, id, taskID, ws).Scan(&previous)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			"INSERT INTO md.task_comment_edits (workspace_id, comment_id, body, edited_by) VALUES ($1, $2, $3, $4)",
+			ws, id, previous, editedBy); err != nil {
+			return err
+		}
+		updated, err = scanComment(tx.QueryRow(ctx, `
+			UPDATE md.task_comments SET body = $4, edited_at = now()
+			WHERE id = $1 AND task_id = $2 AND workspace_id = $3 AND deleted_at IS NULL
+			RETURNING `+commentColumns, id, taskID, ws, body))
+		return err
+	})
+	if err != nil {
+		return entity.CommentEntity{}, commentError("edit comment", err)
+	}
+
+	r.logger.Info("Comment edited", "comment_id", id, "task_id", taskID)
+	return updated, nil
+}
+
+// DeleteComment оставляет вместо комментария заглушку без текста и истории правок.
+func (r *CommentRepository) DeleteComment(ctx context.Context, taskID, id int) error {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if r.rls {
+			if err := setWorkspace(ctx, tx, ws); err != nil {
+				return err
+			}
+		}
+		tag, err := tx.Exec(ctx, `
+			UPDATE md.task_comments SET body = '', deleted_at = now()
+			WHERE id = $1 AND task_id = $2 AND workspace_id = $3 AND deleted_at IS NULL`, id, taskID, ws)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return repository.ErrCommentNotFound
+		}
+		_, err = tx.Exec(ctx, "DELETE FROM md.task_comment_edits WHERE comment_id = $1", id)
+		return err
+	})
+	if err != nil {
+		return commentError("delete comment", err)
+	}
+
+	r.logger.Info("Comment deleted", "comment_id", id, "task_id", taskID)
+	return nil
+}
+
+// GetCommentEdits возвращает прежние версии текста, старые первыми.
+func (r *CommentRepository) GetCommentEdits(ctx context.Context, taskID, id int) ([]entity.CommentEditEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	var edits []entity.CommentEditEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		err := q.QueryRow(ctx,
+			"SELECT 1 FROM md.task_comments WHERE id = $1 AND task_id = $2 AND workspace_id = $3", id, taskID, ws).Scan(new(int))
+		if err != nil {
+			return err
+		}
+		rows, err := q.Query(ctx, `
+			SELECT id, comment_id, body, edited_by, edited_at FROM md.task_comment_edits
+			WHERE comment_id = $1 AND workspace_id = $2
+			ORDER BY id`, id, ws)
+		if err != nil {
+			return err
+		}
+		edits, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.CommentEditEntity, error) {
+			var e entity.CommentEditEntity
+			err := row.Scan(&e.ID, &e.CommentID, &e.Body, &e.EditedBy, &e.EditedAt)
+			return e, err
+		})
+		return err
+	})
+	if err != nil {
+		return nil, commentError("get comment edits", err)
+	}
+	return edits, nil
+}
+
+// commentError приводит ошибки драйвера к ошибкам пакета repository.
+func commentError(op string, err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows), errors.Is(err, repository.ErrCommentNotFound):
+		return repository.ErrCommentNotFound
+	case errors.Is(err, repository.ErrNoWorkspace):
+		return err
+	case isForeignKeyViolation(err):
+		return repository.ErrTaskNotFound
+	}
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
+
+func scanComment(row pgx.Row) (entity.CommentEntity, error) {
+	var c entity.CommentEntity
+	err := row.Scan(
+		&c.ID,
+		&c.TaskID,
+		&c.ReplyTo,
+		&c.AuthorID,
+		&c.WorkspaceID,
+		&c.Body,
+		&c.CreatedAt,
+		&c.EditedAt,
+		&c.DeletedAt,
+	)
+	return c, err
+}
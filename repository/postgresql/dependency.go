@@ -0,0 +1,189 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// dependencyLockKey — как hierarchyLockKey, но для графа зависимостей.
+const dependencyLockKey int32 = 734092118
+
+// AddDependency отмечает, что blockerID блокирует задачу id. Блокер должен быть живой задачей
+// того же пространства, а связь не должна замыкать цикл. Повторное добавление ничего не меняет,
+// кроме версии задачи.
+func (t *TaskRepository) AddDependency(ctx context.Context, id, blockerID, version int) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	task, err := t.modifyAudited(ctx, pool, id, model.AuditDepend, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		if err := checkBlocker(ctx, tx, id, blockerID, ws); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		if _, err := tx.Exec(ctx,
+			"INSERT INTO md.task_dependencies (task_id, blocker_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			id, blockerID); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return bumpTask(ctx, tx, id, ws, version)
+	})
+	if err != nil {
+		return entity.TaskEntity{}, t.dependencyError("add dependency", id, err)
+	}
+
+	t.logger.Info("Task dependency added", "task_id", id, "blocker_id", blockerID)
+	return task, nil
+}
+
+// RemoveDependency снимает блокировку задачи id задачей blockerID.
+func (t *TaskRepository) RemoveDependency(ctx context.Context, id, blockerID, version int) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	task, err := t.modifyAudited(ctx, pool, id, model.AuditDepend, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		if _, err := tx.Exec(ctx,
+			"DELETE FROM md.task_dependencies WHERE task_id = $1 AND blocker_id = $2", id, blockerID); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return bumpTask(ctx, tx, id, ws, version)
+	})
+	if err != nil {
+		return entity.TaskEntity{}, t.dependencyError("remove dependency", id, err)
+	}
+
+	t.logger.Info("Task dependency removed", "task_id", id, "blocker_id", blockerID)
+	return task, nil
+}
+
+// checkBlocker проверяет, что blockerID существует, не в корзине и сам не ждёт задачу id,
+// прямо или через другие задачи.
+func checkBlocker(ctx context.Context, tx pgx.Tx, id, blockerID, ws int) error {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1, $2)", dependencyLockKey, ws); err != nil {
+		return err
+	}
+
+	var exists bool
+	err := tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM md.tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL)",
+		blockerID, ws).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return repository.ErrBlockerNotFound
+	}
+
+	var cycle bool
+	err = tx.QueryRow(ctx, `
+		WITH RECURSIVE up AS (
+			SELECT $1::integer AS id
+			UNION
+			SELECT d.blocker_id FROM md.task_dependencies d JOIN up ON d.task_id = up.id
+		)
+		SELECT EXISTS (SELECT 1 FROM up WHERE id = $2)`, blockerID, id).Scan(&cycle)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return model.ErrDependencyCycle
+	}
+	return nil
+}
+
+// bumpDependents увеличивает версию задач, которые ждут одну из blockerIDs: их открытые
+// блокеры считаются подзапросом, а ETag зависит только от версии. Вызывается в транзакции
+// изменения блокера, как retag; строки блокируются по возрастанию ID.
+func bumpDependents(ctx context.Context, tx pgx.Tx, ws int, blockerIDs ...int) error {
+	if len(blockerIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		WITH locked AS (
+			SELECT id FROM md.tasks
+			WHERE workspace_id = $2 AND id IN (SELECT task_id FROM md.task_dependencies WHERE blocker_id = ANY($1))
+			ORDER BY id
+			FOR UPDATE
+		)
+		UPDATE md.tasks SET version = version + 1
+		WHERE id IN (SELECT id FROM locked)`, blockerIDs, ws)
+	return err
+}
+
+// GetDependencies возвращает живые задачи, которые блокируют id, и те, которые id блокирует.
+func (t *TaskRepository) GetDependencies(ctx context.Context, id int) (blockers, dependents []entity.TaskEntity, err error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return nil, nil, repository.ErrDatabaseUnavailable
+	}
+
+	err = t.inWorkspace(ctx, pool, func(db querier, ws int) error {
+		err := db.QueryRow(ctx,
+			"SELECT 1 FROM md.tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL", id, ws).Scan(new(int))
+		if err != nil {
+			return err
+		}
+		blockers, err = queryTasks(ctx, db, `
+			SELECT `+taskColumns+` FROM md.tasks
+			WHERE workspace_id = $2 AND deleted_at IS NULL
+				AND id IN (SELECT blocker_id FROM md.task_dependencies WHERE task_id = $1)
+			ORDER BY id`, id, ws)
+		if err != nil {
+			return err
+		}
+		dependents, err = queryTasks(ctx, db, `
+			SELECT `+taskColumns+` FROM md.tasks
+			WHERE workspace_id = $2 AND deleted_at IS NULL
+				AND id IN (SELECT task_id FROM md.task_dependencies WHERE blocker_id = $1)
+			ORDER BY id`, id, ws)
+		return err
+	})
+	if err != nil {
+		return nil, nil, taskError("get dependencies", err)
+	}
+	return blockers, dependents, nil
+}
+
+// GetOpenTasks возвращает все незавершённые задачи пространства вне корзины по ID.
+func (t *TaskRepository) GetOpenTasks(ctx context.Context) ([]entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	var tasks []entity.TaskEntity
+	err := t.inWorkspace(ctx, pool, func(db querier, ws int) error {
+		var err error
+		tasks, err = queryTasks(ctx, db, `
+			SELECT `+taskColumns+` FROM md.tasks
+			WHERE workspace_id = $1 AND deleted_at IS NULL AND status <> 'completed'
+			ORDER BY id`, ws)
+		return err
+	})
+	if errors.Is(err, repository.ErrNoWorkspace) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func (t *TaskRepository) dependencyError(op string, id int, err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return repository.ErrTaskNotFound
+	case errors.Is(err, repository.ErrBlockerNotFound), errors.Is(err, model.ErrDependencyCycle), errors.Is(err, repository.ErrNoWorkspace):
+		return err
+	}
+	t.logger.Error("Failed to "+op, "task_id", id, "error", err)
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
@@ -0,0 +1,168 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"myApi/model"
+	"myApi/repository"
+	"myApi/reqctx"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// importColumns — колонки md.tasks, которые заполняет импорт. id берутся из
+// последовательности заранее, чтобы теги и историю пачки тоже записать через COPY.
+var importColumns = []string{
+	"id", "workspace_id", "title", "description", "status", "priority", "version", "created_by",
+	"started_at", "completed_at", "start_at", "due_at", "timezone", "created_at", "updated_at",
+}
+
+// ImportTasks записывает пачки задач через COPY в одной транзакции: на пачку уходит
+// несколько обращений к базе вместо одного на задачу. Ошибка в любой пачке отменяет
+// весь импорт. Задачам пачки проставляются ID и пространство.
+func (t *TaskRepository) ImportTasks(ctx context.Context, next func() ([]model.Task, error)) (int, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return 0, repository.ErrDatabaseUnavailable
+	}
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	imported := 0
+	err = pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if t.rls {
+			if err := setWorkspace(ctx, tx, ws); err != nil {
+				return err
+			}
+		}
+		for {
+			batch, err := next()
+			if err != nil {
+				return err
+			}
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := t.importBatch(ctx, tx, ws, batch); err != nil {
+				return err
+			}
+			imported += len(batch)
+		}
+	})
+	if err != nil {
+		t.logger.Error("Failed to import tasks", "imported_before_rollback", imported, "error", err)
+		return 0, fmt.Errorf("failed to import tasks: %w", err)
+	}
+
+	t.logger.Info("Tasks imported", "count", imported)
+	return imported, nil
+}
+
+func (t *TaskRepository) importBatch(ctx context.Context, tx pgx.Tx, ws int, tasks []model.Task) error {
+	rows, err := tx.Query(ctx, "SELECT nextval(pg_get_serial_sequence('md.tasks', 'id')) FROM generate_series(1, $1)", len(tasks))
+	if err != nil {
+		return err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return fmt.Errorf("reserve task ids: %w", err)
+	}
+
+	tagIDs, err := upsertTagNames(ctx, tx, ws, tasks)
+	if err != nil {
+		return fmt.Errorf("create tags: %w", err)
+	}
+
+	taskRows := make([][]any, len(tasks))
+	auditRows := make([][]any, len(tasks))
+	var tagRows [][]any
+	for i := range tasks {
+		task := &tasks[i]
+		task.ID, task.WorkspaceID = ids[i], ws
+		taskRows[i] = []any{
+			task.ID, ws, task.Title, task.Description, task.Status, task.Priority, task.Version, task.CreatedBy,
+			task.StartedAt, task.CompletedAt, task.StartAt, task.DueAt, task.Timezone, task.CreatedAt, task.UpdatedAt,
+		}
+		for _, name := range task.Tags {
+			tagRows = append(tagRows, []any{task.ID, tagIDs[name]})
+		}
+		auditRows[i] = []any{task.ID, ws, model.AuditImport, reqctx.Actor(ctx), reqctx.RequestID(ctx), model.DiffTasks(nil, task)}
+	}
+
+	if err := t.copyRows(ctx, tx, "tasks", importColumns, taskRows); err != nil {
+		return fmt.Errorf("copy tasks: %w", err)
+	}
+	if err := t.copyRows(ctx, tx, "task_tags", []string{"task_id", "tag_id"}, tagRows); err != nil {
+		return fmt.Errorf("copy task tags: %w", err)
+	}
+	if err := t.copyRows(ctx, tx, "task_audit", []string{"task_id", "workspace_id", "action", "actor", "request_id", "changes"}, auditRows); err != nil {
+		return fmt.Errorf("copy audit entries: %w", err)
+	}
+	return nil
+}
+
+// upsertTagNames создаёт недостающие теги задач пачки и возвращает ID тегов по имени.
+func upsertTagNames(ctx context.Context, tx pgx.Tx, ws int, tasks []model.Task) (map[string]int, error) {
+	seen := make(map[string]bool)
+	var names []string
+	for i := range tasks {
+		for _, name := range tasks[i].Tags {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	ids := make(map[string]int, len(names))
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO md.tags (workspace_id, name)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT (workspace_id, name) DO NOTHING`, ws, names); err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, "SELECT name, id FROM md.tags WHERE workspace_id = $1 AND name = ANY($2)", ws, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var id int
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, err
+		}
+		ids[name] = id
+	}
+	return ids, rows.Err()
+}
+
+// copyRows записывает строки в md.<table> через COPY. COPY FROM не работает с таблицами,
+// на которых включён RLS, поэтому с EnableRowLevelSecurity строки уходят пачкой INSERT —
+// тоже за одно обращение к базе.
+func (t *TaskRepository) copyRows(ctx context.Context, tx pgx.Tx, table string, columns []string, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if !t.rls {
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"md", table}, columns, pgx.CopyFromRows(rows))
+		return err
+	}
+
+	placeholders := make([]string, len(columns))
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := fmt.Sprintf("INSERT INTO md.%s (%s) VALUES (%s)", table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	batch := &pgx.Batch{}
+	for _, row := range rows {
+		batch.Queue(query, row...)
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
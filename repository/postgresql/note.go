@@ -0,0 +1,209 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/db"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const noteColumns = "id, title, body, task_id, workspace_id, created_by, created_at, updated_at"
+
+type NoteRepository struct {
+	dbPool *db.Pool
+	logger *slog.Logger
+	rls    bool
+}
+
+func NewNoteRepository(dbPool *db.Pool, logger *slog.Logger) *NoteRepository {
+	return &NoteRepository{
+		dbPool: dbPool,
+		logger: logger,
+	}
+}
+
+// EnableRowLevelSecurity — см. TaskRepository.EnableRowLevelSecurity.
+func (r *NoteRepository) EnableRowLevelSecurity() {
+	r.rls = true
+}
+
+// ListNotes возвращает страницу заметок пространства, недавно изменённые первыми.
+func (r *NoteRepository) ListNotes(ctx context.Context, filter model.NoteFilter) (entity.NotePage, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.NotePage{}, repository.ErrDatabaseUnavailable
+	}
+
+	page := entity.NotePage{Notes: []entity.NoteEntity{}}
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		where := "workspace_id = $1"
+		args := []any{ws}
+		if filter.TaskID != nil {
+			args = append(args, *filter.TaskID)
+			where += fmt.Sprintf(" AND task_id = $%d", len(args))
+		}
+
+		if err := q.QueryRow(ctx, "SELECT count(*) FROM md.notes WHERE "+where, args...).Scan(&page.Total); err != nil {
+			return err
+		}
+
+		args = append(args, filter.Limit, filter.Offset)
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM md.notes
+			WHERE %s
+			ORDER BY updated_at DESC, id DESC
+			LIMIT $%d OFFSET $%d`, noteColumns, where, len(args)-1, len(args))
+
+		rows, err := q.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			note, err := scanNote(rows)
+			if err != nil {
+				return err
+			}
+			page.Notes = append(page.Notes, note)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNoWorkspace) {
+			return entity.NotePage{}, err
+		}
+		r.logger.Error("Failed to list notes", "error", err)
+		return entity.NotePage{}, fmt.Errorf("failed to list notes: %w", err)
+	}
+	return page, nil
+}
+
+func (r *NoteRepository) GetNote(ctx context.Context, id int) (entity.NoteEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.NoteEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	var note entity.NoteEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		var err error
+		note, err = scanNote(q.QueryRow(ctx, "SELECT "+noteColumns+" FROM md.notes WHERE id = $1 AND workspace_id = $2", id, ws))
+		return err
+	})
+	if err != nil {
+		return entity.NoteEntity{}, noteError("get note", err)
+	}
+	return note, nil
+}
+
+func (r *NoteRepository) CreateNote(ctx context.Context, note model.Note) (entity.NoteEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.NoteEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		INSERT INTO md.notes (title, body, task_id, workspace_id, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + noteColumns
+
+	var created entity.NoteEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		var err error
+		created, err = scanNote(q.QueryRow(ctx, query, note.Title, note.Body, note.TaskID, ws, note.CreatedBy))
+		return err
+	})
+	if err != nil {
+		return entity.NoteEntity{}, noteError("create note", err)
+	}
+
+	r.logger.Info("Note created", "note_id", created.ID, "workspace_id", created.WorkspaceID)
+	return created, nil
+}
+
+// UpdateNote заменяет заголовок, текст и привязку к задаче.
+func (r *NoteRepository) UpdateNote(ctx context.Context, note model.Note) (entity.NoteEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.NoteEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		UPDATE md.notes
+		SET title = $3, body = $4, task_id = $5, updated_at = now()
+		WHERE id = $1 AND workspace_id = $2
+		RETURNING ` + noteColumns
+
+	var updated entity.NoteEntity
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		var err error
+		updated, err = scanNote(q.QueryRow(ctx, query, note.ID, ws, note.Title, note.Body, note.TaskID))
+		return err
+	})
+	if err != nil {
+		return entity.NoteEntity{}, noteError("update note", err)
+	}
+
+	r.logger.Info("Note updated", "note_id", updated.ID)
+	return updated, nil
+}
+
+func (r *NoteRepository) DeleteNote(ctx context.Context, id int) error {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+
+	err := inWorkspace(ctx, pool, r.rls, func(q querier, ws int) error {
+		tag, err := q.Exec(ctx, "DELETE FROM md.notes WHERE id = $1 AND workspace_id = $2", id, ws)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return repository.ErrNoteNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return noteError("delete note", err)
+	}
+
+	r.logger.Info("Note deleted", "note_id", id)
+	return nil
+}
+
+// noteError приводит ошибки драйвера к ошибкам пакета repository.
+func noteError(op string, err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows), errors.Is(err, repository.ErrNoteNotFound):
+		return repository.ErrNoteNotFound
+	case errors.Is(err, repository.ErrNoWorkspace):
+		return err
+	case isForeignKeyViolation(err):
+		return repository.ErrTaskNotFound
+	}
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
+
+func scanNote(row pgx.Row) (entity.NoteEntity, error) {
+	var n entity.NoteEntity
+	err := row.Scan(
+		&n.ID,
+		&n.Title,
+		&n.Body,
+		&n.TaskID,
+		&n.WorkspaceID,
+		&n.CreatedBy,
+		&n.CreatedAt,
+		&n.UpdatedAt,
+	)
+	return n, err
+}
@@ -0,0 +1,134 @@
+package postgresql
+
+import (
+	"fmt"
+	"myApi/model"
+	"strings"
+)
+
+// sortColumns — белый список колонок для ORDER BY; в SQL попадают только эти строки.
+// Заголовки сравниваются побайтно (COLLATE "C"), как в memory.TaskRepository: иначе
+// порядок и курсоры зависели бы от collation базы.
+var sortColumns = map[model.TaskSortField]string{
+	model.SortByCreatedAt: "created_at",
+	model.SortByUpdatedAt: "updated_at",
+	model.SortByPriority:  "priority",
+	model.SortByTitle:     `title COLLATE "C"`,
+	model.SortByID:        "id",
+}
+
+// taskQuery собирает WHERE для md.tasks; значения всегда уходят параметрами.
+type taskQuery struct {
+	conds []string
+	args  []any
+}
+
+// newTaskQuery начинает выборку живых задач пространства ws.
+func newTaskQuery(ws int) *taskQuery {
+	q := &taskQuery{conds: []string{"deleted_at IS NULL"}}
+	q.add("workspace_id = %s", ws)
+	return q
+}
+
+func (q *taskQuery) arg(v any) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *taskQuery) add(cond string, args ...any) {
+	placeholders := make([]any, len(args))
+	for i, a := range args {
+		placeholders[i] = q.arg(a)
+	}
+	q.conds = append(q.conds, fmt.Sprintf(cond, placeholders...))
+}
+
+func (q *taskQuery) applyFilter(f model.TaskFilter) {
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, st := range f.Statuses {
+			statuses[i] = string(st)
+		}
+		q.add("status = ANY(%s)", statuses)
+	}
+	if f.PriorityMin != nil {
+		q.add("priority >= %s", *f.PriorityMin)
+	}
+	if f.PriorityMax != nil {
+		q.add("priority <= %s", *f.PriorityMax)
+	}
+	if f.CreatedFrom != nil {
+		q.add("created_at >= %s", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		q.add("created_at < %s", *f.CreatedTo)
+	}
+	if f.UpdatedFrom != nil {
+		q.add("updated_at >= %s", *f.UpdatedFrom)
+	}
+	if f.UpdatedTo != nil {
+		q.add("updated_at < %s", *f.UpdatedTo)
+	}
+	if f.AssigneeID != nil {
+		q.add("assignee_id = %s", *f.AssigneeID)
+	}
+	if f.Unassigned {
+		q.add("assignee_id IS NULL")
+	}
+	if f.CreatedBy != nil {
+		q.add("created_by = %s", *f.CreatedBy)
+	}
+	if len(f.Tags) > 0 {
+		const tagged = "SELECT %s FROM md.task_tags tt JOIN md.tags g ON g.id = tt.tag_id WHERE tt.task_id = tasks.id AND g.name = ANY(%%s)"
+		if f.TagMatch == model.TagMatchAll {
+			q.add("("+fmt.Sprintf(tagged, "count(*)")+") = %s", f.Tags, len(f.Tags))
+		} else {
+			q.add("EXISTS ("+fmt.Sprintf(tagged, "1")+")", f.Tags)
+		}
+	}
+	if f.Overdue {
+		q.add("due_at < now() AND status <> 'completed'")
+	}
+	if f.DueFrom != nil {
+		q.add("due_at >= %s", *f.DueFrom)
+	}
+	if f.DueTo != nil {
+		q.add("due_at < %s", *f.DueTo)
+	}
+}
+
+// applyCursor добавляет keyset-условие «строго после курсора» в направлении сортировки.
+func (q *taskQuery) applyCursor(f model.TaskFilter) error {
+	if f.Cursor == nil {
+		return nil
+	}
+	op := ">"
+	if f.SortDesc {
+		op = "<"
+	}
+	if f.SortBy == model.SortByID {
+		q.add("id "+op+" %s", f.Cursor.ID)
+		return nil
+	}
+	value, err := f.Cursor.SortValue()
+	if err != nil {
+		return err
+	}
+	q.add("("+sortColumns[f.SortBy]+", id) "+op+" (%s, %s)", value, f.Cursor.ID)
+	return nil
+}
+
+func (q *taskQuery) where() string {
+	return " WHERE " + strings.Join(q.conds, " AND ")
+}
+
+func (q *taskQuery) orderBy(f model.TaskFilter) string {
+	dir := "ASC"
+	if f.SortDesc {
+		dir = "DESC"
+	}
+	if f.SortBy == model.SortByID {
+		return " ORDER BY id " + dir
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", sortColumns[f.SortBy], dir, dir)
+}
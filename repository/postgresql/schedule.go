@@ -0,0 +1,157 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ScheduleTask заменяет плановые даты и зону задачи. Если срок изменился, напоминание
+// о нём будет отправлено заново.
+func (t *TaskRepository) ScheduleTask(ctx context.Context, id int, schedule model.TaskSchedule, version int) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		UPDATE md.tasks
+		SET start_at = $1, due_at = $2, timezone = $3, recurrence = $7,
+			reminded_at = CASE WHEN due_at IS DISTINCT FROM $2 THEN NULL ELSE reminded_at END,
+			version = version + 1, updated_at = now()
+		WHERE id = $4 AND workspace_id = $5 AND deleted_at IS NULL AND ($6 = 0 OR version = $6)
+		RETURNING ` + taskColumns
+
+	task, err := t.modifyAudited(ctx, pool, id, model.AuditSchedule, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		return scanTask(tx.QueryRow(ctx, query, schedule.StartAt, schedule.DueAt, schedule.Timezone, id, ws, version, schedule.Recurrence))
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return entity.TaskEntity{}, repository.ErrTaskNotFound
+		case errors.Is(err, repository.ErrNoWorkspace):
+			return entity.TaskEntity{}, err
+		}
+		t.logger.Error("Failed to schedule task", "task_id", id, "error", err)
+		return entity.TaskEntity{}, fmt.Errorf("failed to schedule task: %w", err)
+	}
+
+	t.logger.Info("Task schedule changed", "task_id", id, "due_at", task.DueAt)
+	return task, nil
+}
+
+// ClaimDueReminders отмечает напоминание отправленным для живых незавершённых задач всех
+// пространств со сроком в (from, to], у которых его ещё не было, и возвращает их по сроку.
+// Строки, захваченные другой репликой, пропускаются (SKIP LOCKED), так что каждое напоминание
+// достаётся одному процессу. История и версия задачи не меняются.
+func (t *TaskRepository) ClaimDueReminders(ctx context.Context, from, to time.Time, limit int) ([]entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		UPDATE md.tasks SET reminded_at = now()
+		WHERE id IN (
+			SELECT id FROM md.tasks
+			WHERE deleted_at IS NULL AND status <> 'completed' AND reminded_at IS NULL
+				AND due_at > $1 AND due_at <= $2
+			ORDER BY due_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + taskColumns
+
+	if !t.rls {
+		tasks, err := queryTasks(ctx, pool, query, from, to, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim reminders: %w", err)
+		}
+		return tasks, nil
+	}
+
+	// Под RLS задачи видны только внутри своего пространства, поэтому обходим их по одному.
+	rows, err := pool.Query(ctx, "SELECT id FROM md.workspaces ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim reminders: %w", err)
+	}
+	workspaces, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim reminders: %w", err)
+	}
+
+	var tasks []entity.TaskEntity
+	for _, ws := range workspaces {
+		if len(tasks) >= limit {
+			break
+		}
+		var claimed []entity.TaskEntity
+		err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+			if err := setWorkspace(ctx, tx, ws); err != nil {
+				return err
+			}
+			var err error
+			claimed, err = queryTasks(ctx, tx, query, from, to, limit-len(tasks))
+			return err
+		})
+		if err != nil {
+			return tasks, fmt.Errorf("failed to claim reminders: %w", err)
+		}
+		tasks = append(tasks, claimed...)
+	}
+	return tasks, nil
+}
+
+// CreateOccurrence создаёт следующий экземпляр повторяющейся задачи prevID с её тегами.
+// Если у prevID уже есть следующий экземпляр, возвращается repository.ErrOccurrenceExists.
+func (t *TaskRepository) CreateOccurrence(ctx context.Context, prevID int, task model.Task) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	created, err := t.modifyAudited(ctx, pool, 0, model.AuditRecur, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		var id int
+		err := tx.QueryRow(ctx, `
+			INSERT INTO md.tasks (title, description, status, priority, created_by, assignee_id, workspace_id,
+				parent_id, start_at, due_at, timezone, recurrence, recurred_from)
+			SELECT $1, $2, $3, $4, $5, $6, workspace_id, $7, $8, $9, $10, $11, id
+			FROM md.tasks WHERE id = $12 AND workspace_id = $13
+			ON CONFLICT (recurred_from) DO NOTHING
+			RETURNING id`,
+			task.Title, task.Description, task.Status, task.Priority, task.CreatedBy, task.AssigneeID,
+			task.ParentID, task.StartAt, task.DueAt, task.Timezone, task.Recurrence, prevID, ws).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.TaskEntity{}, repository.ErrOccurrenceExists
+		}
+		if err != nil {
+			return entity.TaskEntity{}, err
+		}
+		if _, err := tx.Exec(ctx,
+			"INSERT INTO md.task_tags (task_id, tag_id) SELECT $1, tag_id FROM md.task_tags WHERE task_id = $2",
+			id, prevID); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		created, err := scanTask(tx.QueryRow(ctx, "SELECT "+taskColumns+" FROM md.tasks WHERE id = $1", id))
+		if err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return created, bumpAncestors(ctx, tx, ws, created.ParentID)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrOccurrenceExists) || errors.Is(err, repository.ErrNoWorkspace) {
+			return entity.TaskEntity{}, err
+		}
+		t.logger.Error("Failed to create next occurrence", "task_id", prevID, "error", err)
+		return entity.TaskEntity{}, fmt.Errorf("failed to create next occurrence: %w", err)
+	}
+
+	t.logger.Info("Next occurrence created", "task_id", created.ID, "recurred_from", prevID, "due_at", created.DueAt)
+	return created, nil
+}
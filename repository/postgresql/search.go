@@ -0,0 +1,91 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// searchMatch отбирает живые задачи пространства $1, подходящие под запрос $2: по
+// tsvector в обеих конфигурациях или, если $3, по триграммам слов с опечатками.
+// Ранг — ts_rank плюс небольшая добавка за триграммное сходство, чтобы при равном
+// ts_rank выше шли задачи, где слова запроса встречаются точнее.
+const searchMatch = `
+	FROM md.tasks,
+		LATERAL (SELECT websearch_to_tsquery('russian', $2) || websearch_to_tsquery('english', $2) AS query) q
+	WHERE workspace_id = $1 AND deleted_at IS NULL
+		AND (search_vector @@ q.query OR ($3 AND $2 <% (title || ' ' || description)))`
+
+// headlineOptions — ts_headline размечает совпадения маркерами model.HighlightStart/Stop.
+const headlineOptions = "StartSel=" + model.HighlightStart + ", StopSel=" + model.HighlightStop +
+	`, MaxWords=25, MinWords=8, MaxFragments=2, FragmentDelimiter=" … "`
+
+// SearchTasks ищет задачи по заголовку и описанию, лучшие совпадения первыми. Фрагменты
+// строятся в русской конфигурации: она разбирает и кириллицу, и латиницу (последнюю
+// английским стеммером), поэтому подсвечивает слова обоих языков.
+func (t *TaskRepository) SearchTasks(ctx context.Context, search model.TaskSearch) (entity.SearchPage, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.SearchPage{}, repository.ErrDatabaseUnavailable
+	}
+
+	page := entity.SearchPage{Hits: []entity.SearchHitEntity{}}
+	err := t.inWorkspace(ctx, pool, func(db querier, ws int) error {
+		args := []any{ws, search.Query, search.Fuzzy}
+		if err := db.QueryRow(ctx, "SELECT count(*)"+searchMatch, args...).Scan(&page.Total); err != nil {
+			return fmt.Errorf("failed to count search results: %w", err)
+		}
+
+		// taskColumns ссылается на tasks.id, поэтому ранжированный список соединяется с
+		// md.tasks ещё раз уже после LIMIT.
+		query := `
+			SELECT ` + taskColumns + `, hits.rank, hits.title_highlight, hits.snippet
+			FROM md.tasks JOIN (
+				SELECT id AS hit_id,
+					ts_rank(search_vector, q.query) + 0.1 * word_similarity($2, title || ' ' || description) AS rank,
+					ts_headline('russian', title, q.query, $4 || ', HighlightAll=true') AS title_highlight,
+					ts_headline('russian', description, q.query, $4) AS snippet
+				` + searchMatch + `
+				ORDER BY rank DESC, id
+				LIMIT $5 OFFSET $6
+			) hits ON hits.hit_id = tasks.id
+			ORDER BY hits.rank DESC, tasks.id`
+		rows, err := db.Query(ctx, query, append(args, headlineOptions, search.Limit, search.Offset)...)
+		if err != nil {
+			return fmt.Errorf("failed to search tasks: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var hit entity.SearchHitEntity
+			hit.Task, err = scanTask(withExtra(rows, &hit.Rank, &hit.Title, &hit.Snippet))
+			if err != nil {
+				return fmt.Errorf("failed to scan search result: %w", err)
+			}
+			page.Hits = append(page.Hits, hit)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		t.logger.Error("Failed to search tasks", "error", err)
+		return entity.SearchPage{}, err
+	}
+	return page, nil
+}
+
+// extraRow дописывает к назначениям scanTask колонки, выбранные после taskColumns.
+type extraRow struct {
+	pgx.Row
+	extra []any
+}
+
+func withExtra(row pgx.Row, extra ...any) pgx.Row {
+	return extraRow{Row: row, extra: extra}
+}
+
+func (r extraRow) Scan(dest ...any) error {
+	return r.Row.Scan(append(dest, r.extra...)...)
+}
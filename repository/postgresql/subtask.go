@@ -0,0 +1,184 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// hierarchyLockKey — первый ключ pg_advisory_xact_lock(key, workspace_id): перемещения задач
+// внутри пространства выполняются по очереди, иначе два встречных перемещения могут
+// вместе образовать цикл, хотя каждое по отдельности его не создаёт.
+const hierarchyLockKey int32 = 734092117
+
+// MoveTask делает задачу подзадачей parentID; nil делает её корневой. Родитель должен быть
+// живой задачей того же пространства и не может быть самой задачей или её подзадачей.
+// Ненулевая version должна совпадать с версией в базе, иначе возвращается repository.ErrTaskNotFound.
+func (t *TaskRepository) MoveTask(ctx context.Context, id int, parentID *int, version int) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	task, err := t.modifyAudited(ctx, pool, id, model.AuditMove, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		if parentID != nil {
+			if err := checkParent(ctx, tx, id, *parentID, ws); err != nil {
+				return entity.TaskEntity{}, err
+			}
+		}
+		var oldParentID *int
+		if err := tx.QueryRow(ctx, "SELECT parent_id FROM md.tasks WHERE id = $1", id).Scan(&oldParentID); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		moved, err := scanTask(tx.QueryRow(ctx, `
+			UPDATE md.tasks
+			SET parent_id = $1, version = version + 1, updated_at = now()
+			WHERE id = $2 AND workspace_id = $3 AND deleted_at IS NULL AND ($4 = 0 OR version = $4)
+			RETURNING `+taskColumns, parentID, id, ws, version))
+		if err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return moved, bumpAncestors(ctx, tx, ws, oldParentID, parentID)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return entity.TaskEntity{}, repository.ErrTaskNotFound
+		case errors.Is(err, repository.ErrParentNotFound), errors.Is(err, model.ErrTaskCycle), errors.Is(err, repository.ErrNoWorkspace):
+			return entity.TaskEntity{}, err
+		}
+		t.logger.Error("Failed to move task", "task_id", id, "error", err)
+		return entity.TaskEntity{}, fmt.Errorf("failed to move task: %w", err)
+	}
+
+	t.logger.Info("Task parent changed", "task_id", id, "parent_id", parentID)
+	return task, nil
+}
+
+// checkParent проверяет, что parentID можно сделать родителем id: он существует, не в корзине
+// и не лежит в поддереве id. Вызывается в транзакции перемещения.
+func checkParent(ctx context.Context, tx pgx.Tx, id, parentID, ws int) error {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1, $2)", hierarchyLockKey, ws); err != nil {
+		return err
+	}
+
+	var exists bool
+	err := tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM md.tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL)",
+		parentID, ws).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return repository.ErrParentNotFound
+	}
+
+	// Цикл появится, если id — сам parentID или один из его предков.
+	var cycle bool
+	err = tx.QueryRow(ctx, `
+		WITH RECURSIVE up AS (
+			SELECT id, parent_id FROM md.tasks WHERE id = $1
+			UNION
+			SELECT p.id, p.parent_id FROM md.tasks p JOIN up ON p.id = up.parent_id
+		)
+		SELECT EXISTS (SELECT 1 FROM up WHERE id = $2)`, parentID, id).Scan(&cycle)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return model.ErrTaskCycle
+	}
+	return nil
+}
+
+// bumpAncestors увеличивает версию задач parentIDs и всех их предков, каждой один раз.
+// Прогресс подзадач у них считается из потомков, а ETag зависит только от версии, поэтому
+// без этого кэш отдавал бы старый прогресс. Вызывается в транзакции изменения потомка,
+// как retag; updated_at и история не меняются. Строки блокируются по возрастанию ID.
+func bumpAncestors(ctx context.Context, tx pgx.Tx, ws int, parentIDs ...*int) error {
+	var ids []int
+	for _, id := range parentIDs {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		WITH RECURSIVE up AS (
+			SELECT id, parent_id FROM md.tasks WHERE id = ANY($1) AND workspace_id = $2
+			UNION
+			SELECT p.id, p.parent_id FROM md.tasks p JOIN up ON p.id = up.parent_id
+		), locked AS (
+			SELECT id FROM md.tasks WHERE id IN (SELECT id FROM up) ORDER BY id FOR UPDATE
+		)
+		UPDATE md.tasks SET version = version + 1
+		WHERE id IN (SELECT id FROM locked)`, ids, ws)
+	return err
+}
+
+// GetSubtasks возвращает живые подзадачи первого уровня по возрастанию ID.
+func (t *TaskRepository) GetSubtasks(ctx context.Context, id int) ([]entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	var tasks []entity.TaskEntity
+	err := t.inWorkspace(ctx, pool, func(db querier, ws int) error {
+		err := db.QueryRow(ctx,
+			"SELECT 1 FROM md.tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL", id, ws).Scan(new(int))
+		if err != nil {
+			return err
+		}
+		tasks, err = queryTasks(ctx, db, `
+			SELECT `+taskColumns+` FROM md.tasks
+			WHERE parent_id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+			ORDER BY id`, id, ws)
+		return err
+	})
+	if err != nil {
+		return nil, taskError("get subtasks", err)
+	}
+	return tasks, nil
+}
+
+// GetTaskTree возвращает задачу и все её живые подзадачи: сначала корень, затем уровни
+// по глубине, внутри уровня — по ID.
+func (t *TaskRepository) GetTaskTree(ctx context.Context, id int) ([]entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, 0 AS depth FROM md.tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id, tree.depth + 1 FROM md.tasks c JOIN tree ON c.parent_id = tree.id
+			WHERE c.workspace_id = $2 AND c.deleted_at IS NULL
+		)
+		SELECT ` + taskColumns + `
+		FROM md.tasks JOIN tree USING (id)
+		ORDER BY tree.depth, id`
+
+	var tasks []entity.TaskEntity
+	err := t.inWorkspace(ctx, pool, func(db querier, ws int) error {
+		var err error
+		tasks, err = queryTasks(ctx, db, query, id, ws)
+		return err
+	})
+	if err != nil {
+		return nil, taskError("get task tree", err)
+	}
+	if len(tasks) == 0 {
+		return nil, repository.ErrTaskNotFound
+	}
+	return tasks, nil
+}
@@ -0,0 +1,340 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Теги живут в TaskRepository: смена тегов меняет задачи (версию и историю),
+// и это удобнее делать в одной транзакции с md.tasks.
+
+const tagColumns = "g.id, g.workspace_id, g.name, g.created_at"
+
+// ListTags возвращает теги пространства по алфавиту с числом живых задач.
+func (t *TaskRepository) ListTags(ctx context.Context) ([]entity.TagEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		SELECT ` + tagColumns + `, count(tasks.id)
+		FROM md.tags g
+		LEFT JOIN md.task_tags tt ON tt.tag_id = g.id
+		LEFT JOIN md.tasks ON tasks.id = tt.task_id AND tasks.deleted_at IS NULL
+		WHERE g.workspace_id = $1
+		GROUP BY g.id
+		ORDER BY g.name`
+
+	tags := []entity.TagEntity{}
+	err := t.inWorkspace(ctx, pool, func(q querier, ws int) error {
+		rows, err := q.Query(ctx, query, ws)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var tag entity.TagEntity
+			if err := rows.Scan(&tag.ID, &tag.WorkspaceID, &tag.Name, &tag.CreatedAt, &tag.TaskCount); err != nil {
+				return err
+			}
+			tags = append(tags, tag)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, tagError("list tags", err)
+	}
+	return tags, nil
+}
+
+func (t *TaskRepository) CreateTag(ctx context.Context, name string) (entity.TagEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TagEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	var tag entity.TagEntity
+	err := t.inWorkspace(ctx, pool, func(q querier, ws int) error {
+		var err error
+		tag, err = scanTag(q.QueryRow(ctx,
+			"INSERT INTO md.tags AS g (workspace_id, name) VALUES ($1, $2) RETURNING "+tagColumns, ws, name))
+		return err
+	})
+	if err != nil {
+		return entity.TagEntity{}, tagError("create tag", err)
+	}
+
+	t.logger.Info("Tag created", "tag_id", tag.ID, "name", tag.Name)
+	return tag, nil
+}
+
+// RenameTag переименовывает тег; задачи с ним получают новую версию и запись в истории.
+func (t *TaskRepository) RenameTag(ctx context.Context, id int, name string) (entity.TagEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TagEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	var tag entity.TagEntity
+	err := t.retag(ctx, pool, []int{id}, func(tx pgx.Tx, ws int) error {
+		var err error
+		tag, err = scanTag(tx.QueryRow(ctx,
+			"UPDATE md.tags AS g SET name = $3 WHERE id = $1 AND workspace_id = $2 RETURNING "+tagColumns, id, ws, name))
+		if err != nil {
+			return err
+		}
+		tag.TaskCount, err = countTagged(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return entity.TagEntity{}, tagError("rename tag", err)
+	}
+
+	t.logger.Info("Tag renamed", "tag_id", id, "name", name)
+	return tag, nil
+}
+
+// MergeTags переносит тег sourceID на задачи как targetID и удаляет sourceID.
+func (t *TaskRepository) MergeTags(ctx context.Context, sourceID, targetID int) (entity.TagEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TagEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	var target entity.TagEntity
+	err := t.retag(ctx, pool, []int{sourceID}, func(tx pgx.Tx, ws int) error {
+		var err error
+		target, err = scanTag(tx.QueryRow(ctx,
+			"SELECT "+tagColumns+" FROM md.tags g WHERE id = $1 AND workspace_id = $2 FOR UPDATE", targetID, ws))
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO md.task_tags (task_id, tag_id)
+			SELECT task_id, $2 FROM md.task_tags WHERE tag_id = $1
+			ON CONFLICT DO NOTHING`, sourceID, targetID); err != nil {
+			return err
+		}
+		if err := deleteTag(ctx, tx, sourceID, ws); err != nil {
+			return err
+		}
+		target.TaskCount, err = countTagged(ctx, tx, targetID)
+		return err
+	})
+	if err != nil {
+		return entity.TagEntity{}, tagError("merge tags", err)
+	}
+
+	t.logger.Info("Tags merged", "source_id", sourceID, "target_id", targetID)
+	return target, nil
+}
+
+// DeleteTag удаляет тег и снимает его со всех задач.
+func (t *TaskRepository) DeleteTag(ctx context.Context, id int) error {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+
+	err := t.retag(ctx, pool, []int{id}, func(tx pgx.Tx, ws int) error {
+		return deleteTag(ctx, tx, id, ws)
+	})
+	if err != nil {
+		return tagError("delete tag", err)
+	}
+
+	t.logger.Info("Tag deleted", "tag_id", id)
+	return nil
+}
+
+// AttachTags ставит задаче теги по именам, создавая недостающие. Ненулевой version —
+// ожидаемая версия задачи.
+func (t *TaskRepository) AttachTags(ctx context.Context, id int, names []string, version int) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	task, err := t.modifyAudited(ctx, pool, id, model.AuditTag, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		if err := attachTags(ctx, tx, id, ws, names); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return bumpTask(ctx, tx, id, ws, version)
+	})
+	if err != nil {
+		return entity.TaskEntity{}, taskError("attach tags", err)
+	}
+
+	t.logger.Info("Tags attached", "task_id", id, "tags", names)
+	return task, nil
+}
+
+// DetachTags снимает с задачи теги по именам; сами теги остаются.
+func (t *TaskRepository) DetachTags(ctx context.Context, id int, names []string, version int) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	task, err := t.modifyAudited(ctx, pool, id, model.AuditTag, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		if err := detachTags(ctx, tx, id, ws, names); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return bumpTask(ctx, tx, id, ws, version)
+	})
+	if err != nil {
+		return entity.TaskEntity{}, taskError("detach tags", err)
+	}
+
+	t.logger.Info("Tags detached", "task_id", id, "tags", names)
+	return task, nil
+}
+
+// retag выполняет change над тегами tagIDs и записывает в историю каждой задачи,
+// у которой был один из этих тегов, как изменился её список тегов.
+func (t *TaskRepository) retag(ctx context.Context, pool *pgxpool.Pool, tagIDs []int, change func(tx pgx.Tx, ws int) error) error {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if t.rls {
+			if err := setWorkspace(ctx, tx, ws); err != nil {
+				return err
+			}
+		}
+
+		before, err := queryTasks(ctx, tx, `
+			SELECT `+taskColumns+` FROM md.tasks
+			WHERE workspace_id = $1 AND id IN (SELECT task_id FROM md.task_tags WHERE tag_id = ANY($2))
+			ORDER BY id
+			FOR UPDATE`, ws, tagIDs)
+		if err != nil {
+			return err
+		}
+
+		if err := change(tx, ws); err != nil {
+			return err
+		}
+
+		if len(before) == 0 {
+			return nil
+		}
+		ids := make([]int, len(before))
+		for i := range before {
+			ids[i] = before[i].ID
+		}
+		after, err := queryTasks(ctx, tx, `
+			UPDATE md.tasks
+			SET version = version + 1, updated_at = now()
+			WHERE workspace_id = $1 AND id = ANY($2)
+			RETURNING `+taskColumns, ws, ids)
+		if err != nil {
+			return err
+		}
+
+		prev := make(map[int]*model.Task, len(before))
+		for i := range before {
+			prev[before[i].ID] = before[i].ToModel()
+		}
+		for i := range after {
+			if err := insertAudit(ctx, tx, after[i].ID, ws, model.AuditTag, prev[after[i].ID], after[i].ToModel()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// attachTags ставит задаче теги по именам, создавая недостающие теги пространства.
+func attachTags(ctx context.Context, tx pgx.Tx, id, ws int, names []string) error {
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO md.tags (workspace_id, name)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT (workspace_id, name) DO NOTHING`, ws, names); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO md.task_tags (task_id, tag_id)
+		SELECT $1, id FROM md.tags WHERE workspace_id = $2 AND name = ANY($3)
+		ON CONFLICT DO NOTHING`, id, ws, names)
+	return err
+}
+
+func detachTags(ctx context.Context, tx pgx.Tx, id, ws int, names []string) error {
+	_, err := tx.Exec(ctx, `
+		DELETE FROM md.task_tags
+		WHERE task_id = $1 AND tag_id IN (SELECT id FROM md.tags WHERE workspace_id = $2 AND name = ANY($3))`,
+		id, ws, names)
+	return err
+}
+
+// bumpTask увеличивает версию живой задачи после смены её тегов. Если задачи нет, она
+// в корзине или версия не совпала, возвращается pgx.ErrNoRows и транзакция откатывается.
+func bumpTask(ctx context.Context, tx pgx.Tx, id, ws, version int) (entity.TaskEntity, error) {
+	return scanTask(tx.QueryRow(ctx, `
+		UPDATE md.tasks
+		SET version = version + 1, updated_at = now()
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL AND ($3 = 0 OR version = $3)
+		RETURNING `+taskColumns, id, ws, version))
+}
+
+// countTagged — число живых задач с тегом, как в ListTags.
+func countTagged(ctx context.Context, q querier, tagID int) (int, error) {
+	var n int
+	err := q.QueryRow(ctx, `
+		SELECT count(*) FROM md.task_tags tt JOIN md.tasks ON tasks.id = tt.task_id
+		WHERE tt.tag_id = $1 AND tasks.deleted_at IS NULL`, tagID).Scan(&n)
+	return n, err
+}
+
+func deleteTag(ctx context.Context, tx pgx.Tx, id, ws int) error {
+	tag, err := tx.Exec(ctx, "DELETE FROM md.tags WHERE id = $1 AND workspace_id = $2", id, ws)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrTagNotFound
+	}
+	return nil
+}
+
+// tagError приводит ошибки операций над тегами к ошибкам пакета repository.
+func tagError(op string, err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows), errors.Is(err, repository.ErrTagNotFound):
+		return repository.ErrTagNotFound
+	case errors.Is(err, repository.ErrNoWorkspace):
+		return err
+	case isUniqueViolation(err):
+		return repository.ErrTagExists
+	}
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
+
+// taskError — то же для операций над одной задачей (её тегами, подзадачами, зависимостями):
+// здесь «не найдено» — задача.
+func taskError(op string, err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return repository.ErrTaskNotFound
+	case errors.Is(err, repository.ErrNoWorkspace):
+		return err
+	}
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
+
+func scanTag(row pgx.Row) (entity.TagEntity, error) {
+	var tag entity.TagEntity
+	err := row.Scan(&tag.ID, &tag.WorkspaceID, &tag.Name, &tag.CreatedAt)
+	return tag, err
+}
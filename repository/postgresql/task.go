@@ -7,17 +7,48 @@ import (
 	"log/slog"
 	"myApi/db"
 	"myApi/db/entity"
-	"myApi/dto"
 	"myApi/model"
+	"myApi/repository"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 )
 
-var ErrDatabaseUnavailable = errors.New("database connection not available")
+// taskColumns заканчивается подзапросами тегов, подзадач и блокеров, поэтому запросы
+// обращаются к md.tasks без псевдонима: подзапросы ссылаются на tasks.id.
+const taskColumns = "id, title, description, status, priority, version, created_at, updated_at, started_at, completed_at, deleted_at, created_by, assignee_id, workspace_id, parent_id, start_at, due_at, timezone, reminded_at, recurrence, recurred_from, " +
+	taskTagsColumn + ", " + taskSubtasksColumn + ", " + taskBlockersColumns
 
+const taskTagsColumn = `ARRAY(
+		SELECT g.name FROM md.task_tags tt JOIN md.tags g ON g.id = tt.tag_id
+		WHERE tt.task_id = tasks.id ORDER BY g.name
+	) AS tags`
+
+// taskSubtasksColumn — число живых подзадач всех уровней и завершённых среди них.
+const taskSubtasksColumn = `(
+		WITH RECURSIVE sub AS (
+			SELECT c.id, c.status FROM md.tasks c WHERE c.parent_id = tasks.id AND c.deleted_at IS NULL
+			UNION
+			SELECT c.id, c.status FROM md.tasks c JOIN sub ON c.parent_id = sub.id WHERE c.deleted_at IS NULL
+		)
+		SELECT ARRAY[count(*), count(*) FILTER (WHERE sub.status = 'completed')] FROM sub
+	) AS subtasks`
+
+// taskBlockersColumns — все блокеры задачи и те из них, что ещё открыты.
+const taskBlockersColumns = `ARRAY(
+		SELECT d.blocker_id FROM md.task_dependencies d WHERE d.task_id = tasks.id ORDER BY d.blocker_id
+	) AS blocked_by, ARRAY(
+		SELECT d.blocker_id FROM md.task_dependencies d JOIN md.tasks b ON b.id = d.blocker_id
+		WHERE d.task_id = tasks.id AND b.deleted_at IS NULL AND b.status <> 'completed'
+		ORDER BY d.blocker_id
+	) AS open_blockers`
+
+// TaskRepository ограничивает каждый запрос рабочим пространством из контекста
+// (reqctx.Workspace); задачи других пространств для него не существуют.
 type TaskRepository struct {
 	dbPool *db.Pool
 	logger *slog.Logger
+	rls    bool
 }
 
 func NewTaskRepository(dbPool *db.Pool, logger *slog.Logger) *TaskRepository {
@@ -27,47 +58,57 @@ func NewTaskRepository(dbPool *db.Pool, logger *slog.Logger) *TaskRepository {
 	}
 }
 
-func (t *TaskRepository) GetAllTasks(ctx context.Context) ([]entity.TaskEntity, error) {
+// EnableRowLevelSecurity включает выставление app.workspace_id для политик RLS из миграции
+// 0009. Нужно, если RLS на md.tasks включён и сервер подключается не владельцем таблиц.
+func (t *TaskRepository) EnableRowLevelSecurity() {
+	t.rls = true
+}
+
+func (t *TaskRepository) GetAllTasks(ctx context.Context, filter model.TaskFilter) (entity.TaskPage, error) {
 	pool := t.dbPool.GetPool()
 	if pool == nil {
 		t.logger.Warn("Attempted to get tasks but database is unavailable")
-		return nil, ErrDatabaseUnavailable
+		return entity.TaskPage{}, repository.ErrDatabaseUnavailable
 	}
+	var (
+		total int
+		tasks []entity.TaskEntity
+	)
+	err := t.inWorkspace(ctx, pool, func(db querier, ws int) error {
+		q := newTaskQuery(ws)
+		q.applyFilter(filter)
 
-	query := `
-		SELECT id, title, description, status, priority, created_at, updated_at
-		FROM md.tasks
-		ORDER BY created_at DESC
-	`
+		if err := db.QueryRow(ctx, "SELECT count(*) FROM md.tasks"+q.where(), q.args...).Scan(&total); err != nil {
+			t.logger.Error("Failed to count tasks", "error", err)
+			return fmt.Errorf("failed to count tasks: %w", err)
+		}
 
-	rows, err := pool.Query(ctx, query)
-	if err != nil {
-		t.logger.Error("Failed to query tasks", "error", err)
-		return nil, fmt.Errorf("failed to get tasks: %w", err)
-	}
-	defer rows.Close()
+		if err := q.applyCursor(filter); err != nil {
+			return err
+		}
+		query := "SELECT " + taskColumns + " FROM md.tasks" + q.where() + q.orderBy(filter) +
+			fmt.Sprintf(" LIMIT %s OFFSET %s", q.arg(filter.Limit+1), q.arg(filter.Offset))
 
-	var tasks []entity.TaskEntity
-	for rows.Next() {
-		var task entity.TaskEntity
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.Priority,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
+		var err error
+		tasks, err = queryTasks(ctx, db, query, q.args...)
 		if err != nil {
-			t.logger.Error("Failed to scan task", "error", err)
-			return nil, fmt.Errorf("failed to scan task: %w", err)
+			t.logger.Error("Failed to query tasks", "error", err)
+			return fmt.Errorf("failed to get tasks: %w", err)
 		}
-		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return entity.TaskPage{}, err
 	}
 
-	t.logger.Info("Retrieved tasks", "count", len(tasks))
-	return tasks, rows.Err()
+	page := entity.TaskPage{Tasks: tasks, Total: total}
+	if len(tasks) > filter.Limit {
+		page.Tasks = tasks[:filter.Limit]
+		page.HasMore = true
+	}
+
+	t.logger.Info("Retrieved tasks", "count", len(page.Tasks), "total", total)
+	return page, nil
 }
 
 func (t *TaskRepository) CreateTask(ctx context.Context, task model.Task) (entity.TaskEntity, error) {
@@ -76,30 +117,34 @@ func (t *TaskRepository) CreateTask(ctx context.Context, task model.Task) (entit
 		t.logger.Warn("Attempted to create task but database is unavailable",
 			"title", task.Title,
 		)
-		return entity.TaskEntity{}, ErrDatabaseUnavailable
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
 	}
 
 	query := `
-		INSERT INTO md.tasks (title, description, status, priority)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, title, description, status, priority, created_at, updated_at
-	`
+		INSERT INTO md.tasks (title, description, status, priority, created_by, assignee_id, workspace_id, parent_id, start_at, due_at, timezone, recurrence)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING ` + taskColumns
 
-	var taskEntity entity.TaskEntity
-	err := pool.QueryRow(ctx, query,
-		task.Title,
-		task.Description,
-		task.Status,
-		task.Priority,
-	).Scan(
-		&taskEntity.ID,
-		&taskEntity.Title,
-		&taskEntity.Description,
-		&taskEntity.Status,
-		&taskEntity.Priority,
-		&taskEntity.CreatedAt,
-		&taskEntity.UpdatedAt,
-	)
+	taskEntity, err := t.modifyAudited(ctx, pool, 0, model.AuditCreate, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		created, err := scanTask(tx.QueryRow(ctx, query,
+			task.Title,
+			task.Description,
+			task.Status,
+			task.Priority,
+			task.CreatedBy,
+			task.AssigneeID,
+			ws,
+			task.ParentID,
+			task.StartAt,
+			task.DueAt,
+			task.Timezone,
+			task.Recurrence,
+		))
+		if err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return created, bumpAncestors(ctx, tx, ws, created.ParentID)
+	})
 
 	if err != nil {
 		t.logger.Error("Failed to create task",
@@ -116,57 +161,396 @@ func (t *TaskRepository) CreateTask(ctx context.Context, task model.Task) (entit
 
 	return taskEntity, nil
 }
-func (t *TaskRepository) UpdateTask(ctx context.Context, task dto.UpdateTaskRequest) (entity.TaskEntity, error) {
+
+// UpdateTask перезаписывает title, description и priority. Ненулевой task.Version
+// должен совпадать с версией в базе, иначе возвращается repository.ErrTaskNotFound.
+func (t *TaskRepository) UpdateTask(ctx context.Context, task model.Task) (entity.TaskEntity, error) {
 	pool := t.dbPool.GetPool()
 	if pool == nil {
-		return entity.TaskEntity{}, ErrDatabaseUnavailable
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.TaskEntity{}, err
 	}
 	query := `
 				update md.tasks
-				set title=$1, description=$2, status=$3, priority=$4
-				where id=$5
-				returning id, title, description, status, priority, created_at, updated_at`
+				set title=$1, description=$2, priority=$3, version=version+1, updated_at=now()
+				where id=$4 and workspace_id=$6 and deleted_at is null and ($5 = 0 or version=$5)
+				returning ` + taskColumns
 
-	var taskEntity entity.TaskEntity
-	err := pool.QueryRow(ctx, query,
-		task.ID,
+	taskEntity, err := t.execAudited(ctx, pool, task.ID, model.AuditUpdate, query,
 		task.Title,
 		task.Description,
 		task.Priority,
-	).Scan(
-		&taskEntity.ID,
-		&taskEntity.Title,
-		&taskEntity.Description,
-		&taskEntity.Status,
-		&taskEntity.Priority,
-		&taskEntity.CreatedAt,
-		&taskEntity.UpdatedAt)
+		task.ID,
+		task.Version,
+		ws,
+	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.TaskEntity{}, repository.ErrTaskNotFound
+		}
 		return entity.TaskEntity{}, fmt.Errorf("failed to update task: %w", err)
 	}
 	return taskEntity, nil
 }
-func (t *TaskRepository) GetTaskById(ctx context.Context, id string) (entity.TaskEntity, error) {
+
+// PatchTask меняет только поля из patch.Set. Условия patch.Test и patch.Version добавляются
+// в WHERE, поэтому проверка и запись атомарны; при несовпадении возвращается repository.ErrTaskNotFound.
+func (t *TaskRepository) PatchTask(ctx context.Context, id int, patch model.TaskPatch) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.TaskEntity{}, err
+	}
+	q := newTaskQuery(ws)
+	var sets []string
+	if patch.Set.Title != nil {
+		sets = append(sets, "title = "+q.arg(*patch.Set.Title))
+	}
+	if patch.Set.Description != nil {
+		sets = append(sets, "description = "+q.arg(*patch.Set.Description))
+	}
+	if patch.Set.Priority != nil {
+		sets = append(sets, "priority = "+q.arg(*patch.Set.Priority))
+	}
+	sets = append(sets, "version = version + 1", "updated_at = now()")
+
+	q.add("id = %s", id)
+	if patch.Version > 0 {
+		q.add("version = %s", patch.Version)
+	}
+	if patch.Test.Title != nil {
+		q.add("title = %s", *patch.Test.Title)
+	}
+	if patch.Test.Description != nil {
+		q.add("description = %s", *patch.Test.Description)
+	}
+	if patch.Test.Priority != nil {
+		q.add("priority = %s", *patch.Test.Priority)
+	}
+
+	query := "UPDATE md.tasks SET " + strings.Join(sets, ", ") + q.where() + " RETURNING " + taskColumns
+
+	task, err := t.execAudited(ctx, pool, id, model.AuditPatch, query, q.args...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.TaskEntity{}, repository.ErrTaskNotFound
+		}
+		t.logger.Error("Failed to patch task", "task_id", id, "error", err)
+		return entity.TaskEntity{}, fmt.Errorf("failed to patch task: %w", err)
+	}
+
+	t.logger.Info("Task patched", "task_id", id, "fields", len(sets)-2)
+	return task, nil
+}
+
+// UpdateTaskStatus сохраняет результат перехода Workflow. Строка меняется, только если
+// статус и версия в базе всё ещё равны from и task.Version, иначе возвращается model.ErrInvalidTransition.
+func (t *TaskRepository) UpdateTaskStatus(ctx context.Context, task model.Task, from model.TaskStatus) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		UPDATE md.tasks
+		SET status = $1, started_at = $2, completed_at = $3, version = version + 1, updated_at = now()
+		WHERE id = $4 AND status = $5 AND version = $6 AND workspace_id = $7 AND deleted_at IS NULL
+		RETURNING ` + taskColumns
+
+	updated, err := t.modifyAudited(ctx, pool, task.ID, model.AuditStatus, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		updated, err := scanTask(tx.QueryRow(ctx, query,
+			task.Status,
+			task.StartedAt,
+			task.CompletedAt,
+			task.ID,
+			from,
+			task.Version,
+			ws,
+		))
+		if err != nil || !completionChanged(string(from), updated.Status) {
+			return updated, err
+		}
+		if err := bumpAncestors(ctx, tx, ws, updated.ParentID); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return updated, bumpDependents(ctx, tx, ws, updated.ID)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.TaskEntity{}, fmt.Errorf("%w: task status changed concurrently", model.ErrInvalidTransition)
+		}
+		t.logger.Error("Failed to update task status", "task_id", task.ID, "error", err)
+		return entity.TaskEntity{}, fmt.Errorf("failed to update task status: %w", err)
+	}
+
+	t.logger.Info("Task status changed", "task_id", task.ID, "from", from, "to", updated.Status)
+	return updated, nil
+}
+
+// AssignTask назначает исполнителя; nil снимает назначение. Ненулевая version должна
+// совпадать с версией в базе, иначе возвращается repository.ErrTaskNotFound.
+func (t *TaskRepository) AssignTask(ctx context.Context, id int, assigneeID *int, version int) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		UPDATE md.tasks
+		SET assignee_id = $1, version = version + 1, updated_at = now()
+		WHERE id = $2 AND workspace_id = $4 AND deleted_at IS NULL AND ($3 = 0 OR version = $3)
+		RETURNING ` + taskColumns
+
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return entity.TaskEntity{}, err
+	}
+	task, err := t.execAudited(ctx, pool, id, model.AuditAssign, query, assigneeID, id, version, ws)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.TaskEntity{}, repository.ErrTaskNotFound
+		}
+		if isForeignKeyViolation(err) {
+			return entity.TaskEntity{}, repository.ErrUserNotFound
+		}
+		t.logger.Error("Failed to assign task", "task_id", id, "error", err)
+		return entity.TaskEntity{}, fmt.Errorf("failed to assign task: %w", err)
+	}
+
+	t.logger.Info("Task assignee changed", "task_id", id, "assignee_id", assigneeID)
+	return task, nil
+}
+
+func (t *TaskRepository) GetTaskById(ctx context.Context, id int) (entity.TaskEntity, error) {
 	pool := t.dbPool.GetPool()
 	if pool == nil {
-		return entity.TaskEntity{}, ErrDatabaseUnavailable
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
 	}
-	uq := "select * from md.tasks where id=$1;"
+	query := `
+		SELECT ` + taskColumns + `
+		FROM md.tasks
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL`
+
 	var task entity.TaskEntity
-	err := pool.QueryRow(ctx, uq, id).Scan(
-		task.ID,
-		task.Title,
-		task.Description,
-		task.Status,
-		task.UpdatedAt,
-		task.CreatedAt,
-		task.Priority,
-	)
+	err := t.inWorkspace(ctx, pool, func(db querier, ws int) error {
+		var err error
+		task, err = scanTask(db.QueryRow(ctx, query, id, ws))
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return entity.TaskEntity{}, fmt.Errorf("task not found: %w", err)
+			return entity.TaskEntity{}, repository.ErrTaskNotFound
+		}
+		if errors.Is(err, repository.ErrNoWorkspace) {
+			return entity.TaskEntity{}, err
 		}
 		return entity.TaskEntity{}, fmt.Errorf("failed to get task: %w", err)
 	}
 	return task, nil
 }
+
+// GetDeletedTasks возвращает задачи из корзины, последние удалённые первыми.
+func (t *TaskRepository) GetDeletedTasks(ctx context.Context) ([]entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		t.logger.Warn("Attempted to get trash but database is unavailable")
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		SELECT ` + taskColumns + `
+		FROM md.tasks
+		WHERE workspace_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	var tasks []entity.TaskEntity
+	err := t.inWorkspace(ctx, pool, func(db querier, ws int) error {
+		var err error
+		tasks, err = queryTasks(ctx, db, query, ws)
+		return err
+	})
+	if errors.Is(err, repository.ErrNoWorkspace) {
+		return nil, err
+	}
+	if err != nil {
+		t.logger.Error("Failed to query deleted tasks", "error", err)
+		return nil, fmt.Errorf("failed to get deleted tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// DeleteTask помечает задачу удалённой, сама строка остаётся в md.tasks до PurgeTask.
+func (t *TaskRepository) DeleteTask(ctx context.Context, id int) error {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		UPDATE md.tasks
+		SET deleted_at = now(), version = version + 1
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+		RETURNING ` + taskColumns
+
+	_, err := t.modifyAudited(ctx, pool, id, model.AuditDelete, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		deleted, err := scanTask(tx.QueryRow(ctx, query, id, ws))
+		if err != nil {
+			return entity.TaskEntity{}, err
+		}
+		if err := bumpAncestors(ctx, tx, ws, deleted.ParentID); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return deleted, bumpDependents(ctx, tx, ws, id)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrTaskNotFound
+		}
+		t.logger.Error("Failed to delete task", "task_id", id, "error", err)
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	t.logger.Info("Task moved to trash", "task_id", id)
+	return nil
+}
+
+// RestoreTask возвращает задачу из корзины.
+func (t *TaskRepository) RestoreTask(ctx context.Context, id int) (entity.TaskEntity, error) {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return entity.TaskEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		UPDATE md.tasks
+		SET deleted_at = NULL, version = version + 1
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NOT NULL
+		RETURNING ` + taskColumns
+
+	task, err := t.modifyAudited(ctx, pool, id, model.AuditRestore, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		restored, err := scanTask(tx.QueryRow(ctx, query, id, ws))
+		if err != nil {
+			return entity.TaskEntity{}, err
+		}
+		if err := bumpAncestors(ctx, tx, ws, restored.ParentID); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return restored, bumpDependents(ctx, tx, ws, id)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.TaskEntity{}, repository.ErrTaskNotFound
+		}
+		t.logger.Error("Failed to restore task", "task_id", id, "error", err)
+		return entity.TaskEntity{}, fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	t.logger.Info("Task restored from trash", "task_id", id)
+	return task, nil
+}
+
+// PurgeTask окончательно удаляет задачу. Удалить можно только то, что уже лежит в корзине.
+func (t *TaskRepository) PurgeTask(ctx context.Context, id int) error {
+	pool := t.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+
+	query := "DELETE FROM md.tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NOT NULL RETURNING " + taskColumns
+
+	_, err := t.modifyAudited(ctx, pool, id, model.AuditPurge, func(tx pgx.Tx, ws int) (entity.TaskEntity, error) {
+		// ON DELETE SET NULL делает подзадачи корневыми, а ON DELETE CASCADE убирает задачу
+		// из блокеров зависимых, поэтому их версии тоже меняются.
+		if _, err := tx.Exec(ctx,
+			"UPDATE md.tasks SET version = version + 1 WHERE parent_id = $1 AND workspace_id = $2",
+			id, ws); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		if err := bumpDependents(ctx, tx, ws, id); err != nil {
+			return entity.TaskEntity{}, err
+		}
+		return scanTask(tx.QueryRow(ctx, query, id, ws))
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrTaskNotFound
+		}
+		t.logger.Error("Failed to purge task", "task_id", id, "error", err)
+		return fmt.Errorf("failed to purge task: %w", err)
+	}
+
+	t.logger.Info("Task purged", "task_id", id)
+	return nil
+}
+
+// completionChanged сообщает, завершилась задача или открылась снова: от этого зависят
+// прогресс её предков и открытые блокеры зависимых задач.
+func completionChanged(from, to string) bool {
+	return (from == string(model.StatusCompleted)) != (to == string(model.StatusCompleted))
+}
+
+func queryTasks(ctx context.Context, db querier, query string, args ...any) ([]entity.TaskEntity, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []entity.TaskEntity
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// scanTask читает строку в порядке колонок taskColumns.
+func scanTask(row pgx.Row) (entity.TaskEntity, error) {
+	var (
+		task     entity.TaskEntity
+		subtasks []int
+	)
+	err := row.Scan(
+		&task.ID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.Priority,
+		&task.Version,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.StartedAt,
+		&task.CompletedAt,
+		&task.DeletedAt,
+		&task.CreatedBy,
+		&task.AssigneeID,
+		&task.WorkspaceID,
+		&task.ParentID,
+		&task.StartAt,
+		&task.DueAt,
+		&task.Timezone,
+		&task.RemindedAt,
+		&task.Recurrence,
+		&task.RecurredFrom,
+		&task.Tags,
+		&subtasks,
+		&task.BlockedBy,
+		&task.OpenBlockers,
+	)
+	if err == nil && len(subtasks) == 2 {
+		task.SubtasksTotal, task.SubtasksCompleted = subtasks[0], subtasks[1]
+	}
+	return task, err
+}
@@ -0,0 +1,60 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"myApi/repository"
+	"myApi/reqctx"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier — общее у пула и транзакции.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// workspaceID возвращает рабочее пространство запроса. Без него репозиторий задач
+// ничего не читает и не пишет.
+func workspaceID(ctx context.Context) (int, error) {
+	id, ok := reqctx.Workspace(ctx)
+	if !ok {
+		return 0, repository.ErrNoWorkspace
+	}
+	return id, nil
+}
+
+// inWorkspace выполняет fn в пространстве запроса. Условие workspace_id запросы добавляют
+// сами; с включённым RLS они вдобавок идут в транзакции с выставленным app.workspace_id.
+func (t *TaskRepository) inWorkspace(ctx context.Context, pool *pgxpool.Pool, fn func(q querier, ws int) error) error {
+	return inWorkspace(ctx, pool, t.rls, fn)
+}
+
+func inWorkspace(ctx context.Context, pool *pgxpool.Pool, rls bool, fn func(q querier, ws int) error) error {
+	ws, err := workspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	if !rls {
+		return fn(pool, ws)
+	}
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if err := setWorkspace(ctx, tx, ws); err != nil {
+			return err
+		}
+		return fn(tx, ws)
+	})
+}
+
+// setWorkspace выставляет app.workspace_id до конца транзакции для политик RLS.
+func setWorkspace(ctx context.Context, tx pgx.Tx, ws int) error {
+	if _, err := tx.Exec(ctx, "SELECT set_config('app.workspace_id', $1, true)", strconv.Itoa(ws)); err != nil {
+		return fmt.Errorf("failed to set workspace: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/db"
+	"myApi/db/entity"
+	"myApi/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TokenRepository хранит выданные refresh-токены и отозванные access-токены.
+type TokenRepository struct {
+	dbPool *db.Pool
+	logger *slog.Logger
+}
+
+func NewTokenRepository(dbPool *db.Pool, logger *slog.Logger) *TokenRepository {
+	return &TokenRepository{
+		dbPool: dbPool,
+		logger: logger,
+	}
+}
+
+func (r *TokenRepository) SaveRefreshToken(ctx context.Context, jti string, userID int, expiresAt time.Time) error {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+
+	_, err := pool.Exec(ctx,
+		"INSERT INTO md.refresh_tokens (jti, user_id, expires_at) VALUES ($1, $2, $3)",
+		jti, userID, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeRefreshToken отзывает refresh-токен и возвращает его состояние до отзыва:
+// RevokedAt != nil значит, что токен уже использовали.
+func (r *TokenRepository) RevokeRefreshToken(ctx context.Context, jti string) (entity.RefreshTokenEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.RefreshTokenEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		WITH prev AS (
+			SELECT jti, user_id, expires_at, revoked_at, created_at
+			FROM md.refresh_tokens
+			WHERE jti = $1
+			FOR UPDATE
+		)
+		UPDATE md.refresh_tokens r
+		SET revoked_at = COALESCE(r.revoked_at, now())
+		FROM prev
+		WHERE r.jti = prev.jti
+		RETURNING prev.jti, prev.user_id, prev.expires_at, prev.revoked_at, prev.created_at`
+
+	var token entity.RefreshTokenEntity
+	err := pool.QueryRow(ctx, query, jti).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.RefreshTokenEntity{}, repository.ErrTokenNotFound
+		}
+		return entity.RefreshTokenEntity{}, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *TokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID int) error {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+
+	tag, err := pool.Exec(ctx,
+		"UPDATE md.refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL",
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	r.logger.Warn("Revoked all refresh tokens of user", "user_id", userID, "count", tag.RowsAffected())
+	return nil
+}
+
+// RevokeAccessToken заносит jti в список отозванных и заодно чистит записи с истёкшим сроком.
+func (r *TokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("INSERT INTO md.revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING", jti, expiresAt)
+	batch.Queue("DELETE FROM md.revoked_tokens WHERE expires_at < now()")
+	if err := pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	return nil
+}
+
+func (r *TokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return false, repository.ErrDatabaseUnavailable
+	}
+
+	var revoked bool
+	err := pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM md.revoked_tokens WHERE jti = $1)", jti).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check token: %w", err)
+	}
+	return revoked, nil
+}
@@ -0,0 +1,194 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/db"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const userColumns = "id, email, name, password_hash, role, created_at, updated_at"
+
+// SQLSTATE нарушений ограничений.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+type UserRepository struct {
+	dbPool *db.Pool
+	logger *slog.Logger
+}
+
+func NewUserRepository(dbPool *db.Pool, logger *slog.Logger) *UserRepository {
+	return &UserRepository{
+		dbPool: dbPool,
+		logger: logger,
+	}
+}
+
+// createUserQuery добавляет пользователя; пока администраторов нет, новый пользователь
+// становится администратором.
+const createUserQuery = `
+	INSERT INTO md.users (email, name, password_hash, role)
+	VALUES ($1, $2, $3, CASE WHEN EXISTS (SELECT 1 FROM md.users WHERE role = 'admin') THEN $4 ELSE 'admin' END)
+	RETURNING ` + userColumns
+
+func (u *UserRepository) CreateUser(ctx context.Context, user model.User) (entity.UserEntity, error) {
+	pool := u.dbPool.GetPool()
+	if pool == nil {
+		return entity.UserEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	created, err := scanUser(pool.QueryRow(ctx, createUserQuery, user.Email, user.Name, user.PasswordHash, string(user.Role)))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.UserEntity{}, repository.ErrUserExists
+		}
+		u.logger.Error("Failed to create user", "error", err)
+		return entity.UserEntity{}, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	u.logger.Info("User registered", "user_id", created.ID, "role", created.Role)
+	return created, nil
+}
+
+// CreateUserWithWorkspace создаёт пользователя и его личное пространство в одной транзакции,
+// чтобы сбой не оставил пользователя без пространства.
+func (u *UserRepository) CreateUserWithWorkspace(ctx context.Context, user model.User, workspaceName string) (entity.UserEntity, entity.WorkspaceEntity, error) {
+	pool := u.dbPool.GetPool()
+	if pool == nil {
+		return entity.UserEntity{}, entity.WorkspaceEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	var (
+		created entity.UserEntity
+		ws      entity.WorkspaceEntity
+	)
+	err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		var err error
+		created, err = scanUser(tx.QueryRow(ctx, createUserQuery, user.Email, user.Name, user.PasswordHash, string(user.Role)))
+		if err != nil {
+			return err
+		}
+		ws, err = scanWorkspace(tx.QueryRow(ctx,
+			"INSERT INTO md.workspaces (name, created_by) VALUES ($1, $2) RETURNING "+workspaceColumns,
+			workspaceName, created.ID,
+		))
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, "INSERT INTO md.workspace_members (workspace_id, user_id) VALUES ($1, $2)", ws.ID, created.ID)
+		return err
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.UserEntity{}, entity.WorkspaceEntity{}, repository.ErrUserExists
+		}
+		u.logger.Error("Failed to create user", "error", err)
+		return entity.UserEntity{}, entity.WorkspaceEntity{}, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	u.logger.Info("User registered", "user_id", created.ID, "role", created.Role, "workspace_id", ws.ID)
+	return created, ws, nil
+}
+
+func (u *UserRepository) GetUserByEmail(ctx context.Context, email string) (entity.UserEntity, error) {
+	return u.getUser(ctx, "email = $1", email)
+}
+
+func (u *UserRepository) GetUserByID(ctx context.Context, id int) (entity.UserEntity, error) {
+	return u.getUser(ctx, "id = $1", id)
+}
+
+func (u *UserRepository) ListUsers(ctx context.Context) ([]entity.UserEntity, error) {
+	pool := u.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	rows, err := pool.Query(ctx, "SELECT "+userColumns+" FROM md.users ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []entity.UserEntity{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, nil
+}
+
+func (u *UserRepository) SetUserRole(ctx context.Context, id int, role model.Role) (entity.UserEntity, error) {
+	pool := u.dbPool.GetPool()
+	if pool == nil {
+		return entity.UserEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	query := "UPDATE md.users SET role = $2, updated_at = now() WHERE id = $1 RETURNING " + userColumns
+	user, err := scanUser(pool.QueryRow(ctx, query, id, string(role)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.UserEntity{}, repository.ErrUserNotFound
+		}
+		return entity.UserEntity{}, fmt.Errorf("failed to set user role: %w", err)
+	}
+
+	u.logger.Info("User role changed", "user_id", id, "role", role)
+	return user, nil
+}
+
+func (u *UserRepository) getUser(ctx context.Context, cond string, arg any) (entity.UserEntity, error) {
+	pool := u.dbPool.GetPool()
+	if pool == nil {
+		return entity.UserEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	user, err := scanUser(pool.QueryRow(ctx, "SELECT "+userColumns+" FROM md.users WHERE "+cond, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.UserEntity{}, repository.ErrUserNotFound
+		}
+		return entity.UserEntity{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+func scanUser(row pgx.Row) (entity.UserEntity, error) {
+	var user entity.UserEntity
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Name,
+		&user.PasswordHash,
+		&user.Role,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	return user, err
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation
+}
@@ -0,0 +1,201 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/db"
+	"myApi/db/entity"
+	"myApi/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const workspaceColumns = "id, name, created_by, created_at"
+
+type WorkspaceRepository struct {
+	dbPool *db.Pool
+	logger *slog.Logger
+}
+
+func NewWorkspaceRepository(dbPool *db.Pool, logger *slog.Logger) *WorkspaceRepository {
+	return &WorkspaceRepository{
+		dbPool: dbPool,
+		logger: logger,
+	}
+}
+
+// CreateWorkspace создаёт пространство и делает ownerID его участником в одной транзакции.
+func (r *WorkspaceRepository) CreateWorkspace(ctx context.Context, name string, ownerID int) (entity.WorkspaceEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.WorkspaceEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	var ws entity.WorkspaceEntity
+	err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		var err error
+		ws, err = scanWorkspace(tx.QueryRow(ctx,
+			"INSERT INTO md.workspaces (name, created_by) VALUES ($1, $2) RETURNING "+workspaceColumns,
+			name, ownerID,
+		))
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, "INSERT INTO md.workspace_members (workspace_id, user_id) VALUES ($1, $2)", ws.ID, ownerID)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("Failed to create workspace", "owner_id", ownerID, "error", err)
+		return entity.WorkspaceEntity{}, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	r.logger.Info("Workspace created", "workspace_id", ws.ID, "owner_id", ownerID)
+	return ws, nil
+}
+
+func (r *WorkspaceRepository) GetWorkspace(ctx context.Context, id int) (entity.WorkspaceEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return entity.WorkspaceEntity{}, repository.ErrDatabaseUnavailable
+	}
+
+	ws, err := scanWorkspace(pool.QueryRow(ctx, "SELECT "+workspaceColumns+" FROM md.workspaces WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.WorkspaceEntity{}, repository.ErrWorkspaceNotFound
+		}
+		return entity.WorkspaceEntity{}, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	return ws, nil
+}
+
+// ListUserWorkspaces возвращает пространства, где состоит пользователь, в порядке создания.
+func (r *WorkspaceRepository) ListUserWorkspaces(ctx context.Context, userID int) ([]entity.WorkspaceEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	query := `
+		SELECT w.id, w.name, w.created_by, w.created_at
+		FROM md.workspaces w
+		JOIN md.workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1
+		ORDER BY w.id`
+
+	rows, err := pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	workspaces := []entity.WorkspaceEntity{}
+	for rows.Next() {
+		ws, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, ws)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) IsMember(ctx context.Context, workspaceID, userID int) (bool, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return false, repository.ErrDatabaseUnavailable
+	}
+
+	var member bool
+	err := pool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM md.workspace_members WHERE workspace_id = $1 AND user_id = $2)",
+		workspaceID, userID,
+	).Scan(&member)
+	if err != nil {
+		return false, fmt.Errorf("failed to check membership: %w", err)
+	}
+	return member, nil
+}
+
+func (r *WorkspaceRepository) ListMembers(ctx context.Context, workspaceID int) ([]entity.WorkspaceMemberEntity, error) {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return nil, repository.ErrDatabaseUnavailable
+	}
+
+	rows, err := pool.Query(ctx,
+		"SELECT workspace_id, user_id, created_at FROM md.workspace_members WHERE workspace_id = $1 ORDER BY created_at, user_id",
+		workspaceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []entity.WorkspaceMemberEntity{}
+	for rows.Next() {
+		var m entity.WorkspaceMemberEntity
+		if err := rows.Scan(&m.WorkspaceID, &m.UserID, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember добавляет пользователя в пространство; повторное добавление не ошибка.
+func (r *WorkspaceRepository) AddMember(ctx context.Context, workspaceID, userID int) error {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+
+	_, err := pool.Exec(ctx,
+		"INSERT INTO md.workspace_members (workspace_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+		workspaceID, userID,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return repository.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to add member: %w", err)
+	}
+
+	r.logger.Info("Workspace member added", "workspace_id", workspaceID, "user_id", userID)
+	return nil
+}
+
+func (r *WorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID int) error {
+	pool := r.dbPool.GetPool()
+	if pool == nil {
+		return repository.ErrDatabaseUnavailable
+	}
+
+	tag, err := pool.Exec(ctx,
+		"DELETE FROM md.workspace_members WHERE workspace_id = $1 AND user_id = $2",
+		workspaceID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrUserNotFound
+	}
+
+	r.logger.Info("Workspace member removed", "workspace_id", workspaceID, "user_id", userID)
+	return nil
+}
+
+func scanWorkspace(row pgx.Row) (entity.WorkspaceEntity, error) {
+	var ws entity.WorkspaceEntity
+	err := row.Scan(&ws.ID, &ws.Name, &ws.CreatedBy, &ws.CreatedAt)
+	return ws, err
+}
@@ -0,0 +1,47 @@
+// Package reqctx переносит сведения о текущем запросе (кто его выполняет, в каком
+// рабочем пространстве и его ID) через context.Context до сервисов и репозиториев.
+package reqctx
+
+import "context"
+
+// SystemActor — инициатор изменений, сделанных не через API (миграции, фоновые задачи).
+const SystemActor = "system"
+
+type ctxKey int
+
+const (
+	actorKey ctxKey = iota
+	requestIDKey
+	workspaceKey
+)
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithWorkspace задаёт рабочее пространство, которым ограничены все запросы к задачам.
+func WithWorkspace(ctx context.Context, workspaceID int) context.Context {
+	return context.WithValue(ctx, workspaceKey, workspaceID)
+}
+
+// Workspace возвращает рабочее пространство запроса; false — оно не выбрано.
+func Workspace(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(workspaceKey).(int)
+	return id, ok && id > 0
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"myApi/auth"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/storage"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrAttachmentTooLarge   = errors.New("attachment is too large")
+	ErrUnsupportedMediaType = errors.New("attachment type is not allowed")
+)
+
+// maxFileNameLength — предел длины имени файла в байтах, как у большинства файловых систем.
+const maxFileNameLength = 255
+
+type AttachmentRepository interface {
+	ListAttachments(ctx context.Context, taskID int) ([]entity.AttachmentEntity, error)
+	GetAttachment(ctx context.Context, taskID, id int) (entity.AttachmentEntity, error)
+	CreateAttachment(ctx context.Context, attachment model.Attachment) (entity.AttachmentEntity, error)
+	DeleteAttachment(ctx context.Context, taskID, id int) (entity.AttachmentEntity, error)
+}
+
+// AttachmentLimits — ограничения на загружаемые файлы. AllowedTypes — MIME-типы без
+// параметров; "image/*" разрешает любой подтип.
+type AttachmentLimits struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+// AttachmentService — файлы задач. Описание файла хранится в репозитории, содержимое —
+// в blob-хранилище. Тип файла определяется по содержимому, а не по заявке клиента.
+type AttachmentService struct {
+	repo   AttachmentRepository
+	tasks  TaskRepository
+	blobs  storage.Storage
+	limits AttachmentLimits
+	logger *slog.Logger
+}
+
+func NewAttachmentService(repo AttachmentRepository, tasks TaskRepository, blobs storage.Storage, limits AttachmentLimits, logger *slog.Logger) *AttachmentService {
+	return &AttachmentService{
+		repo:   repo,
+		tasks:  tasks,
+		blobs:  blobs,
+		limits: limits,
+		logger: logger,
+	}
+}
+
+// MaxSize — предел размера одного файла в байтах.
+func (s *AttachmentService) MaxSize() int64 {
+	return s.limits.MaxSize
+}
+
+func (s *AttachmentService) List(ctx context.Context, taskID int) ([]model.Attachment, error) {
+	if _, err := s.tasks.GetTaskById(ctx, taskID); err != nil {
+		return nil, err
+	}
+	found, err := s.repo.ListAttachments(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	attachments := make([]model.Attachment, len(found))
+	for i := range found {
+		attachments[i] = *found[i].ToModel()
+	}
+	return attachments, nil
+}
+
+// Upload проверяет размер и тип файла, считает SHA-256 и сохраняет содержимое. content
+// читается дважды: сначала для проверки и хеша, затем для записи в хранилище.
+func (s *AttachmentService) Upload(ctx context.Context, taskID int, name string, content io.ReadSeeker) (*model.Attachment, error) {
+	task, err := s.tasks.GetTaskById(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+	hash := sha256.New()
+	hash.Write(head)
+	rest, err := io.Copy(hash, io.LimitReader(content, s.limits.MaxSize-int64(n)+1))
+	if err != nil {
+		return nil, err
+	}
+	size := int64(n) + rest
+	if size == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrValidation)
+	}
+	if size > s.limits.MaxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrAttachmentTooLarge, s.limits.MaxSize)
+	}
+	contentType := http.DetectContentType(head)
+	if !s.allowed(contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, contentType)
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	attachment := model.Attachment{
+		TaskID:      taskID,
+		FileName:    cleanFileName(name),
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  fmt.Sprintf("%d/%d/%s", task.WorkspaceID, taskID, rand.Text()),
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		attachment.UploadedBy = &principal.UserID
+	}
+	err = s.blobs.Put(ctx, attachment.StorageKey, io.LimitReader(content, size), storage.BlobInfo{
+		Size:        size,
+		ContentType: contentType,
+		SHA256:      attachment.Checksum,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store attachment: %w", err)
+	}
+
+	created, err := s.repo.CreateAttachment(ctx, attachment)
+	if err != nil {
+		s.removeBlob(attachment.StorageKey)
+		return nil, err
+	}
+	return created.ToModel(), nil
+}
+
+// Open возвращает описание вложения и его содержимое; вызывающий закрывает reader.
+func (s *AttachmentService) Open(ctx context.Context, taskID, id int) (*model.Attachment, io.ReadCloser, error) {
+	if _, err := s.tasks.GetTaskById(ctx, taskID); err != nil {
+		return nil, nil, err
+	}
+	found, err := s.repo.GetAttachment(ctx, taskID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.blobs.Open(ctx, found.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open attachment content: %w", err)
+	}
+	return found.ToModel(), content, nil
+}
+
+// Delete удаляет описание вложения, затем содержимое. Если хранилище не ответило,
+// файл остаётся сиротой — это лишь место на диске, поэтому ошибка только логируется.
+func (s *AttachmentService) Delete(ctx context.Context, taskID, id int) error {
+	if _, err := s.tasks.GetTaskById(ctx, taskID); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteAttachment(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+	s.removeBlob(deleted.StorageKey)
+	return nil
+}
+
+func (s *AttachmentService) removeBlob(key string) {
+	if err := s.blobs.Delete(context.Background(), key); err != nil {
+		s.logger.Warn("Failed to remove attachment content", "key", key, "error", err)
+	}
+}
+
+func (s *AttachmentService) allowed(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range s.limits.AllowedTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") || allowed == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanFileName оставляет от присланного имени только последний элемент пути без
+// управляющих символов: имя попадёт в Content-Disposition при скачивании.
+func cleanFileName(name string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	for len(name) > maxFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" || name == "." || name == ".." {
+		return "attachment"
+	}
+	return name
+}
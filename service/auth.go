@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/auth"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const minPasswordLength = 8
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrEmailTaken         = errors.New("email is already registered")
+)
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, user model.User) (entity.UserEntity, error)
+	// CreateUserWithWorkspace создаёт пользователя и его пространство атомарно.
+	CreateUserWithWorkspace(ctx context.Context, user model.User, workspaceName string) (entity.UserEntity, entity.WorkspaceEntity, error)
+	GetUserByEmail(ctx context.Context, email string) (entity.UserEntity, error)
+	GetUserByID(ctx context.Context, id int) (entity.UserEntity, error)
+	ListUsers(ctx context.Context) ([]entity.UserEntity, error)
+	SetUserRole(ctx context.Context, id int, role model.Role) (entity.UserEntity, error)
+}
+
+type TokenRepository interface {
+	SaveRefreshToken(ctx context.Context, jti string, userID int, expiresAt time.Time) error
+	RevokeRefreshToken(ctx context.Context, jti string) (entity.RefreshTokenEntity, error)
+	RevokeUserRefreshTokens(ctx context.Context, userID int) error
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key model.APIKey) (entity.APIKeyEntity, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (entity.APIKeyEntity, error)
+	ListAPIKeys(ctx context.Context, userID int) ([]entity.APIKeyEntity, error)
+	RevokeAPIKey(ctx context.Context, userID, id int) (entity.APIKeyEntity, error)
+	TouchAPIKey(ctx context.Context, id int, at time.Time) error
+}
+
+type AuthService struct {
+	users      UserRepository
+	tokens     TokenRepository
+	keys       APIKeyRepository
+	workspaces WorkspaceRepository
+	issuer     *auth.TokenIssuer
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+func NewAuthService(users UserRepository, tokens TokenRepository, keys APIKeyRepository, workspaces WorkspaceRepository, issuer *auth.TokenIssuer, logger *slog.Logger) *AuthService {
+	return &AuthService{
+		users:      users,
+		tokens:     tokens,
+		keys:       keys,
+		workspaces: workspaces,
+		issuer:     issuer,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+func (s *AuthService) Register(ctx context.Context, email, name, password string) (*model.User, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if n := utf8.RuneCountInString(password); n < minPasswordLength || len(password) > auth.MaxPasswordLength {
+		return nil, fmt.Errorf("%w: password must be %d-%d characters", ErrValidation, minPasswordLength, auth.MaxPasswordLength)
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	user := model.User{
+		Email:        email,
+		Name:         strings.TrimSpace(name),
+		PasswordHash: hash,
+		Role:         model.RoleMember,
+	}
+	// У каждого нового пользователя есть своё пространство, чтобы он сразу мог
+	// работать с задачами, не дожидаясь приглашения.
+	created, _, err := s.users.CreateUserWithWorkspace(ctx, user, personalWorkspaceName(user))
+	if errors.Is(err, repository.ErrUserExists) {
+		return nil, ErrEmailTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return created.ToModel(), nil
+}
+
+// Login выдаёт пару токенов. Если workspaceID не 0, токены действуют только в этом
+// пространстве.
+func (s *AuthService) Login(ctx context.Context, email, password string, workspaceID int) (auth.TokenPair, error) {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return auth.TokenPair{}, ErrInvalidCredentials
+	}
+
+	user, err := s.users.GetUserByEmail(ctx, email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		auth.SimulatePasswordCheck(password)
+		return auth.TokenPair{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return auth.TokenPair{}, err
+	}
+	if !auth.CheckPassword(user.PasswordHash, password) {
+		s.logger.Warn("Failed login attempt", "user_id", user.ID)
+		return auth.TokenPair{}, ErrInvalidCredentials
+	}
+	if err := s.checkBinding(ctx, user, workspaceID); err != nil {
+		return auth.TokenPair{}, err
+	}
+
+	return s.issue(ctx, user.ID, user.Email, workspaceID)
+}
+
+// Refresh обменивает refresh-токен на новую пару. Каждый refresh-токен одноразовый:
+// повторное предъявление означает утечку, и тогда отзываются все сессии пользователя.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (auth.TokenPair, error) {
+	claims, err := s.issuer.Parse(refreshToken, auth.TokenTypeRefresh)
+	if err != nil {
+		return auth.TokenPair{}, err
+	}
+
+	prev, err := s.tokens.RevokeRefreshToken(ctx, claims.ID)
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		return auth.TokenPair{}, fmt.Errorf("%w: unknown refresh token", auth.ErrInvalidToken)
+	}
+	if err != nil {
+		return auth.TokenPair{}, err
+	}
+	if prev.RevokedAt != nil {
+		s.logger.Warn("Refresh token reuse detected", "user_id", prev.UserID)
+		if err := s.tokens.RevokeUserRefreshTokens(ctx, prev.UserID); err != nil {
+			return auth.TokenPair{}, err
+		}
+		return auth.TokenPair{}, fmt.Errorf("%w: refresh token was already used", auth.ErrInvalidToken)
+	}
+
+	user, err := s.users.GetUserByID(ctx, prev.UserID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return auth.TokenPair{}, fmt.Errorf("%w: user no longer exists", auth.ErrInvalidToken)
+	}
+	if err != nil {
+		return auth.TokenPair{}, err
+	}
+	// Пока сессия жила, пользователя могли исключить из пространства.
+	if err := s.checkBinding(ctx, user, claims.WorkspaceID); err != nil {
+		return auth.TokenPair{}, err
+	}
+	return s.issue(ctx, user.ID, user.Email, claims.WorkspaceID)
+}
+
+// Logout отзывает текущий access-токен и, если передан, refresh-токен той же сессии.
+func (s *AuthService) Logout(ctx context.Context, principal auth.Principal, refreshToken string) error {
+	if principal.APIKeyID != 0 {
+		return fmt.Errorf("%w: api keys are revoked via DELETE /auth/keys/{id}", ErrValidation)
+	}
+	if err := s.tokens.RevokeAccessToken(ctx, principal.TokenID, principal.ExpiresAt); err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	claims, err := s.issuer.Parse(refreshToken, auth.TokenTypeRefresh)
+	if err != nil {
+		return err
+	}
+	if claims.Subject != strconv.Itoa(principal.UserID) {
+		return fmt.Errorf("%w: refresh token belongs to another user", auth.ErrInvalidToken)
+	}
+	if _, err := s.tokens.RevokeRefreshToken(ctx, claims.ID); err != nil && !errors.Is(err, repository.ErrTokenNotFound) {
+		return err
+	}
+	return nil
+}
+
+// Authenticate проверяет токен из заголовка Authorization: access-токен пользователя
+// или API-ключ.
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (auth.Principal, error) {
+	if auth.IsAPIKey(accessToken) {
+		return s.authenticateAPIKey(ctx, accessToken)
+	}
+
+	claims, err := s.issuer.Parse(accessToken, auth.TokenTypeAccess)
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	userID, err := claims.UserID()
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	revoked, err := s.tokens.IsAccessTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if revoked {
+		return auth.Principal{}, fmt.Errorf("%w: revoked", auth.ErrInvalidToken)
+	}
+
+	role, err := s.userRole(ctx, userID)
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	return auth.Principal{
+		UserID:    userID,
+		Email:     claims.Email,
+		TokenID:   claims.ID,
+		ExpiresAt: claims.Expiry(),
+		Role:      role,
+		// Пользовательская сессия не ограничена scope'ами, только ролью.
+		Scopes:      model.Scopes{model.ScopeAdmin},
+		WorkspaceID: claims.WorkspaceID,
+	}, nil
+}
+
+// userRole читает роль при каждой аутентификации, чтобы смена роли действовала сразу,
+// а не после истечения уже выданных токенов.
+func (s *AuthService) userRole(ctx context.Context, userID int) (model.Role, error) {
+	user, err := s.users.GetUserByID(ctx, userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return "", fmt.Errorf("%w: user no longer exists", auth.ErrInvalidToken)
+	}
+	if err != nil {
+		return "", err
+	}
+	return model.Role(user.Role), nil
+}
+
+func (s *AuthService) authenticateAPIKey(ctx context.Context, key string) (auth.Principal, error) {
+	prefix, ok := auth.SplitAPIKey(key)
+	if !ok {
+		return auth.Principal{}, fmt.Errorf("%w: malformed api key", auth.ErrInvalidToken)
+	}
+
+	found, err := s.keys.GetAPIKeyByPrefix(ctx, prefix)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return auth.Principal{}, fmt.Errorf("%w: unknown api key", auth.ErrInvalidToken)
+	}
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	apiKey := found.ToModel()
+	now := s.now()
+	if !auth.CheckAPIKey(key, apiKey.Hash) || !apiKey.Active(now) {
+		return auth.Principal{}, fmt.Errorf("%w: api key is invalid, expired or revoked", auth.ErrInvalidToken)
+	}
+
+	// Учёт использования не должен ронять запрос.
+	if err := s.keys.TouchAPIKey(ctx, apiKey.ID, now); err != nil {
+		s.logger.Warn("Failed to record api key usage", "id", apiKey.ID, "error", err)
+	}
+
+	role, err := s.userRole(ctx, apiKey.UserID)
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	principal := auth.Principal{
+		UserID:   apiKey.UserID,
+		Role:     role,
+		APIKeyID: apiKey.ID,
+		Scopes:   apiKey.Scopes,
+	}
+	if apiKey.ExpiresAt != nil {
+		principal.ExpiresAt = *apiKey.ExpiresAt
+	}
+	if apiKey.WorkspaceID != nil {
+		principal.WorkspaceID = *apiKey.WorkspaceID
+	}
+	return principal, nil
+}
+
+// CreateAPIKey выпускает ключ от имени principal. Ключ возвращается открытым текстом
+// только здесь, потом его не восстановить. workspaceID ограничивает ключ одним
+// пространством; клиент, сам привязанный к пространству, выпускает ключи только для него.
+func (s *AuthService) CreateAPIKey(ctx context.Context, principal auth.Principal, name string, scopes model.Scopes, workspaceID *int, expiresAt *time.Time) (*model.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return nil, "", fmt.Errorf("%w: name must be 1-100 characters", ErrValidation)
+	}
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", ErrValidation)
+	}
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, "", fmt.Errorf("%w: %w %q", ErrValidation, model.ErrInvalidScope, scope)
+		}
+	}
+	if !principal.Scopes.Covers(scopes) {
+		return nil, "", fmt.Errorf("%w: cannot grant scopes you do not have", policy.ErrForbidden)
+	}
+	if expiresAt != nil && !expiresAt.After(s.now()) {
+		return nil, "", fmt.Errorf("%w: expires_at must be in the future", ErrValidation)
+	}
+	if principal.WorkspaceID != 0 {
+		if workspaceID != nil && *workspaceID != principal.WorkspaceID {
+			return nil, "", fmt.Errorf("%w: token is bound to workspace %d", policy.ErrForbidden, principal.WorkspaceID)
+		}
+		workspaceID = &principal.WorkspaceID
+	}
+	if workspaceID != nil {
+		if err := checkWorkspaceAccess(ctx, s.workspaces, principal, *workspaceID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	key, prefix, hash := auth.GenerateAPIKey()
+	created, err := s.keys.CreateAPIKey(ctx, model.APIKey{
+		UserID:      principal.UserID,
+		Name:        name,
+		Prefix:      prefix,
+		Hash:        hash,
+		Scopes:      slices.Compact(slices.Sorted(slices.Values(scopes))),
+		WorkspaceID: workspaceID,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return created.ToModel(), key, nil
+}
+
+func (s *AuthService) ListAPIKeys(ctx context.Context, principal auth.Principal) ([]model.APIKey, error) {
+	found, err := s.keys.ListAPIKeys(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]model.APIKey, len(found))
+	for i := range found {
+		keys[i] = *found[i].ToModel()
+	}
+	return keys, nil
+}
+
+func (s *AuthService) RevokeAPIKey(ctx context.Context, principal auth.Principal, id int) (*model.APIKey, error) {
+	revoked, err := s.keys.RevokeAPIKey(ctx, principal.UserID, id)
+	if err != nil {
+		return nil, err
+	}
+	return revoked.ToModel(), nil
+}
+
+func (s *AuthService) GetUser(ctx context.Context, id int) (*model.User, error) {
+	user, err := s.users.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return user.ToModel(), nil
+}
+
+func (s *AuthService) ListUsers(ctx context.Context) ([]model.User, error) {
+	found, err := s.users.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]model.User, len(found))
+	for i := range found {
+		users[i] = *found[i].ToModel()
+	}
+	return users, nil
+}
+
+// SetUserRole меняет роль пользователя. Свою роль менять нельзя, чтобы администратор
+// случайно не лишил систему последнего администратора.
+func (s *AuthService) SetUserRole(ctx context.Context, principal auth.Principal, id int, role model.Role) (*model.User, error) {
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: %w %q", ErrValidation, model.ErrInvalidRole, role)
+	}
+	if id == principal.UserID {
+		return nil, fmt.Errorf("%w: cannot change your own role", policy.ErrForbidden)
+	}
+
+	user, err := s.users.SetUserRole(ctx, id, role)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("User role changed", "user_id", id, "role", role, "by", principal.Actor())
+	return user.ToModel(), nil
+}
+
+// checkBinding проверяет, что пользователь может получить токен для пространства.
+func (s *AuthService) checkBinding(ctx context.Context, user entity.UserEntity, workspaceID int) error {
+	if workspaceID == 0 {
+		return nil
+	}
+	principal := auth.Principal{UserID: user.ID, Email: user.Email, Role: model.Role(user.Role)}
+	return checkWorkspaceAccess(ctx, s.workspaces, principal, workspaceID)
+}
+
+func (s *AuthService) issue(ctx context.Context, userID int, email string, workspaceID int) (auth.TokenPair, error) {
+	pair, err := s.issuer.Issue(userID, email, workspaceID)
+	if err != nil {
+		return auth.TokenPair{}, fmt.Errorf("issue tokens: %w", err)
+	}
+	if err := s.tokens.SaveRefreshToken(ctx, pair.Refresh.ID, userID, pair.RefreshExpiresAt); err != nil {
+		return auth.TokenPair{}, err
+	}
+	return pair, nil
+}
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", fmt.Errorf("%w: invalid email", ErrValidation)
+	}
+	return email, nil
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"myApi/auth"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"myApi/repository/memory"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testAuth struct {
+	auth       *AuthService
+	workspaces *WorkspaceService
+	wsRepo     *memory.WorkspaceRepository
+}
+
+func newTestAuthService(t *testing.T) testAuth {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	issuer, err := auth.NewTokenIssuer([]byte(strings.Repeat("s", 32)), time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	workspaces := memory.NewWorkspaceRepository()
+	users := memory.NewUserRepository(workspaces)
+	return testAuth{
+		auth:       NewAuthService(users, memory.NewTokenRepository(), memory.NewAPIKeyRepository(), workspaces, issuer, logger),
+		workspaces: NewWorkspaceService(workspaces, users, logger),
+		wsRepo:     workspaces,
+	}
+}
+
+// register создаёт пользователя и возвращает его principal и личное пространство.
+// Первый зарегистрированный пользователь становится администратором.
+func (a testAuth) register(t *testing.T, email string) (auth.Principal, int) {
+	t.Helper()
+	ctx := context.Background()
+	user, err := a.auth.Register(ctx, email, "", "password123")
+	if err != nil {
+		t.Fatalf("Register(%q) = %v", email, err)
+	}
+	workspaces, err := a.wsRepo.ListUserWorkspaces(ctx, user.ID)
+	if err != nil || len(workspaces) != 1 {
+		t.Fatalf("workspaces of %q = %v, %v", email, workspaces, err)
+	}
+	principal := auth.Principal{UserID: user.ID, Role: user.Role, Scopes: model.Scopes{model.ScopeAdmin}}
+	return principal, workspaces[0].ID
+}
+
+func TestCreateAPIKeyValidation(t *testing.T) {
+	a := newTestAuthService(t)
+	a.register(t, "admin@example.com")
+	member, _ := a.register(t, "member@example.com")
+	reader := member
+	reader.Scopes = model.Scopes{model.ScopeTasksRead}
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		keyName   string
+		scopes    model.Scopes
+		expiresAt *time.Time
+		wantErr   error
+	}{
+		{name: "ok", principal: member, keyName: "ci", scopes: model.Scopes{model.ScopeTasksRead}},
+		{name: "admin session grants admin", principal: member, keyName: "ci", scopes: model.Scopes{model.ScopeAdmin}},
+		{name: "blank name", principal: member, keyName: "  ", scopes: model.Scopes{model.ScopeTasksRead}, wantErr: ErrValidation},
+		{name: "long name", principal: member, keyName: strings.Repeat("k", 101), scopes: model.Scopes{model.ScopeTasksRead}, wantErr: ErrValidation},
+		{name: "no scopes", principal: member, keyName: "ci", wantErr: ErrValidation},
+		{name: "unknown scope", principal: member, keyName: "ci", scopes: model.Scopes{"tasks:delete"}, wantErr: model.ErrInvalidScope},
+		{name: "expired", principal: member, keyName: "ci", scopes: model.Scopes{model.ScopeTasksRead}, expiresAt: &past, wantErr: ErrValidation},
+		{name: "same scope", principal: reader, keyName: "ci", scopes: model.Scopes{model.ScopeTasksRead}},
+		{name: "wider scope", principal: reader, keyName: "ci", scopes: model.Scopes{model.ScopeTasksWrite}, wantErr: policy.ErrForbidden},
+		{name: "admin scope", principal: reader, keyName: "ci", scopes: model.Scopes{model.ScopeAdmin}, wantErr: policy.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, secret, err := a.auth.CreateAPIKey(context.Background(), tt.principal, tt.keyName, tt.scopes, nil, tt.expiresAt)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CreateAPIKey() = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateAPIKey() = %v", err)
+			}
+			if !strings.HasPrefix(secret, key.Prefix+"_") {
+				t.Errorf("key %q does not start with prefix %q", secret, key.Prefix)
+			}
+		})
+	}
+}
+
+func TestCreateAPIKeyNormalizesScopes(t *testing.T) {
+	a := newTestAuthService(t)
+	principal, _ := a.register(t, "admin@example.com")
+	scopes := model.Scopes{model.ScopeTasksWrite, model.ScopeTasksRead, model.ScopeTasksWrite}
+	key, _, err := a.auth.CreateAPIKey(context.Background(), principal, "ci", scopes, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.Scopes{model.ScopeTasksRead, model.ScopeTasksWrite}
+	if len(key.Scopes) != len(want) || key.Scopes[0] != want[0] || key.Scopes[1] != want[1] {
+		t.Errorf("scopes = %v, want %v", key.Scopes, want)
+	}
+}
+
+func TestCreateAPIKeyWorkspaceBinding(t *testing.T) {
+	a := newTestAuthService(t)
+	admin, adminWS := a.register(t, "admin@example.com")
+	member, memberWS := a.register(t, "member@example.com")
+	bound := member
+	bound.WorkspaceID = memberWS
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		workspace *int
+		want      *int
+		wantErr   error
+	}{
+		{name: "unbound key", principal: member},
+		{name: "own workspace", principal: member, workspace: &memberWS, want: &memberWS},
+		{name: "foreign workspace", principal: member, workspace: &adminWS, wantErr: policy.ErrForbidden},
+		{name: "missing workspace", principal: member, workspace: ptr(999), wantErr: repository.ErrWorkspaceNotFound},
+		{name: "admin in any workspace", principal: admin, workspace: &memberWS, want: &memberWS},
+		{name: "bound principal gets its workspace", principal: bound, want: &memberWS},
+		{name: "bound principal names its workspace", principal: bound, workspace: &memberWS, want: &memberWS},
+		{name: "bound principal names another", principal: bound, workspace: &adminWS, wantErr: policy.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, _, err := a.auth.CreateAPIKey(context.Background(), tt.principal, "ci", model.Scopes{model.ScopeTasksRead}, tt.workspace, nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CreateAPIKey() = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateAPIKey() = %v", err)
+			}
+			switch {
+			case tt.want == nil && key.WorkspaceID != nil:
+				t.Errorf("workspace = %d, want none", *key.WorkspaceID)
+			case tt.want != nil && (key.WorkspaceID == nil || *key.WorkspaceID != *tt.want):
+				t.Errorf("workspace = %v, want %d", key.WorkspaceID, *tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	a := newTestAuthService(t)
+	ctx := context.Background()
+	a.register(t, "admin@example.com")
+	member, memberWS := a.register(t, "member@example.com")
+	expiresAt := time.Now().Add(time.Hour)
+	key, secret, err := a.auth.CreateAPIKey(ctx, member, "ci", model.Scopes{model.ScopeTasksRead}, &memberWS, &expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, err := a.auth.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("Authenticate() = %v", err)
+	}
+	if principal.UserID != member.UserID || principal.APIKeyID != key.ID || principal.WorkspaceID != memberWS {
+		t.Errorf("principal = %+v", principal)
+	}
+	if principal.Role != model.RoleMember || len(principal.Scopes) != 1 || principal.Scopes[0] != model.ScopeTasksRead {
+		t.Errorf("role %q, scopes %v, want member with tasks:read", principal.Role, principal.Scopes)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"malformed", auth.APIKeyPrefix + "nosecret"},
+		{"unknown prefix", auth.APIKeyPrefix + "000000000000_secret"},
+		{"wrong secret", key.Prefix + "_" + strings.Repeat("A", 43)},
+	}
+	for _, tt := range tests {
+		if _, err := a.auth.Authenticate(ctx, tt.key); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("%s: Authenticate() = %v, want ErrInvalidToken", tt.name, err)
+		}
+	}
+
+	// Истёкший ключ не принимается.
+	a.auth.now = func() time.Time { return expiresAt.Add(time.Second) }
+	if _, err := a.auth.Authenticate(ctx, secret); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("Authenticate() after expiry = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	a := newTestAuthService(t)
+	ctx := context.Background()
+	owner, _ := a.register(t, "owner@example.com")
+	other, _ := a.register(t, "other@example.com")
+	key, secret, err := a.auth.CreateAPIKey(ctx, owner, "ci", model.Scopes{model.ScopeTasksWrite}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Чужой ключ отозвать нельзя, и он продолжает работать.
+	if _, err := a.auth.RevokeAPIKey(ctx, other, key.ID); !errors.Is(err, repository.ErrAPIKeyNotFound) {
+		t.Fatalf("RevokeAPIKey() by another user = %v, want ErrAPIKeyNotFound", err)
+	}
+	if _, err := a.auth.Authenticate(ctx, secret); err != nil {
+		t.Fatalf("Authenticate() = %v", err)
+	}
+
+	revoked, err := a.auth.RevokeAPIKey(ctx, owner, key.ID)
+	if err != nil || revoked.RevokedAt == nil {
+		t.Fatalf("RevokeAPIKey() = %+v, %v", revoked, err)
+	}
+	if _, err := a.auth.Authenticate(ctx, secret); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("Authenticate() with a revoked key = %v, want ErrInvalidToken", err)
+	}
+	keys, err := a.auth.ListAPIKeys(ctx, owner)
+	if err != nil || len(keys) != 1 || keys[0].RevokedAt == nil {
+		t.Errorf("ListAPIKeys() = %+v, %v, want the revoked key", keys, err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	a := newTestAuthService(t)
+	ctx := context.Background()
+	user, err := a.auth.Register(ctx, "  Ann@Example.com ", " Ann ", "password123")
+	if err != nil {
+		t.Fatalf("Register() = %v", err)
+	}
+	if user.Email != "ann@example.com" || user.Name != "Ann" || user.PasswordHash == "password123" {
+		t.Errorf("user = %+v", user)
+	}
+	// Первый пользователь становится администратором, следующие — участниками.
+	if user.Role != model.RoleAdmin {
+		t.Errorf("first user role = %q, want admin", user.Role)
+	}
+	workspaces, err := a.wsRepo.ListUserWorkspaces(ctx, user.ID)
+	if err != nil || len(workspaces) != 1 || workspaces[0].Name != "Ann's workspace" {
+		t.Fatalf("workspaces = %+v, %v, want the personal one", workspaces, err)
+	}
+	if member, _ := a.wsRepo.IsMember(ctx, workspaces[0].ID, user.ID); !member {
+		t.Error("user is not a member of the personal workspace")
+	}
+
+	second, err := a.auth.Register(ctx, "bob@example.com", "", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Role != model.RoleMember {
+		t.Errorf("second user role = %q, want member", second.Role)
+	}
+	if ws, _ := a.wsRepo.ListUserWorkspaces(ctx, second.ID); len(ws) != 1 || ws[0].Name != "bob's workspace" {
+		t.Errorf("workspaces = %+v, want bob's workspace", ws)
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		wantErr  error
+	}{
+		{"taken email", "ANN@example.com", "password123", ErrEmailTaken},
+		{"invalid email", "ann", "password123", ErrValidation},
+		{"display name in email", "Ann <ann2@example.com>", "password123", ErrValidation},
+		{"short password", "ann2@example.com", "short", ErrValidation},
+		{"long password", "ann2@example.com", strings.Repeat("p", auth.MaxPasswordLength+1), ErrValidation},
+	}
+	for _, tt := range tests {
+		if _, err := a.auth.Register(ctx, tt.email, "", tt.password); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Register() = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	// Отклонённые регистрации не оставляют пространств: следующий номер не занят.
+	created, err := a.wsRepo.CreateWorkspace(ctx, "Next", user.ID)
+	if err != nil || created.ID != workspaces[0].ID+2 {
+		t.Errorf("next workspace id = %d, %v, want %d", created.ID, err, workspaces[0].ID+2)
+	}
+}
+
+// TestRegisterConcurrent проверяет, что пользователь и пространство создаются вместе
+// и при гонке за один адрес пространство появляется ровно одно.
+func TestRegisterConcurrent(t *testing.T) {
+	a := newTestAuthService(t)
+	ctx := context.Background()
+	const n = 8
+	errs := make(chan error, n)
+	for range n {
+		go func() {
+			_, err := a.auth.Register(ctx, "race@example.com", "", "password123")
+			errs <- err
+		}()
+	}
+	created := 0
+	for range n {
+		switch err := <-errs; {
+		case err == nil:
+			created++
+		case !errors.Is(err, ErrEmailTaken):
+			t.Errorf("Register() = %v", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("%d registrations succeeded, want 1", created)
+	}
+	next, err := a.wsRepo.CreateWorkspace(ctx, "Next", 1)
+	if err != nil || next.ID != model.DefaultWorkspaceID+2 {
+		t.Errorf("next workspace id = %d, %v, want %d", next.ID, err, model.DefaultWorkspaceID+2)
+	}
+}
+
+func TestLoginWorkspaceBinding(t *testing.T) {
+	a := newTestAuthService(t)
+	ctx := context.Background()
+	_, adminWS := a.register(t, "admin@example.com")
+	_, memberWS := a.register(t, "member@example.com")
+
+	tests := []struct {
+		name      string
+		email     string
+		workspace int
+		wantErr   error
+	}{
+		{"unbound", "member@example.com", 0, nil},
+		{"own workspace", "member@example.com", memberWS, nil},
+		{"foreign workspace", "member@example.com", adminWS, policy.ErrForbidden},
+		{"missing workspace", "member@example.com", 999, repository.ErrWorkspaceNotFound},
+		{"admin in any workspace", "admin@example.com", memberWS, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair, err := a.auth.Login(ctx, tt.email, "password123", tt.workspace)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Login() = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			principal, err := a.auth.Authenticate(ctx, pair.AccessToken)
+			if err != nil || principal.WorkspaceID != tt.workspace {
+				t.Errorf("Authenticate() = %+v, %v, want workspace %d", principal, err, tt.workspace)
+			}
+		})
+	}
+}
+
+func TestSetUserRole(t *testing.T) {
+	a := newTestAuthService(t)
+	ctx := context.Background()
+	admin, _ := a.register(t, "admin@example.com")
+	member, _ := a.register(t, "member@example.com")
+	pair, err := a.auth.Login(ctx, "member@example.com", "password123", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.auth.SetUserRole(ctx, admin, admin.UserID, model.RoleMember); !errors.Is(err, policy.ErrForbidden) {
+		t.Errorf("SetUserRole() on self = %v, want ErrForbidden", err)
+	}
+	if _, err := a.auth.SetUserRole(ctx, admin, member.UserID, "owner"); !errors.Is(err, ErrValidation) {
+		t.Errorf("SetUserRole() with an unknown role = %v, want ErrValidation", err)
+	}
+	if _, err := a.auth.SetUserRole(ctx, admin, 999, model.RoleAdmin); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("SetUserRole() for a missing user = %v, want ErrUserNotFound", err)
+	}
+
+	updated, err := a.auth.SetUserRole(ctx, admin, member.UserID, model.RoleAdmin)
+	if err != nil || updated.Role != model.RoleAdmin {
+		t.Fatalf("SetUserRole() = %+v, %v", updated, err)
+	}
+	// Новая роль действует и для уже выданного токена.
+	principal, err := a.auth.Authenticate(ctx, pair.AccessToken)
+	if err != nil || principal.Role != model.RoleAdmin {
+		t.Errorf("Authenticate() = %+v, %v, want admin", principal, err)
+	}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"time"
+)
+
+// Bulk применяет одно изменение ко многим задачам в одной транзакции. Каждая задача
+// проверяется так же, как в одиночных операциях (переходы Workflow, приоритет, теги).
+// В атомарном режиме неудача у любой задачи отменяет операцию целиком, в best_effort
+// остальные задачи изменяются. Задачи, которым менять нечего, не записываются.
+func (s *TaskService) Bulk(ctx context.Context, req model.BulkRequest) (model.BulkReport, error) {
+	if err := req.Normalize(); err != nil {
+		return model.BulkReport{}, err
+	}
+	change := req.Change
+	if change.Priority != nil && (*change.Priority < MinPriority || *change.Priority > MaxPriority) {
+		return model.BulkReport{}, fmt.Errorf("%w: priority must be between %d and %d", ErrValidation, MinPriority, MaxPriority)
+	}
+	var err error
+	if change.AddTags, err = model.NormalizeTagNames(change.AddTags); err != nil {
+		return model.BulkReport{}, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	if change.RemoveTags, err = model.NormalizeTagNames(change.RemoveTags); err != nil {
+		return model.BulkReport{}, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	for _, name := range change.AddTags {
+		if slices.Contains(change.RemoveTags, name) {
+			return model.BulkReport{}, fmt.Errorf("%w: tag %q is both added and removed", ErrValidation, name)
+		}
+	}
+
+	results, err := s.bulkTargets(ctx, req)
+	if err != nil {
+		return model.BulkReport{}, err
+	}
+	report := model.BulkReport{Mode: req.Mode, Results: results}
+	atomic := req.Mode == model.BulkAtomic
+
+	var (
+		items []model.BulkItem
+		index []int
+	)
+	now := s.now()
+	for i := range results {
+		r := &results[i]
+		if r.Err != nil {
+			continue
+		}
+		item, changed, err := s.planBulk(r.Task, change, now)
+		if err != nil {
+			r.Task, r.Err = nil, err
+			continue
+		}
+		if changed {
+			items = append(items, item)
+			index = append(index, i)
+		}
+	}
+	if atomic && report.Failed() > 0 {
+		abortBulk(results)
+		return report, nil
+	}
+	if len(items) == 0 {
+		return report, nil
+	}
+
+	applied, err := s.repo.ApplyBulk(ctx, items, atomic)
+	if err != nil {
+		return model.BulkReport{}, err
+	}
+	for j, res := range applied {
+		r := &results[index[j]]
+		switch {
+		case res.Err != nil:
+			r.Task, r.Err = nil, res.Err
+		case items[j].Delete:
+			r.Task, r.Changed = nil, true
+		default:
+			r.Task, r.Changed = res.Task.ToModel(), true
+		}
+	}
+	if atomic && report.Failed() > 0 {
+		abortBulk(results)
+		return report, nil
+	}
+
+	for i := range results {
+		if r := &results[i]; r.Changed && r.Task != nil && change.Status != nil && *change.Status == model.StatusCompleted {
+			s.recur(ctx, r.Task)
+		}
+	}
+	return report, nil
+}
+
+// bulkTargets загружает задачи операции. Для списка ID отсутствующая задача — неудача
+// этой задачи; фильтр должен выбирать не больше model.MaxBulkTasks задач.
+func (s *TaskService) bulkTargets(ctx context.Context, req model.BulkRequest) ([]model.BulkResult, error) {
+	if req.Filter != nil {
+		filter := *req.Filter
+		filter.SortBy, filter.SortDesc = model.SortByID, false
+		filter.Limit, filter.Offset, filter.Cursor = model.MaxBulkTasks, 0, nil
+		if err := filter.Normalize(); err != nil {
+			return nil, err
+		}
+		page, err := s.repo.GetAllTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if page.Total > model.MaxBulkTasks {
+			return nil, fmt.Errorf("%w: filter matches %d tasks, at most %d per request", model.ErrInvalidBulk, page.Total, model.MaxBulkTasks)
+		}
+		results := make([]model.BulkResult, len(page.Tasks))
+		for i := range page.Tasks {
+			results[i] = model.BulkResult{ID: page.Tasks[i].ID, Task: page.Tasks[i].ToModel()}
+		}
+		return results, nil
+	}
+
+	results := make([]model.BulkResult, len(req.IDs))
+	for i, id := range req.IDs {
+		results[i].ID = id
+		task, err := s.repo.GetTaskById(ctx, id)
+		if errors.Is(err, repository.ErrTaskNotFound) {
+			results[i].Err = err
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		results[i].Task = task.ToModel()
+	}
+	return results, nil
+}
+
+// planBulk рассчитывает новое состояние задачи. Статус, который у задачи уже есть, и уже
+// стоящие или отсутствующие теги изменением не считаются.
+func (s *TaskService) planBulk(task *model.Task, change model.BulkChange, now time.Time) (model.BulkItem, bool, error) {
+	item := model.BulkItem{Task: *task}
+	if change.Delete {
+		item.Delete, item.Action = true, model.AuditDelete
+		return item, true, nil
+	}
+
+	var actions []model.AuditAction
+	if change.Status != nil && *change.Status != task.Status {
+		if err := s.workflow.Transition(&item.Task, *change.Status, now); err != nil {
+			return model.BulkItem{}, false, err
+		}
+		actions = append(actions, model.AuditStatus)
+	}
+	if change.Priority != nil && *change.Priority != task.Priority {
+		item.Task.Priority = *change.Priority
+		actions = append(actions, model.AuditPatch)
+	}
+	item.Task.Tags, item.AddTags, item.RemoveTags = model.MergeTags(task.Tags, change.AddTags, change.RemoveTags)
+	if len(item.AddTags) > 0 || len(item.RemoveTags) > 0 {
+		actions = append(actions, model.AuditTag)
+	}
+
+	switch len(actions) {
+	case 0:
+		return item, false, nil
+	case 1:
+		item.Action = actions[0]
+	default:
+		item.Action = model.AuditPatch
+	}
+	return item, true, nil
+}
+
+// abortBulk помечает задачи без своей ошибки как не применённые из-за чужой.
+func abortBulk(results []model.BulkResult) {
+	for i := range results {
+		if results[i].Err == nil {
+			results[i].Task, results[i].Changed, results[i].Err = nil, false, model.ErrBulkAborted
+		}
+	}
+}
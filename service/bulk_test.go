@@ -0,0 +1,163 @@
+package service
+
+import (
+	"errors"
+	"myApi/model"
+	"myApi/repository"
+	"slices"
+	"testing"
+)
+
+func TestBulkModes(t *testing.T) {
+	completed := model.StatusCompleted
+	tests := []struct {
+		name    string
+		mode    model.BulkMode
+		failed  int
+		changed []bool
+		errs    []error
+		status  []model.TaskStatus
+	}{
+		{
+			name:    "atomic rolls back everything",
+			mode:    model.BulkAtomic,
+			failed:  3,
+			changed: []bool{false, false, false},
+			errs:    []error{model.ErrBulkAborted, model.ErrInvalidTransition, repository.ErrTaskNotFound},
+			status:  []model.TaskStatus{model.StatusInProgress, model.StatusPending},
+		},
+		{
+			name:    "best effort applies what it can",
+			mode:    model.BulkBestEffort,
+			failed:  2,
+			changed: []bool{true, false, false},
+			errs:    []error{nil, model.ErrInvalidTransition, repository.ErrTaskNotFound},
+			status:  []model.TaskStatus{model.StatusCompleted, model.StatusPending},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ctx := newTestTaskService(t)
+			started := createTestTask(t, s, ctx, "Started")
+			if _, err := s.Start(ctx, started.ID, 0); err != nil {
+				t.Fatal(err)
+			}
+			pending := createTestTask(t, s, ctx, "Pending")
+
+			// Завершить можно только начатую задачу; задачи 999 нет.
+			report, err := s.Bulk(ctx, model.BulkRequest{
+				IDs:    []int{started.ID, pending.ID, 999},
+				Change: model.BulkChange{Status: &completed},
+				Mode:   tt.mode,
+			})
+			if err != nil {
+				t.Fatalf("Bulk() = %v", err)
+			}
+			if report.Mode != tt.mode || len(report.Results) != 3 {
+				t.Fatalf("report = %+v", report)
+			}
+			if report.Failed() != tt.failed {
+				t.Errorf("Failed() = %d, want %d", report.Failed(), tt.failed)
+			}
+			for i, r := range report.Results {
+				if !errors.Is(r.Err, tt.errs[i]) {
+					t.Errorf("result %d err = %v, want %v", r.ID, r.Err, tt.errs[i])
+				}
+				if r.Changed != tt.changed[i] || (r.Task != nil) != tt.changed[i] {
+					t.Errorf("result %d changed %v task %v, want changed %v", r.ID, r.Changed, r.Task, tt.changed[i])
+				}
+			}
+
+			for i, id := range []int{started.ID, pending.ID} {
+				task, err := s.Get(ctx, id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if task.Status != tt.status[i] {
+					t.Errorf("task %d status = %s, want %s", id, task.Status, tt.status[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBulkSkipsUnchangedTasks(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	same := createTestTask(t, s, ctx, "Same priority")
+	other := createTestTask(t, s, ctx, "Other priority")
+	if _, err := s.Patch(ctx, other.ID, model.TaskPatch{Set: model.TaskFields{Priority: ptr(1)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := s.Bulk(ctx, model.BulkRequest{
+		IDs:    []int{same.ID, other.ID, same.ID},
+		Change: model.BulkChange{Priority: ptr(DefaultPriority)},
+	})
+	if err != nil {
+		t.Fatalf("Bulk() = %v", err)
+	}
+	if len(report.Results) != 2 || report.Failed() != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	if r := report.Results[0]; r.Changed || r.Task == nil || r.Task.Version != same.Version {
+		t.Errorf("unchanged task result = %+v", r)
+	}
+	if r := report.Results[1]; !r.Changed || r.Task.Priority != DefaultPriority {
+		t.Errorf("changed task result = %+v", r)
+	}
+}
+
+func TestBulkTags(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	first := createTestTask(t, s, ctx, "First")
+	second := createTestTask(t, s, ctx, "Second")
+	if _, err := s.AddTags(ctx, first.ID, []string{"old", "keep"}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := s.Bulk(ctx, model.BulkRequest{
+		IDs:    []int{first.ID, second.ID},
+		Change: model.BulkChange{AddTags: []string{" New "}, RemoveTags: []string{"old"}},
+	})
+	if err != nil {
+		t.Fatalf("Bulk() = %v", err)
+	}
+	if got := report.Results[0].Task.Tags; !slices.Equal(got, []string{"keep", "new"}) {
+		t.Errorf("first tags = %v", got)
+	}
+	if got := report.Results[1].Task.Tags; !slices.Equal(got, []string{"new"}) {
+		t.Errorf("second tags = %v", got)
+	}
+
+	_, err = s.Bulk(ctx, model.BulkRequest{
+		IDs:    []int{first.ID},
+		Change: model.BulkChange{AddTags: []string{"x"}, RemoveTags: []string{"X"}},
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Bulk() adding and removing one tag = %v, want ErrValidation", err)
+	}
+}
+
+func TestBulkRejectsInvalidRequests(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	tooMany := make([]int, model.MaxBulkTasks+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+	tests := []struct {
+		name string
+		req  model.BulkRequest
+		err  error
+	}{
+		{"too many ids", model.BulkRequest{IDs: tooMany, Change: model.BulkChange{Delete: true}}, model.ErrInvalidBulk},
+		{"priority out of range", model.BulkRequest{IDs: []int{1}, Change: model.BulkChange{Priority: ptr(MaxPriority + 1)}}, ErrValidation},
+		{"invalid tag", model.BulkRequest{IDs: []int{1}, Change: model.BulkChange{AddTags: []string{""}}}, ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Bulk(ctx, tt.req); !errors.Is(err, tt.err) {
+				t.Errorf("Bulk() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
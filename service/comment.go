@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/auth"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxCommentLength — предел длины комментария в символах.
+const maxCommentLength = 10_000
+
+type CommentRepository interface {
+	ListComments(ctx context.Context, taskID int) ([]entity.CommentEntity, error)
+	GetComment(ctx context.Context, taskID, id int) (entity.CommentEntity, error)
+	CreateComment(ctx context.Context, comment model.Comment) (entity.CommentEntity, error)
+	EditComment(ctx context.Context, taskID, id int, body string, editedBy *int) (entity.CommentEntity, error)
+	DeleteComment(ctx context.Context, taskID, id int) error
+	GetCommentEdits(ctx context.Context, taskID, id int) ([]entity.CommentEditEntity, error)
+}
+
+// CommentService — обсуждение задач. Комментировать можно только задачи вне корзины;
+// править комментарий может только автор, удалять — автор или модератор.
+type CommentService struct {
+	repo   CommentRepository
+	tasks  TaskRepository
+	logger *slog.Logger
+}
+
+func NewCommentService(repo CommentRepository, tasks TaskRepository, logger *slog.Logger) *CommentService {
+	return &CommentService{
+		repo:   repo,
+		tasks:  tasks,
+		logger: logger,
+	}
+}
+
+// Threads возвращает комментарии задачи, разложенные по веткам.
+func (s *CommentService) Threads(ctx context.Context, taskID int) ([]*model.CommentNode, int, error) {
+	if _, err := s.tasks.GetTaskById(ctx, taskID); err != nil {
+		return nil, 0, err
+	}
+	found, err := s.repo.ListComments(ctx, taskID)
+	if err != nil {
+		return nil, 0, err
+	}
+	comments := make([]model.Comment, len(found))
+	for i := range found {
+		comments[i] = *found[i].ToModel()
+	}
+	return model.BuildCommentThreads(comments), len(comments), nil
+}
+
+// Create добавляет комментарий от имени пользователя из контекста. Ответить можно только
+// на неудалённый комментарий той же задачи.
+func (s *CommentService) Create(ctx context.Context, comment model.Comment) (*model.Comment, error) {
+	comment.AuthorID = nil
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		comment.AuthorID = &principal.UserID
+	}
+	body, err := validateComment(comment.Body)
+	if err != nil {
+		return nil, err
+	}
+	comment.Body = body
+
+	if _, err := s.tasks.GetTaskById(ctx, comment.TaskID); err != nil {
+		return nil, err
+	}
+	if comment.ReplyTo != nil {
+		parent, err := s.repo.GetComment(ctx, comment.TaskID, *comment.ReplyTo)
+		if errors.Is(err, repository.ErrCommentNotFound) || err == nil && parent.DeletedAt != nil {
+			return nil, fmt.Errorf("%w: comment %d does not exist in this task", ErrValidation, *comment.ReplyTo)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.repo.CreateComment(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+	return created.ToModel(), nil
+}
+
+// Edit заменяет текст комментария; прежний текст остаётся в истории правок.
+func (s *CommentService) Edit(ctx context.Context, taskID, id int, body string) (*model.Comment, error) {
+	body, err := validateComment(body)
+	if err != nil {
+		return nil, err
+	}
+	principal, _ := auth.PrincipalFromContext(ctx)
+	if err := s.checkAuthor(ctx, taskID, id, principal, false); err != nil {
+		return nil, err
+	}
+
+	var editor *int
+	if principal.UserID != 0 {
+		editor = &principal.UserID
+	}
+	updated, err := s.repo.EditComment(ctx, taskID, id, body, editor)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToModel(), nil
+}
+
+// Delete удаляет комментарий; moderate разрешает удалять чужие.
+func (s *CommentService) Delete(ctx context.Context, taskID, id int, moderate bool) error {
+	principal, _ := auth.PrincipalFromContext(ctx)
+	if err := s.checkAuthor(ctx, taskID, id, principal, moderate); err != nil {
+		return err
+	}
+	return s.repo.DeleteComment(ctx, taskID, id)
+}
+
+// Edits возвращает прежние версии текста комментария, старые первыми.
+func (s *CommentService) Edits(ctx context.Context, taskID, id int) ([]model.CommentEdit, error) {
+	if _, err := s.tasks.GetTaskById(ctx, taskID); err != nil {
+		return nil, err
+	}
+	found, err := s.repo.GetCommentEdits(ctx, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+	edits := make([]model.CommentEdit, len(found))
+	for i := range found {
+		edits[i] = found[i].ToModel()
+	}
+	return edits, nil
+}
+
+// checkAuthor проверяет, что задача доступна, комментарий не удалён и principal — его автор.
+func (s *CommentService) checkAuthor(ctx context.Context, taskID, id int, principal auth.Principal, moderate bool) error {
+	if _, err := s.tasks.GetTaskById(ctx, taskID); err != nil {
+		return err
+	}
+	comment, err := s.repo.GetComment(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+	if comment.DeletedAt != nil {
+		return repository.ErrCommentNotFound
+	}
+	if moderate || comment.AuthorID != nil && *comment.AuthorID == principal.UserID {
+		return nil
+	}
+	return fmt.Errorf("%w: only the author can change comment %d", policy.ErrForbidden, id)
+}
+
+func validateComment(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if !utf8.ValidString(body) {
+		return "", fmt.Errorf("%w: body must be valid UTF-8", ErrValidation)
+	}
+	if n := utf8.RuneCountInString(body); n == 0 || n > maxCommentLength {
+		return "", fmt.Errorf("%w: body must be 1-%d characters", ErrValidation, maxCommentLength)
+	}
+	return body, nil
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/auth"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"myApi/repository/memory"
+	"myApi/reqctx"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newTestCommentService(t *testing.T) (*CommentService, *TaskService, context.Context) {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	workspaces := memory.NewWorkspaceRepository()
+	tasks := memory.NewTaskRepository(logger)
+	s := NewCommentService(memory.NewCommentRepository(), tasks, logger)
+	return s, NewTaskService(tasks, memory.NewUserRepository(workspaces), workspaces, logger),
+		reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID)
+}
+
+// asUser возвращает контекст запроса от пользователя userID.
+func asUser(ctx context.Context, userID int) context.Context {
+	return auth.WithPrincipal(ctx, auth.Principal{UserID: userID})
+}
+
+func createTestComment(t *testing.T, s *CommentService, ctx context.Context, taskID int, replyTo *int, body string) *model.Comment {
+	t.Helper()
+	comment, err := s.Create(ctx, model.Comment{TaskID: taskID, ReplyTo: replyTo, Body: body})
+	if err != nil {
+		t.Fatalf("Create(%q) = %v", body, err)
+	}
+	return comment
+}
+
+// threadShape записывает ветки как "1(2(4))3": ID комментария и ответы на него в скобках.
+func threadShape(nodes []*model.CommentNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(strconv.Itoa(n.ID))
+		if len(n.Replies) > 0 {
+			b.WriteString("(" + threadShape(n.Replies) + ")")
+		}
+	}
+	return b.String()
+}
+
+func TestCommentThreads(t *testing.T) {
+	s, tasks, ctx := newTestCommentService(t)
+	task := createTestTask(t, tasks, ctx, "Discussed")
+	other := createTestTask(t, tasks, ctx, "Quiet")
+	alice, bob := asUser(ctx, 1), asUser(ctx, 2)
+
+	first := createTestComment(t, s, alice, task.ID, nil, "  Why?  ")
+	reply := createTestComment(t, s, bob, task.ID, &first.ID, "Because")
+	second := createTestComment(t, s, bob, task.ID, nil, "Another topic")
+	nested := createTestComment(t, s, alice, task.ID, &reply.ID, "I see")
+	createTestComment(t, s, alice, other.ID, nil, "Elsewhere")
+
+	if first.Body != "Why?" || first.AuthorID == nil || *first.AuthorID != 1 {
+		t.Errorf("first = %+v, want trimmed body by user 1", first)
+	}
+	// Автора задаёт principal, а не клиент.
+	forged, err := s.Create(bob, model.Comment{TaskID: task.ID, Body: "Forged", AuthorID: ptr(1)})
+	if err != nil || forged.AuthorID == nil || *forged.AuthorID != 2 {
+		t.Errorf("Create() with author_id = %+v, %v, want author 2", forged, err)
+	}
+	if err := s.Delete(bob, task.ID, forged.ID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	threads, total, err := s.Threads(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("Threads() = %v", err)
+	}
+	want := fmt.Sprintf("%d(%d(%d))%d%d", first.ID, reply.ID, nested.ID, second.ID, forged.ID)
+	if got := threadShape(threads); got != want || total != 5 {
+		t.Errorf("Threads() = %s (total %d), want %s (total 5)", got, total, want)
+	}
+
+	// Удалённый комментарий остаётся в ветке без текста, чтобы ответы не потеряли место.
+	if err := s.Delete(bob, task.ID, reply.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	threads, _, err = s.Threads(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := threadShape(threads); got != want {
+		t.Errorf("Threads() after delete = %s, want %s", got, want)
+	}
+	if deleted := threads[0].Replies[0]; deleted.Body != "" || deleted.DeletedAt == nil {
+		t.Errorf("deleted reply = %+v, want no body", deleted.Comment)
+	}
+
+	if _, _, err := s.Threads(ctx, 999); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("Threads() of a missing task = %v, want ErrTaskNotFound", err)
+	}
+	otherWS := reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID+1)
+	if _, _, err := s.Threads(otherWS, task.ID); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("Threads() from another workspace = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestCommentCreateValidation(t *testing.T) {
+	s, tasks, ctx := newTestCommentService(t)
+	ctx = asUser(ctx, 1)
+	task := createTestTask(t, tasks, ctx, "Discussed")
+	other := createTestTask(t, tasks, ctx, "Other")
+	trashed := createTestTask(t, tasks, ctx, "Trashed")
+	parent := createTestComment(t, s, ctx, task.ID, nil, "Parent")
+	foreign := createTestComment(t, s, ctx, other.ID, nil, "Foreign")
+	deleted := createTestComment(t, s, ctx, task.ID, nil, "Deleted")
+	if err := s.Delete(ctx, task.ID, deleted.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := tasks.Delete(ctx, trashed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		comment model.Comment
+		wantErr error
+	}{
+		{name: "top-level", comment: model.Comment{TaskID: task.ID, Body: "Hello"}},
+		{name: "reply", comment: model.Comment{TaskID: task.ID, ReplyTo: &parent.ID, Body: "Hi"}},
+		{name: "max body", comment: model.Comment{TaskID: task.ID, Body: strings.Repeat("ж", maxCommentLength)}},
+		{name: "blank body", comment: model.Comment{TaskID: task.ID, Body: " \n "}, wantErr: ErrValidation},
+		{name: "long body", comment: model.Comment{TaskID: task.ID, Body: strings.Repeat("ж", maxCommentLength+1)}, wantErr: ErrValidation},
+		{name: "invalid utf-8", comment: model.Comment{TaskID: task.ID, Body: "\xff"}, wantErr: ErrValidation},
+		{name: "reply to a missing comment", comment: model.Comment{TaskID: task.ID, ReplyTo: ptr(999), Body: "Hi"}, wantErr: ErrValidation},
+		{name: "reply to another task", comment: model.Comment{TaskID: task.ID, ReplyTo: &foreign.ID, Body: "Hi"}, wantErr: ErrValidation},
+		{name: "reply to a deleted comment", comment: model.Comment{TaskID: task.ID, ReplyTo: &deleted.ID, Body: "Hi"}, wantErr: ErrValidation},
+		{name: "missing task", comment: model.Comment{TaskID: 999, Body: "Hi"}, wantErr: repository.ErrTaskNotFound},
+		{name: "trashed task", comment: model.Comment{TaskID: trashed.ID, Body: "Hi"}, wantErr: repository.ErrTaskNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := s.Create(ctx, tt.comment)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && created.TaskID != tt.comment.TaskID {
+				t.Errorf("created task_id = %d, want %d", created.TaskID, tt.comment.TaskID)
+			}
+		})
+	}
+}
+
+func TestCommentEditAndDelete(t *testing.T) {
+	s, tasks, ctx := newTestCommentService(t)
+	task := createTestTask(t, tasks, ctx, "Discussed")
+	author, stranger := asUser(ctx, 1), asUser(ctx, 2)
+	comment := createTestComment(t, s, author, task.ID, nil, "Draft")
+
+	// Править может только автор: у Edit нет права модерации.
+	for name, c := range map[string]context.Context{"stranger": stranger, "anonymous": ctx} {
+		if _, err := s.Edit(c, task.ID, comment.ID, "Hijacked"); !errors.Is(err, policy.ErrForbidden) {
+			t.Errorf("Edit() by %s = %v, want ErrForbidden", name, err)
+		}
+	}
+	if _, err := s.Edit(author, task.ID, comment.ID, "  "); !errors.Is(err, ErrValidation) {
+		t.Errorf("Edit() with a blank body = %v, want ErrValidation", err)
+	}
+	if _, err := s.Edit(author, task.ID, 999, "Final"); !errors.Is(err, repository.ErrCommentNotFound) {
+		t.Errorf("Edit() of a missing comment = %v, want ErrCommentNotFound", err)
+	}
+
+	for _, body := range []string{"Second", " Final "} {
+		if _, err := s.Edit(author, task.ID, comment.ID, body); err != nil {
+			t.Fatalf("Edit(%q) = %v", body, err)
+		}
+	}
+	edited, err := s.repo.GetComment(ctx, task.ID, comment.ID)
+	if err != nil || edited.Body != "Final" || edited.EditedAt == nil {
+		t.Errorf("edited = %+v, %v, want body Final with edited_at", edited, err)
+	}
+	edits, err := s.Edits(ctx, task.ID, comment.ID)
+	if err != nil || len(edits) != 2 || edits[0].Body != "Draft" || edits[1].Body != "Second" {
+		t.Fatalf("Edits() = %+v, %v, want Draft then Second", edits, err)
+	}
+	if edits[0].EditedBy == nil || *edits[0].EditedBy != 1 {
+		t.Errorf("edited_by = %v, want 1", edits[0].EditedBy)
+	}
+
+	// Удалять чужое может только модератор.
+	if err := s.Delete(stranger, task.ID, comment.ID, false); !errors.Is(err, policy.ErrForbidden) {
+		t.Errorf("Delete() by a stranger = %v, want ErrForbidden", err)
+	}
+	if err := s.Delete(stranger, task.ID, comment.ID, true); err != nil {
+		t.Fatalf("Delete() by a moderator = %v", err)
+	}
+	// Вместе с комментарием удаляется и история правок.
+	if edits, err := s.Edits(ctx, task.ID, comment.ID); err != nil || len(edits) != 0 {
+		t.Errorf("Edits() after delete = %+v, %v, want none", edits, err)
+	}
+	if _, err := s.Edit(author, task.ID, comment.ID, "Back"); !errors.Is(err, repository.ErrCommentNotFound) {
+		t.Errorf("Edit() of a deleted comment = %v, want ErrCommentNotFound", err)
+	}
+	if err := s.Delete(author, task.ID, comment.ID, false); !errors.Is(err, repository.ErrCommentNotFound) {
+		t.Errorf("Delete() twice = %v, want ErrCommentNotFound", err)
+	}
+
+	own := createTestComment(t, s, author, task.ID, nil, "Mine")
+	if err := s.Delete(author, task.ID, own.ID, false); err != nil {
+		t.Errorf("Delete() by the author = %v", err)
+	}
+	// Комментарий доступен только через свою задачу.
+	kept := createTestComment(t, s, author, task.ID, nil, "Kept")
+	other := createTestTask(t, tasks, ctx, "Other")
+	if err := s.Delete(author, other.ID, kept.ID, true); !errors.Is(err, repository.ErrCommentNotFound) {
+		t.Errorf("Delete() through another task = %v, want ErrCommentNotFound", err)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"myApi/model"
+	"testing"
+)
+
+func TestAddBlockerRejectsCycles(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	a := createTestTask(t, s, ctx, "Task A")
+	b := createTestTask(t, s, ctx, "Task B")
+	c := createTestTask(t, s, ctx, "Task C")
+
+	// a ждёт b, b ждёт c.
+	if _, err := s.AddBlocker(ctx, a.ID, b.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddBlocker(ctx, b.ID, c.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		id, blocker int
+	}{
+		{"self", a.ID, a.ID},
+		{"direct", b.ID, a.ID},
+		{"transitive", c.ID, a.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.AddBlocker(ctx, tt.id, tt.blocker, 0); !errors.Is(err, model.ErrDependencyCycle) {
+				t.Errorf("AddBlocker(%d, %d) = %v, want ErrDependencyCycle", tt.id, tt.blocker, err)
+			}
+		})
+	}
+
+	plan, err := s.Plan(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := planIDs(plan); len(got) != 3 || got[0][0] != c.ID || got[1][0] != b.ID || got[2][0] != a.ID {
+		t.Errorf("Plan() = %v, want [[%d] [%d] [%d]]", got, c.ID, b.ID, a.ID)
+	}
+}
+
+func TestOpenBlockerPreventsStartAndComplete(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	task := createTestTask(t, s, ctx, "Blocked")
+	blocker := createTestTask(t, s, ctx, "Blocker")
+	if _, err := s.AddBlocker(ctx, task.ID, blocker.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Start(ctx, task.ID, 0); !errors.Is(err, model.ErrTaskBlocked) {
+		t.Fatalf("Start() = %v, want ErrTaskBlocked", err)
+	}
+
+	if _, err := s.Start(ctx, blocker.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Блокер в работе — всё ещё открыт.
+	if _, err := s.Start(ctx, task.ID, 0); !errors.Is(err, model.ErrTaskBlocked) {
+		t.Fatalf("Start() with blocker in progress = %v, want ErrTaskBlocked", err)
+	}
+	if _, err := s.Complete(ctx, blocker.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Start(ctx, task.ID, 0); err != nil {
+		t.Fatalf("Start() after the blocker completed = %v", err)
+	}
+
+	// Переоткрытый блокер снова не даёт завершить задачу.
+	if _, err := s.Reopen(ctx, blocker.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Complete(ctx, task.ID, 0); !errors.Is(err, model.ErrTaskBlocked) {
+		t.Fatalf("Complete() with reopened blocker = %v, want ErrTaskBlocked", err)
+	}
+	// Блокер в корзине ничего не блокирует.
+	if err := s.Delete(ctx, blocker.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Complete(ctx, task.ID, 0); err != nil {
+		t.Fatalf("Complete() after the blocker was deleted = %v", err)
+	}
+}
+
+func planIDs(steps []model.PlanStep) [][]int {
+	ids := make([][]int, len(steps))
+	for i, step := range steps {
+		for _, task := range step.Tasks {
+			ids[i] = append(ids[i], task.ID)
+		}
+	}
+	return ids
+}
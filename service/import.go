@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"myApi/auth"
+	"myApi/model"
+	"slices"
+	"time"
+)
+
+// Import загружает задачи из src. Каждая строка проверяется как при создании задачи;
+// неверные строки пропускаются и перечисляются в отчёте, остальные передаются хранилищу
+// пачками по model.ImportBatchSize и сохраняются в одной транзакции. Ошибка чтения файла
+// или хранилища отменяет импорт целиком. С dryRun строки только проверяются.
+func (s *TaskService) Import(ctx context.Context, src model.ImportSource, dryRun bool) (model.ImportReport, error) {
+	report := model.ImportReport{DryRun: dryRun}
+	var createdBy *int
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		createdBy = &principal.UserID
+	}
+	now := s.now()
+
+	next := func() ([]model.Task, error) {
+		var batch []model.Task
+		for len(batch) < model.ImportBatchSize {
+			record, err := src.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			report.Total++
+			task := record.Task
+			if record.Err == nil {
+				record.Err = validateImported(&task, now)
+			}
+			if record.Err != nil {
+				report.Reject(record.Line, record.Err)
+				continue
+			}
+			task.CreatedBy = createdBy
+			batch = append(batch, task)
+		}
+		return batch, nil
+	}
+
+	if dryRun {
+		for {
+			batch, err := next()
+			if err != nil {
+				return model.ImportReport{}, err
+			}
+			if len(batch) == 0 {
+				return report, nil
+			}
+		}
+	}
+
+	imported, err := s.repo.ImportTasks(ctx, next)
+	if err != nil {
+		return model.ImportReport{}, err
+	}
+	report.Imported = imported
+	s.logger.Info("Tasks imported", "imported", imported, "rejected", report.Rejected)
+	return report, nil
+}
+
+// validateImported проверяет задачу из файла импорта. В отличие от Create статус берётся
+// из файла: started_at и completed_at, которых нет в файле, считаются равными now.
+func validateImported(task *model.Task, now time.Time) error {
+	if err := validateTask(task); err != nil {
+		return err
+	}
+	if task.Status == "" {
+		task.Status = model.StatusPending
+	}
+	if !task.Status.Valid() {
+		return fmt.Errorf("%w: unknown status %q", ErrValidation, task.Status)
+	}
+
+	switch task.Status {
+	case model.StatusPending:
+		if task.StartedAt != nil || task.CompletedAt != nil {
+			return fmt.Errorf("%w: pending task cannot have started_at or completed_at", ErrValidation)
+		}
+	case model.StatusInProgress:
+		if task.CompletedAt != nil {
+			return fmt.Errorf("%w: task in progress cannot have completed_at", ErrValidation)
+		}
+		task.StartedAt = orTime(task.StartedAt, now)
+	case model.StatusCompleted:
+		task.CompletedAt = orTime(task.CompletedAt, now)
+		task.StartedAt = orTime(task.StartedAt, *task.CompletedAt)
+		if task.StartedAt.After(*task.CompletedAt) {
+			return fmt.Errorf("%w: started_at is after completed_at", ErrValidation)
+		}
+	}
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = now
+	}
+	if task.CreatedAt.After(now) {
+		return fmt.Errorf("%w: created_at is in the future", ErrValidation)
+	}
+	task.UpdatedAt = now
+
+	tags, err := model.NormalizeTagNames(task.Tags)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	slices.Sort(tags)
+	task.Tags = nil
+	if len(tags) > 0 {
+		task.Tags = tags
+	}
+
+	schedule := task.Schedule()
+	if err := validateSchedule(&schedule); err != nil {
+		return err
+	}
+	task.Version = 1
+	return nil
+}
+
+// orTime возвращает t или, если его нет, def.
+func orTime(t *time.Time, def time.Time) *time.Time {
+	if t != nil {
+		return t
+	}
+	return &def
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/auth"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxNoteBodyLength — предел длины markdown-текста заметки в символах.
+const maxNoteBodyLength = 100_000
+
+type NoteRepository interface {
+	ListNotes(ctx context.Context, filter model.NoteFilter) (entity.NotePage, error)
+	GetNote(ctx context.Context, id int) (entity.NoteEntity, error)
+	CreateNote(ctx context.Context, note model.Note) (entity.NoteEntity, error)
+	UpdateNote(ctx context.Context, note model.Note) (entity.NoteEntity, error)
+	DeleteNote(ctx context.Context, id int) error
+}
+
+type NoteService struct {
+	repo   NoteRepository
+	tasks  TaskRepository
+	logger *slog.Logger
+}
+
+func NewNoteService(repo NoteRepository, tasks TaskRepository, logger *slog.Logger) *NoteService {
+	return &NoteService{
+		repo:   repo,
+		tasks:  tasks,
+		logger: logger,
+	}
+}
+
+func (s *NoteService) List(ctx context.Context, filter model.NoteFilter) (model.NotePage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = model.DefaultPageSize
+	}
+	filter.Limit = min(filter.Limit, model.MaxPageSize)
+	filter.Offset = max(filter.Offset, 0)
+
+	found, err := s.repo.ListNotes(ctx, filter)
+	if err != nil {
+		return model.NotePage{}, err
+	}
+	page := model.NotePage{Notes: make([]model.Note, len(found.Notes)), Total: found.Total}
+	for i := range found.Notes {
+		page.Notes[i] = *found.Notes[i].ToModel()
+	}
+	return page, nil
+}
+
+func (s *NoteService) Get(ctx context.Context, id int) (*model.Note, error) {
+	note, err := s.repo.GetNote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return note.ToModel(), nil
+}
+
+func (s *NoteService) Create(ctx context.Context, note model.Note) (*model.Note, error) {
+	note.CreatedBy = nil
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		note.CreatedBy = &principal.UserID
+	}
+	if err := s.validate(ctx, &note); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.CreateNote(ctx, note)
+	if err != nil {
+		return nil, err
+	}
+	return created.ToModel(), nil
+}
+
+// Update перезаписывает заголовок, текст и привязку к задаче.
+func (s *NoteService) Update(ctx context.Context, note model.Note) (*model.Note, error) {
+	if err := s.validate(ctx, &note); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateNote(ctx, note)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToModel(), nil
+}
+
+func (s *NoteService) Delete(ctx context.Context, id int) error {
+	return s.repo.DeleteNote(ctx, id)
+}
+
+// validate проверяет поля заметки. Привязать можно только задачу из того же
+// пространства, которая не лежит в корзине.
+func (s *NoteService) validate(ctx context.Context, note *model.Note) error {
+	note.Title = strings.TrimSpace(note.Title)
+	if n := utf8.RuneCountInString(note.Title); n < minTitleLength || n > maxTitleLength {
+		return fmt.Errorf("%w: title must be %d-%d characters", ErrValidation, minTitleLength, maxTitleLength)
+	}
+	if !utf8.ValidString(note.Body) {
+		return fmt.Errorf("%w: body must be valid UTF-8", ErrValidation)
+	}
+	if utf8.RuneCountInString(note.Body) > maxNoteBodyLength {
+		return fmt.Errorf("%w: body must be at most %d characters", ErrValidation, maxNoteBodyLength)
+	}
+
+	if note.TaskID == nil {
+		return nil
+	}
+	_, err := s.tasks.GetTaskById(ctx, *note.TaskID)
+	if errors.Is(err, repository.ErrTaskNotFound) {
+		return fmt.Errorf("%w: task %d does not exist", ErrValidation, *note.TaskID)
+	}
+	return err
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"myApi/auth"
+	"myApi/model"
+	"myApi/repository"
+	"myApi/repository/memory"
+	"myApi/reqctx"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newTestNoteService(t *testing.T) (*NoteService, *TaskService, context.Context) {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	workspaces := memory.NewWorkspaceRepository()
+	tasks := memory.NewTaskRepository(logger)
+	s := NewNoteService(memory.NewNoteRepository(), tasks, logger)
+	return s, NewTaskService(tasks, memory.NewUserRepository(workspaces), workspaces, logger),
+		reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID)
+}
+
+func TestNoteCRUD(t *testing.T) {
+	s, _, ctx := newTestNoteService(t)
+	ctx = auth.WithPrincipal(ctx, auth.Principal{UserID: 7})
+
+	created, err := s.Create(ctx, model.Note{Title: "  Plan  ", Body: "# Steps", CreatedBy: ptr(99)})
+	if err != nil {
+		t.Fatalf("Create() = %v", err)
+	}
+	if created.Title != "Plan" || created.Body != "# Steps" || created.WorkspaceID != model.DefaultWorkspaceID {
+		t.Errorf("created = %+v", created)
+	}
+	// Автора задаёт principal, а не клиент.
+	if created.CreatedBy == nil || *created.CreatedBy != 7 {
+		t.Errorf("created_by = %v, want 7", created.CreatedBy)
+	}
+
+	got, err := s.Get(ctx, created.ID)
+	if err != nil || got.Title != "Plan" {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+
+	updated, err := s.Update(ctx, model.Note{ID: created.ID, Title: "Plan v2", Body: "- one"})
+	if err != nil {
+		t.Fatalf("Update() = %v", err)
+	}
+	if updated.Title != "Plan v2" || updated.Body != "- one" || updated.UpdatedAt.Before(created.UpdatedAt) {
+		t.Errorf("updated = %+v", updated)
+	}
+	if _, err := s.Update(ctx, model.Note{ID: 999, Title: "Missing"}); !errors.Is(err, repository.ErrNoteNotFound) {
+		t.Errorf("Update() of a missing note = %v, want ErrNoteNotFound", err)
+	}
+
+	// Заметки другого пространства не видны.
+	other := reqctx.WithWorkspace(ctx, model.DefaultWorkspaceID+1)
+	if _, err := s.Get(other, created.ID); !errors.Is(err, repository.ErrNoteNotFound) {
+		t.Errorf("Get() from another workspace = %v, want ErrNoteNotFound", err)
+	}
+	if err := s.Delete(other, created.ID); !errors.Is(err, repository.ErrNoteNotFound) {
+		t.Errorf("Delete() from another workspace = %v, want ErrNoteNotFound", err)
+	}
+
+	if err := s.Delete(ctx, created.ID); err != nil {
+		t.Fatalf("Delete() = %v", err)
+	}
+	if _, err := s.Get(ctx, created.ID); !errors.Is(err, repository.ErrNoteNotFound) {
+		t.Errorf("Get() after Delete() = %v, want ErrNoteNotFound", err)
+	}
+	if err := s.Delete(ctx, created.ID); !errors.Is(err, repository.ErrNoteNotFound) {
+		t.Errorf("Delete() again = %v, want ErrNoteNotFound", err)
+	}
+}
+
+func TestNoteValidation(t *testing.T) {
+	s, tasks, ctx := newTestNoteService(t)
+	task := createTestTask(t, tasks, ctx, "Linked")
+	trashed := createTestTask(t, tasks, ctx, "Trashed")
+	if err := tasks.Delete(ctx, trashed.ID); err != nil {
+		t.Fatal(err)
+	}
+	otherCtx := reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID+1)
+	foreign := createTestTask(t, tasks, otherCtx, "Foreign")
+
+	tests := []struct {
+		name    string
+		note    model.Note
+		wantErr bool
+	}{
+		{name: "plain note", note: model.Note{Title: "Plan"}},
+		{name: "linked to a task", note: model.Note{Title: "Plan", TaskID: &task.ID}},
+		{name: "max body", note: model.Note{Title: "Plan", Body: strings.Repeat("ж", maxNoteBodyLength)}},
+		{name: "blank title", note: model.Note{Title: "  "}, wantErr: true},
+		{name: "one-letter title", note: model.Note{Title: "a"}, wantErr: true},
+		{name: "long title", note: model.Note{Title: strings.Repeat("a", maxTitleLength+1)}, wantErr: true},
+		{name: "long body", note: model.Note{Title: "Plan", Body: strings.Repeat("ж", maxNoteBodyLength+1)}, wantErr: true},
+		{name: "invalid utf-8", note: model.Note{Title: "Plan", Body: "\xff"}, wantErr: true},
+		{name: "missing task", note: model.Note{Title: "Plan", TaskID: ptr(999)}, wantErr: true},
+		{name: "trashed task", note: model.Note{Title: "Plan", TaskID: &trashed.ID}, wantErr: true},
+		{name: "task in another workspace", note: model.Note{Title: "Plan", TaskID: &foreign.ID}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := s.Create(ctx, tt.note)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("Create() = %v, want ErrValidation", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Create() = %v", err)
+			}
+
+			// Update проверяет поля так же, как Create.
+			base, err := s.Create(ctx, model.Note{Title: "Base"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			update := tt.note
+			update.ID = base.ID
+			_, err = s.Update(ctx, update)
+			if tt.wantErr != errors.Is(err, ErrValidation) {
+				t.Errorf("Update() = %v, want validation error: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && created.TaskID != nil && *created.TaskID != *tt.note.TaskID {
+				t.Errorf("task_id = %d, want %d", *created.TaskID, *tt.note.TaskID)
+			}
+		})
+	}
+}
+
+func TestNoteListPaging(t *testing.T) {
+	s, tasks, ctx := newTestNoteService(t)
+	task := createTestTask(t, tasks, ctx, "Linked")
+	var ids []int
+	for i := range 5 {
+		note := model.Note{Title: "Note " + string(rune('A'+i))}
+		if i%2 == 0 {
+			note.TaskID = &task.ID
+		}
+		created, err := s.Create(ctx, note)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+	otherCtx := reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID+1)
+	if _, err := s.Create(otherCtx, model.Note{Title: "Elsewhere"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter model.NoteFilter
+		want   []int
+		total  int
+	}{
+		// Сначала недавно изменённые.
+		{name: "default limit", filter: model.NoteFilter{}, want: []int{ids[4], ids[3], ids[2], ids[1], ids[0]}, total: 5},
+		{name: "first page", filter: model.NoteFilter{Limit: 2}, want: []int{ids[4], ids[3]}, total: 5},
+		{name: "second page", filter: model.NoteFilter{Limit: 2, Offset: 2}, want: []int{ids[2], ids[1]}, total: 5},
+		{name: "last page", filter: model.NoteFilter{Limit: 2, Offset: 4}, want: []int{ids[0]}, total: 5},
+		{name: "past the end", filter: model.NoteFilter{Limit: 2, Offset: 10}, want: []int{}, total: 5},
+		{name: "negative offset", filter: model.NoteFilter{Limit: 1, Offset: -3}, want: []int{ids[4]}, total: 5},
+		{name: "limit above max", filter: model.NoteFilter{Limit: model.MaxPageSize + 1}, want: []int{ids[4], ids[3], ids[2], ids[1], ids[0]}, total: 5},
+		{name: "by task", filter: model.NoteFilter{TaskID: &task.ID, Limit: 2}, want: []int{ids[4], ids[2]}, total: 3},
+		{name: "by task without notes", filter: model.NoteFilter{TaskID: ptr(999)}, want: []int{}, total: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("List() = %v", err)
+			}
+			got := make([]int, len(page.Notes))
+			for i := range page.Notes {
+				got[i] = page.Notes[i].ID
+			}
+			if page.Total != tt.total || !slices.Equal(got, tt.want) {
+				t.Errorf("List() = %v (total %d), want %v (total %d)", got, page.Total, tt.want, tt.total)
+			}
+		})
+	}
+
+	// Изменённая заметка поднимается в начало списка.
+	if _, err := s.Update(ctx, model.Note{ID: ids[0], Title: "Note A2"}); err != nil {
+		t.Fatal(err)
+	}
+	page, err := s.List(ctx, model.NoteFilter{Limit: 1})
+	if err != nil || len(page.Notes) != 1 || page.Notes[0].ID != ids[0] {
+		t.Errorf("List() after Update() = %+v, %v, want note %d first", page.Notes, err, ids[0])
+	}
+}
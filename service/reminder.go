@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"myApi/db/entity"
+	"myApi/model"
+	"time"
+)
+
+// reminderBatch — сколько напоминаний забирается за один запрос к хранилищу.
+const reminderBatch = 100
+
+// ReminderRepository отдаёт задачи, о сроке которых пора напомнить, сразу помечая их
+// отправленными: повторный вызов их уже не вернёт.
+type ReminderRepository interface {
+	ClaimDueReminders(ctx context.Context, from, to time.Time, limit int) ([]entity.TaskEntity, error)
+}
+
+// ReminderSink доставляет напоминания: в лог, очередь или почту.
+type ReminderSink interface {
+	Remind(ctx context.Context, reminder model.Reminder) error
+}
+
+// LogReminderSink пишет напоминания в журнал приложения.
+type LogReminderSink struct {
+	Logger *slog.Logger
+}
+
+func (s LogReminderSink) Remind(_ context.Context, r model.Reminder) error {
+	attrs := []any{
+		"task_id", r.Task.ID,
+		"workspace_id", r.Task.WorkspaceID,
+		"title", r.Task.Title,
+		"due_at", r.Task.DueAt.In(r.Task.Location()).Format(time.RFC3339),
+	}
+	if r.Task.AssigneeID != nil {
+		attrs = append(attrs, "assignee_id", *r.Task.AssigneeID)
+	}
+	s.Logger.Info("Task due soon", attrs...)
+	return nil
+}
+
+// ReminderScheduler периодически ищет задачи, срок которых наступит в пределах lead, и
+// отправляет по ним события. Задача сначала помечается, потом отправляется, поэтому
+// напоминание приходит не больше одного раза даже при нескольких репликах. Задачи,
+// срок которых уже прошёл, не напоминаются — для них есть фильтр overdue.
+type ReminderScheduler struct {
+	repo     ReminderRepository
+	sink     ReminderSink
+	lead     time.Duration
+	interval time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+func NewReminderScheduler(repo ReminderRepository, sink ReminderSink, lead, interval time.Duration, logger *slog.Logger) *ReminderScheduler {
+	return &ReminderScheduler{
+		repo:     repo,
+		sink:     sink,
+		lead:     lead,
+		interval: interval,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Run проверяет сроки раз в interval, пока не отменён ctx.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	s.logger.Info("Reminder scheduler started", "lead", s.lead, "interval", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Tick(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to send reminders", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick отправляет все накопившиеся напоминания и возвращает их число.
+func (s *ReminderScheduler) Tick(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		now := s.now()
+		tasks, err := s.repo.ClaimDueReminders(ctx, now, now.Add(s.lead), reminderBatch)
+		if err != nil {
+			return sent, err
+		}
+		for _, e := range tasks {
+			reminder := model.Reminder{Task: *e.ToModel(), Lead: s.lead, SentAt: now}
+			if err := s.sink.Remind(ctx, reminder); err != nil {
+				s.logger.Warn("Reminder was not delivered", "task_id", e.ID, "error", err)
+				continue
+			}
+			sent++
+		}
+		if len(tasks) < reminderBatch {
+			return sent, nil
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"myApi/db/entity"
+	"myApi/model"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeReminderRepo отдаёт задачи так же, как ClaimDueReminders хранилищ: срок в (from, to],
+// не больше limit за вызов и только один раз.
+type fakeReminderRepo struct {
+	tasks   []entity.TaskEntity
+	claimed map[int]bool
+	calls   int
+	err     error
+}
+
+func (r *fakeReminderRepo) ClaimDueReminders(_ context.Context, from, to time.Time, limit int) ([]entity.TaskEntity, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	var tasks []entity.TaskEntity
+	for _, e := range r.tasks {
+		if len(tasks) < limit && !r.claimed[e.ID] && e.DueAt.After(from) && !e.DueAt.After(to) {
+			r.claimed[e.ID] = true
+			tasks = append(tasks, e)
+		}
+	}
+	return tasks, nil
+}
+
+// fakeReminderSink запоминает доставленные напоминания и не доставляет задачи из fail.
+type fakeReminderSink struct {
+	sent []model.Reminder
+	fail map[int]bool
+}
+
+func (s *fakeReminderSink) Remind(_ context.Context, r model.Reminder) error {
+	if s.fail[r.Task.ID] {
+		return errors.New("sink is down")
+	}
+	s.sent = append(s.sent, r)
+	return nil
+}
+
+// reminderNow — время часов планировщика в тестах.
+var reminderNow = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// newTestReminders создаёт count задач со сроком через минуту, по одной на ID.
+func newTestReminders(count int) (*ReminderScheduler, *fakeReminderRepo, *fakeReminderSink) {
+	repo := &fakeReminderRepo{claimed: map[int]bool{}}
+	due := reminderNow.Add(time.Minute)
+	for id := 1; id <= count; id++ {
+		repo.tasks = append(repo.tasks, entity.TaskEntity{ID: id, Title: "Due", DueAt: &due})
+	}
+	sink := &fakeReminderSink{fail: map[int]bool{}}
+	s := NewReminderScheduler(repo, sink, time.Hour, time.Minute, slog.New(slog.DiscardHandler))
+	s.now = func() time.Time { return reminderNow }
+	return s, repo, sink
+}
+
+func sentIDs(sink *fakeReminderSink) []int {
+	ids := make([]int, len(sink.sent))
+	for i, r := range sink.sent {
+		ids[i] = r.Task.ID
+	}
+	return ids
+}
+
+func TestReminderTickClaimsOnce(t *testing.T) {
+	s, repo, sink := newTestReminders(2)
+	late, past := reminderNow.Add(2*time.Hour), reminderNow.Add(-time.Minute)
+	repo.tasks = append(repo.tasks,
+		entity.TaskEntity{ID: 3, Title: "Later", DueAt: &late},
+		entity.TaskEntity{ID: 4, Title: "Already late", DueAt: &past},
+	)
+	ctx := context.Background()
+
+	sent, err := s.Tick(ctx)
+	if err != nil || sent != 2 {
+		t.Fatalf("Tick() = %d, %v, want 2", sent, err)
+	}
+	if got := sentIDs(sink); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("sent = %v, want [1 2]", got)
+	}
+	if r := sink.sent[0]; r.Lead != time.Hour || !r.SentAt.Equal(reminderNow) {
+		t.Errorf("reminder lead %v sent at %v, want 1h at %v", r.Lead, r.SentAt, reminderNow)
+	}
+
+	// Второй проход не повторяет уже отправленные.
+	if sent, err := s.Tick(ctx); err != nil || sent != 0 {
+		t.Errorf("second Tick() = %d, %v, want 0", sent, err)
+	}
+	// Срок задачи 3 вошёл в окно — напоминание уходит только о ней.
+	s.now = func() time.Time { return reminderNow.Add(90 * time.Minute) }
+	if sent, err := s.Tick(ctx); err != nil || sent != 1 || sink.sent[2].Task.ID != 3 {
+		t.Errorf("Tick() later = %d, %v, sent %v, want task 3", sent, err, sentIDs(sink))
+	}
+}
+
+func TestReminderTickDrainsBatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		calls int
+	}{
+		{name: "nothing due", count: 0, calls: 1},
+		{name: "partial batch", count: reminderBatch - 1, calls: 1},
+		// Полная пачка означает, что могут быть ещё: нужен дополнительный пустой запрос.
+		{name: "exactly one batch", count: reminderBatch, calls: 2},
+		{name: "several batches", count: 2*reminderBatch + 1, calls: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, sink := newTestReminders(tt.count)
+			sent, err := s.Tick(context.Background())
+			if err != nil || sent != tt.count {
+				t.Fatalf("Tick() = %d, %v, want %d", sent, err, tt.count)
+			}
+			if repo.calls != tt.calls {
+				t.Errorf("ClaimDueReminders() called %d times, want %d", repo.calls, tt.calls)
+			}
+			if len(sink.sent) != tt.count {
+				t.Errorf("sink got %d reminders, want %d", len(sink.sent), tt.count)
+			}
+		})
+	}
+}
+
+func TestReminderTickSkipsFailedDeliveries(t *testing.T) {
+	s, _, sink := newTestReminders(reminderBatch + 2)
+	sink.fail[2] = true
+	sink.fail[reminderBatch+1] = true
+	ctx := context.Background()
+
+	// Ошибка доставки не останавливает проход: остальные напоминания уходят.
+	sent, err := s.Tick(ctx)
+	if err != nil || sent != reminderBatch {
+		t.Fatalf("Tick() = %d, %v, want %d", sent, err, reminderBatch)
+	}
+	if slices.Contains(sentIDs(sink), 2) || slices.Contains(sentIDs(sink), reminderBatch+1) {
+		t.Errorf("failed reminders were reported as sent: %v", sentIDs(sink))
+	}
+	// Задача уже помечена, поэтому недоставленное напоминание не повторяется.
+	delete(sink.fail, 2)
+	if sent, err := s.Tick(ctx); err != nil || sent != 0 {
+		t.Errorf("Tick() after recovery = %d, %v, want 0", sent, err)
+	}
+}
+
+func TestReminderTickRepositoryError(t *testing.T) {
+	s, repo, sink := newTestReminders(1)
+	repo.err = errors.New("db is down")
+	if sent, err := s.Tick(context.Background()); !errors.Is(err, repo.err) || sent != 0 {
+		t.Errorf("Tick() = %d, %v, want %v", sent, err, repo.err)
+	}
+	if len(sink.sent) != 0 {
+		t.Errorf("sink got %d reminders, want none", len(sink.sent))
+	}
+}
@@ -1 +1,8 @@
+// Package service содержит бизнес-правила приложения. Handlers вызывают сервисы
+// и работают только с model, entity и хранилище остаются за сервисом.
 package service
+
+import "errors"
+
+// ErrValidation оборачивает ошибки проверки входных данных.
+var ErrValidation = errors.New("validation failed")
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"myApi/db/entity"
+	"myApi/model"
+)
+
+type TagRepository interface {
+	ListTags(ctx context.Context) ([]entity.TagEntity, error)
+	CreateTag(ctx context.Context, name string) (entity.TagEntity, error)
+	RenameTag(ctx context.Context, id int, name string) (entity.TagEntity, error)
+	MergeTags(ctx context.Context, sourceID, targetID int) (entity.TagEntity, error)
+	DeleteTag(ctx context.Context, id int) error
+}
+
+type TagService struct {
+	repo   TagRepository
+	logger *slog.Logger
+}
+
+func NewTagService(repo TagRepository, logger *slog.Logger) *TagService {
+	return &TagService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *TagService) List(ctx context.Context) ([]model.Tag, error) {
+	found, err := s.repo.ListTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]model.Tag, len(found))
+	for i := range found {
+		tags[i] = *found[i].ToModel()
+	}
+	return tags, nil
+}
+
+func (s *TagService) Create(ctx context.Context, name string) (*model.Tag, error) {
+	name, err := tagName(name)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.repo.CreateTag(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return tag.ToModel(), nil
+}
+
+// Rename меняет имя тега у всех задач. Если имя занято, вместо переименования
+// нужно слияние.
+func (s *TagService) Rename(ctx context.Context, id int, name string) (*model.Tag, error) {
+	name, err := tagName(name)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.repo.RenameTag(ctx, id, name)
+	if err != nil {
+		return nil, err
+	}
+	return tag.ToModel(), nil
+}
+
+// Merge переносит тег sourceID на задачи как targetID и удаляет sourceID.
+func (s *TagService) Merge(ctx context.Context, sourceID, targetID int) (*model.Tag, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: cannot merge a tag into itself", ErrValidation)
+	}
+	tag, err := s.repo.MergeTags(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return tag.ToModel(), nil
+}
+
+func (s *TagService) Delete(ctx context.Context, id int) error {
+	return s.repo.DeleteTag(ctx, id)
+}
+
+func tagName(name string) (string, error) {
+	name, err := model.NormalizeTagName(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	return name, nil
+}
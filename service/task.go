@@ -0,0 +1,645 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myApi/auth"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/repository"
+	"myApi/reqctx"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	DefaultPriority = 3
+	MinPriority     = 1
+	MaxPriority     = 5
+
+	// DefaultOccurrences и MaxOccurrences ограничивают предпросмотр повторений.
+	DefaultOccurrences = 10
+	MaxOccurrences     = 100
+
+	minTitleLength       = 2
+	maxTitleLength       = 200
+	maxDescriptionLength = 10000
+)
+
+// TaskRepository — хранилище задач. Реализации: postgresql.TaskRepository и memory.TaskRepository.
+type TaskRepository interface {
+	GetAllTasks(ctx context.Context, filter model.TaskFilter) (entity.TaskPage, error)
+	SearchTasks(ctx context.Context, search model.TaskSearch) (entity.SearchPage, error)
+	CreateTask(ctx context.Context, task model.Task) (entity.TaskEntity, error)
+	UpdateTask(ctx context.Context, task model.Task) (entity.TaskEntity, error)
+	PatchTask(ctx context.Context, id int, patch model.TaskPatch) (entity.TaskEntity, error)
+	UpdateTaskStatus(ctx context.Context, task model.Task, from model.TaskStatus) (entity.TaskEntity, error)
+	GetTaskById(ctx context.Context, id int) (entity.TaskEntity, error)
+	GetDeletedTasks(ctx context.Context) ([]entity.TaskEntity, error)
+	DeleteTask(ctx context.Context, id int) error
+	RestoreTask(ctx context.Context, id int) (entity.TaskEntity, error)
+	PurgeTask(ctx context.Context, id int) error
+	AssignTask(ctx context.Context, id int, assigneeID *int, version int) (entity.TaskEntity, error)
+	AttachTags(ctx context.Context, id int, names []string, version int) (entity.TaskEntity, error)
+	DetachTags(ctx context.Context, id int, names []string, version int) (entity.TaskEntity, error)
+	MoveTask(ctx context.Context, id int, parentID *int, version int) (entity.TaskEntity, error)
+	GetSubtasks(ctx context.Context, id int) ([]entity.TaskEntity, error)
+	GetTaskTree(ctx context.Context, id int) ([]entity.TaskEntity, error)
+	AddDependency(ctx context.Context, id, blockerID, version int) (entity.TaskEntity, error)
+	RemoveDependency(ctx context.Context, id, blockerID, version int) (entity.TaskEntity, error)
+	GetDependencies(ctx context.Context, id int) (blockers, dependents []entity.TaskEntity, err error)
+	GetOpenTasks(ctx context.Context) ([]entity.TaskEntity, error)
+	ScheduleTask(ctx context.Context, id int, schedule model.TaskSchedule, version int) (entity.TaskEntity, error)
+	CreateOccurrence(ctx context.Context, prevID int, task model.Task) (entity.TaskEntity, error)
+	GetTaskHistory(ctx context.Context, taskID int, limit int, beforeID int64) ([]entity.AuditEntity, error)
+	ApplyBulk(ctx context.Context, items []model.BulkItem, atomic bool) ([]entity.BulkResultEntity, error)
+	// ImportTasks сохраняет пачки задач, которые отдаёт next, пока она не вернёт пустую пачку.
+	ImportTasks(ctx context.Context, next func() ([]model.Task, error)) (int, error)
+}
+
+type TaskService struct {
+	repo       TaskRepository
+	users      UserRepository
+	workspaces WorkspaceRepository
+	workflow   *model.Workflow
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+func NewTaskService(repo TaskRepository, users UserRepository, workspaces WorkspaceRepository, logger *slog.Logger) *TaskService {
+	workflow := model.NewWorkflow()
+	workflow.AddGuard(model.BlockerGuard)
+	return &TaskService{
+		repo:       repo,
+		users:      users,
+		workspaces: workspaces,
+		workflow:   workflow,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Workflow возвращает автомат статусов, чтобы при сборке приложения можно было добавить guard'ы.
+func (s *TaskService) Workflow() *model.Workflow {
+	return s.workflow
+}
+
+// List проверяет фильтр и подставляет значения по умолчанию; хранилище получает готовый фильтр.
+func (s *TaskService) List(ctx context.Context, filter model.TaskFilter) (model.TaskPage, error) {
+	if err := filter.Normalize(); err != nil {
+		return model.TaskPage{}, err
+	}
+	page, err := s.repo.GetAllTasks(ctx, filter)
+	if err != nil {
+		return model.TaskPage{}, err
+	}
+	return model.TaskPage{
+		Tasks:   toModels(page.Tasks),
+		Total:   page.Total,
+		HasMore: page.HasMore,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+		SortBy:  filter.SortBy,
+	}, nil
+}
+
+// Search ищет задачи по словам заголовка и описания, лучшие совпадения первыми.
+// Хранилище получает уже проверенный запрос с подставленными limit и offset.
+func (s *TaskService) Search(ctx context.Context, search model.TaskSearch) (model.SearchPage, error) {
+	if err := search.Normalize(); err != nil {
+		return model.SearchPage{}, err
+	}
+	found, err := s.repo.SearchTasks(ctx, search)
+	if err != nil {
+		return model.SearchPage{}, err
+	}
+	page := model.SearchPage{
+		Hits:   make([]model.SearchHit, len(found.Hits)),
+		Total:  found.Total,
+		Limit:  search.Limit,
+		Offset: search.Offset,
+	}
+	for i, hit := range found.Hits {
+		page.Hits[i] = model.SearchHit{
+			Task:    *hit.Task.ToModel(),
+			Rank:    hit.Rank,
+			Title:   hit.Title,
+			Snippet: hit.Snippet,
+		}
+	}
+	return page, nil
+}
+
+func (s *TaskService) Get(ctx context.Context, id int) (*model.Task, error) {
+	task, err := s.repo.GetTaskById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return task.ToModel(), nil
+}
+
+// Create проверяет задачу (пустой приоритет заменяется на DefaultPriority) и всегда создаёт её в статусе pending.
+// Автором становится пользователь из контекста запроса. Непустой ParentID создаёт подзадачу.
+func (s *TaskService) Create(ctx context.Context, task model.Task) (*model.Task, error) {
+	task.Status = model.StatusPending
+	task.CreatedBy = nil
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		task.CreatedBy = &principal.UserID
+	}
+	if err := validateTask(&task); err != nil {
+		return nil, err
+	}
+	schedule := task.Schedule()
+	if err := validateSchedule(&schedule); err != nil {
+		return nil, err
+	}
+	task.Recurrence = schedule.Recurrence
+	task.RecurredFrom = nil
+	if task.AssigneeID != nil {
+		if err := s.checkAssignee(ctx, *task.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+	if task.ParentID != nil {
+		if err := s.checkParent(ctx, *task.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.repo.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	return created.ToModel(), nil
+}
+
+// Update перезаписывает title, description и priority. Статус меняется только через переходы.
+// Ненулевой task.Version — ожидаемая версия; при расхождении возвращается model.ErrVersionMismatch.
+func (s *TaskService) Update(ctx context.Context, task model.Task) (*model.Task, error) {
+	if err := validateTask(&task); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateTask(ctx, task)
+	if errors.Is(err, repository.ErrTaskNotFound) && task.Version != 0 {
+		return nil, s.explainMissedUpdate(ctx, task.ID, func(*model.Task) error {
+			return model.ErrVersionMismatch
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToModel(), nil
+}
+
+// Patch меняет только указанные поля. Патч без изменений (пустой или из одних test)
+// ничего не записывает и не меняет версию: он лишь проверяет версию и test.
+func (s *TaskService) Patch(ctx context.Context, id int, patch model.TaskPatch) (*model.Task, error) {
+	if patch.Set.IsEmpty() {
+		task, err := s.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if patch.Version != 0 && task.Version != patch.Version {
+			return nil, model.ErrVersionMismatch
+		}
+		if !patch.Test.Matches(task) {
+			return nil, model.ErrPatchTestFailed
+		}
+		return task, nil
+	}
+	if err := validateFields(&patch.Set); err != nil {
+		return nil, err
+	}
+
+	task, err := s.repo.PatchTask(ctx, id, patch)
+	if errors.Is(err, repository.ErrTaskNotFound) && (patch.Version != 0 || !patch.Test.IsEmpty()) {
+		return nil, s.explainMissedUpdate(ctx, id, func(current *model.Task) error {
+			if patch.Version != 0 && current.Version != patch.Version {
+				return model.ErrVersionMismatch
+			}
+			return model.ErrPatchTestFailed
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task.ToModel(), nil
+}
+
+// explainMissedUpdate вызывается, когда условное обновление не затронуло строку:
+// если задачи нет — это ErrTaskNotFound, иначе решает conflict.
+func (s *TaskService) explainMissedUpdate(ctx context.Context, id int, conflict func(current *model.Task) error) error {
+	current, err := s.repo.GetTaskById(ctx, id)
+	if err != nil {
+		return err
+	}
+	return conflict(current.ToModel())
+}
+
+// Методы переходов принимают ожидаемую версию задачи (If-Match); 0 — без проверки.
+
+func (s *TaskService) Start(ctx context.Context, id, version int) (*model.Task, error) {
+	return s.transition(ctx, id, version, model.StatusPending, model.StatusInProgress)
+}
+
+// Complete завершает задачу; у повторяющейся задачи появляется следующий экземпляр.
+func (s *TaskService) Complete(ctx context.Context, id, version int) (*model.Task, error) {
+	task, err := s.transition(ctx, id, version, "", model.StatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	s.recur(ctx, task)
+	return task, nil
+}
+
+// recur создаёт следующий экземпляр завершённой повторяющейся задачи. Завершение уже
+// сохранено, поэтому ошибка только логируется: повторное завершение после reopen
+// создаст пропущенный экземпляр, а уже созданный не продублирует.
+func (s *TaskService) recur(ctx context.Context, task *model.Task) {
+	next, ok := task.NextOccurrence(s.now())
+	if !ok {
+		return
+	}
+	_, err := s.repo.CreateOccurrence(ctx, task.ID, next)
+	if err != nil && !errors.Is(err, repository.ErrOccurrenceExists) {
+		s.logger.Error("Failed to create next occurrence", "task_id", task.ID, "error", err)
+	}
+}
+
+func (s *TaskService) Stop(ctx context.Context, id, version int) (*model.Task, error) {
+	return s.transition(ctx, id, version, "", model.StatusPending)
+}
+
+func (s *TaskService) Reopen(ctx context.Context, id, version int) (*model.Task, error) {
+	return s.transition(ctx, id, version, model.StatusCompleted, model.StatusInProgress)
+}
+
+// transition применяет переход Workflow; непустой expect ограничивает исходный статус
+// (start и reopen ведут в один и тот же in_progress из разных состояний).
+func (s *TaskService) transition(ctx context.Context, id, version int, expect, to model.TaskStatus) (*model.Task, error) {
+	current, err := s.repo.GetTaskById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && current.Version != version {
+		return nil, model.ErrVersionMismatch
+	}
+
+	task := current.ToModel()
+	from := task.Status
+	if expect != "" && from != expect {
+		return nil, fmt.Errorf("%w: task is %s", model.ErrInvalidTransition, from)
+	}
+	if err := s.workflow.Transition(task, to, s.now()); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateTaskStatus(ctx, *task, from)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToModel(), nil
+}
+
+// Assign назначает исполнителя задачи; version — ожидаемая версия (If-Match), 0 — без проверки.
+func (s *TaskService) Assign(ctx context.Context, id, assigneeID, version int) (*model.Task, error) {
+	if err := s.checkAssignee(ctx, assigneeID); err != nil {
+		return nil, err
+	}
+	return s.assign(ctx, id, &assigneeID, version)
+}
+
+func (s *TaskService) Unassign(ctx context.Context, id, version int) (*model.Task, error) {
+	return s.assign(ctx, id, nil, version)
+}
+
+func (s *TaskService) assign(ctx context.Context, id int, assigneeID *int, version int) (*model.Task, error) {
+	task, err := s.repo.AssignTask(ctx, id, assigneeID, version)
+	if errors.Is(err, repository.ErrTaskNotFound) && version != 0 {
+		return nil, s.explainMissedUpdate(ctx, id, func(*model.Task) error {
+			return model.ErrVersionMismatch
+		})
+	}
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, fmt.Errorf("%w: assignee does not exist", ErrValidation)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task.ToModel(), nil
+}
+
+// AddTags ставит задаче теги по именам; несуществующие теги создаются.
+func (s *TaskService) AddTags(ctx context.Context, id int, names []string, version int) (*model.Task, error) {
+	return s.retag(ctx, id, names, version, s.repo.AttachTags)
+}
+
+// RemoveTags снимает теги с задачи; сами теги остаются в пространстве.
+func (s *TaskService) RemoveTags(ctx context.Context, id int, names []string, version int) (*model.Task, error) {
+	return s.retag(ctx, id, names, version, s.repo.DetachTags)
+}
+
+func (s *TaskService) retag(ctx context.Context, id int, names []string, version int, apply func(ctx context.Context, id int, names []string, version int) (entity.TaskEntity, error)) (*model.Task, error) {
+	names, err := model.NormalizeTagNames(names)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: at least one tag is required", ErrValidation)
+	}
+
+	task, err := apply(ctx, id, names, version)
+	if errors.Is(err, repository.ErrTaskNotFound) && version != 0 {
+		return nil, s.explainMissedUpdate(ctx, id, func(*model.Task) error {
+			return model.ErrVersionMismatch
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task.ToModel(), nil
+}
+
+// SetParent делает задачу подзадачей parentID; version — ожидаемая версия (If-Match), 0 — без проверки.
+// Если parentID — сама задача или её подзадача, возвращается model.ErrTaskCycle.
+func (s *TaskService) SetParent(ctx context.Context, id, parentID, version int) (*model.Task, error) {
+	if parentID <= 0 {
+		return nil, fmt.Errorf("%w: invalid parent", ErrValidation)
+	}
+	if parentID == id {
+		return nil, model.ErrTaskCycle
+	}
+	return s.move(ctx, id, &parentID, version)
+}
+
+// RemoveParent делает подзадачу корневой задачей.
+func (s *TaskService) RemoveParent(ctx context.Context, id, version int) (*model.Task, error) {
+	return s.move(ctx, id, nil, version)
+}
+
+func (s *TaskService) move(ctx context.Context, id int, parentID *int, version int) (*model.Task, error) {
+	task, err := s.repo.MoveTask(ctx, id, parentID, version)
+	if errors.Is(err, repository.ErrTaskNotFound) && version != 0 {
+		return nil, s.explainMissedUpdate(ctx, id, func(*model.Task) error {
+			return model.ErrVersionMismatch
+		})
+	}
+	if errors.Is(err, repository.ErrParentNotFound) {
+		return nil, fmt.Errorf("%w: parent task %d does not exist", ErrValidation, *parentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task.ToModel(), nil
+}
+
+// Subtasks возвращает подзадачи первого уровня.
+func (s *TaskService) Subtasks(ctx context.Context, id int) ([]model.Task, error) {
+	tasks, err := s.repo.GetSubtasks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toModels(tasks), nil
+}
+
+// Tree возвращает задачу со всеми уровнями подзадач.
+func (s *TaskService) Tree(ctx context.Context, id int) (*model.TaskNode, error) {
+	tasks, err := s.repo.GetTaskTree(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := model.BuildTaskTree(id, toModels(tasks))
+	if !ok {
+		return nil, repository.ErrTaskNotFound
+	}
+	return root, nil
+}
+
+// AddBlocker отмечает, что blockerID блокирует задачу id: пока блокер не завершён, задачу
+// нельзя начать или завершить. Связь, замыкающая цикл, отклоняется с model.ErrDependencyCycle.
+func (s *TaskService) AddBlocker(ctx context.Context, id, blockerID, version int) (*model.Task, error) {
+	if blockerID <= 0 {
+		return nil, fmt.Errorf("%w: invalid blocker", ErrValidation)
+	}
+	if blockerID == id {
+		return nil, model.ErrDependencyCycle
+	}
+	return s.depend(ctx, id, blockerID, version, s.repo.AddDependency)
+}
+
+func (s *TaskService) RemoveBlocker(ctx context.Context, id, blockerID, version int) (*model.Task, error) {
+	return s.depend(ctx, id, blockerID, version, s.repo.RemoveDependency)
+}
+
+func (s *TaskService) depend(ctx context.Context, id, blockerID, version int, apply func(ctx context.Context, id, blockerID, version int) (entity.TaskEntity, error)) (*model.Task, error) {
+	task, err := apply(ctx, id, blockerID, version)
+	if errors.Is(err, repository.ErrTaskNotFound) && version != 0 {
+		return nil, s.explainMissedUpdate(ctx, id, func(*model.Task) error {
+			return model.ErrVersionMismatch
+		})
+	}
+	if errors.Is(err, repository.ErrBlockerNotFound) {
+		return nil, fmt.Errorf("%w: blocking task %d does not exist", ErrValidation, blockerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task.ToModel(), nil
+}
+
+// Dependencies возвращает блокеры задачи и задачи, которые она блокирует.
+func (s *TaskService) Dependencies(ctx context.Context, id int) (model.TaskDependencies, error) {
+	blockers, dependents, err := s.repo.GetDependencies(ctx, id)
+	if err != nil {
+		return model.TaskDependencies{}, err
+	}
+	return model.TaskDependencies{BlockedBy: toModels(blockers), Blocks: toModels(dependents)}, nil
+}
+
+// Plan раскладывает открытые задачи пространства на волны в порядке зависимостей;
+// первая волна — задачи, которые можно брать в работу сейчас.
+func (s *TaskService) Plan(ctx context.Context) ([]model.PlanStep, error) {
+	tasks, err := s.repo.GetOpenTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.PlanTasks(toModels(tasks)), nil
+}
+
+// Schedule заменяет плановые даты и зону задачи целиком: nil снимает дату.
+// При смене срока напоминание о нём придёт заново.
+func (s *TaskService) Schedule(ctx context.Context, id int, schedule model.TaskSchedule, version int) (*model.Task, error) {
+	if err := validateSchedule(&schedule); err != nil {
+		return nil, err
+	}
+	task, err := s.repo.ScheduleTask(ctx, id, schedule, version)
+	if errors.Is(err, repository.ErrTaskNotFound) && version != 0 {
+		return nil, s.explainMissedUpdate(ctx, id, func(*model.Task) error {
+			return model.ErrVersionMismatch
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task.ToModel(), nil
+}
+
+// Occurrences возвращает ближайшие сроки повторяющейся задачи: текущий, если задача ещё
+// открыта, и следующие за ним. Время — в зоне задачи.
+func (s *TaskService) Occurrences(ctx context.Context, id, limit int) ([]time.Time, error) {
+	task, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.Recurrence == "" || task.DueAt == nil {
+		return nil, fmt.Errorf("%w: task does not recur", ErrValidation)
+	}
+	if limit <= 0 || limit > MaxOccurrences {
+		limit = DefaultOccurrences
+	}
+	loc := task.Location()
+	rule, err := model.ParseRecurrence(task.Recurrence, loc)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status == model.StatusCompleted {
+		limit++
+	}
+	occurrences := rule.Occurrences(*task.DueAt, loc, limit)
+	if task.Status == model.StatusCompleted && len(occurrences) > 0 {
+		occurrences = occurrences[1:]
+	}
+	return occurrences, nil
+}
+
+func (s *TaskService) checkParent(ctx context.Context, parentID int) error {
+	if parentID <= 0 {
+		return fmt.Errorf("%w: invalid parent", ErrValidation)
+	}
+	_, err := s.repo.GetTaskById(ctx, parentID)
+	if errors.Is(err, repository.ErrTaskNotFound) {
+		return fmt.Errorf("%w: parent task %d does not exist", ErrValidation, parentID)
+	}
+	return err
+}
+
+func (s *TaskService) checkAssignee(ctx context.Context, userID int) error {
+	if userID <= 0 {
+		return fmt.Errorf("%w: invalid assignee", ErrValidation)
+	}
+	_, err := s.users.GetUserByID(ctx, userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return fmt.Errorf("%w: assignee %d does not exist", ErrValidation, userID)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Назначить можно только участника пространства, иначе исполнитель не увидит задачу.
+	ws, ok := reqctx.Workspace(ctx)
+	if !ok {
+		return repository.ErrNoWorkspace
+	}
+	member, err := s.workspaces.IsMember(ctx, ws, userID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return fmt.Errorf("%w: assignee %d is not a member of the workspace", ErrValidation, userID)
+	}
+	return nil
+}
+
+func (s *TaskService) Delete(ctx context.Context, id int) error {
+	return s.repo.DeleteTask(ctx, id)
+}
+
+func (s *TaskService) ListTrash(ctx context.Context) ([]model.Task, error) {
+	tasks, err := s.repo.GetDeletedTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toModels(tasks), nil
+}
+
+func (s *TaskService) Restore(ctx context.Context, id int) (*model.Task, error) {
+	task, err := s.repo.RestoreTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return task.ToModel(), nil
+}
+
+func (s *TaskService) Purge(ctx context.Context, id int) error {
+	return s.repo.PurgeTask(ctx, id)
+}
+
+// History возвращает страницу истории задачи; beforeID — ID последней записи предыдущей страницы.
+func (s *TaskService) History(ctx context.Context, taskID int, limit int, beforeID int64) (model.HistoryPage, error) {
+	if limit <= 0 || limit > model.MaxPageSize {
+		limit = model.DefaultPageSize
+	}
+	entries, err := s.repo.GetTaskHistory(ctx, taskID, limit+1, beforeID)
+	if err != nil {
+		return model.HistoryPage{}, err
+	}
+
+	page := model.HistoryPage{}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		page.HasMore = true
+	}
+	for i := range entries {
+		page.Entries = append(page.Entries, entries[i].ToModel())
+	}
+	return page, nil
+}
+
+func validateTask(task *model.Task) error {
+	return validateFields(&model.TaskFields{
+		Title:       &task.Title,
+		Description: &task.Description,
+		Priority:    &task.Priority,
+	})
+}
+
+func validateSchedule(schedule *model.TaskSchedule) error {
+	if err := schedule.Normalize(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	return nil
+}
+
+// validateFields проверяет указанные поля; пустой приоритет заменяется на DefaultPriority.
+func validateFields(f *model.TaskFields) error {
+	if f.Title != nil {
+		*f.Title = strings.TrimSpace(*f.Title)
+		if n := utf8.RuneCountInString(*f.Title); n < minTitleLength || n > maxTitleLength {
+			return fmt.Errorf("%w: title must be %d-%d characters", ErrValidation, minTitleLength, maxTitleLength)
+		}
+	}
+	if f.Description != nil && utf8.RuneCountInString(*f.Description) > maxDescriptionLength {
+		return fmt.Errorf("%w: description must be at most %d characters", ErrValidation, maxDescriptionLength)
+	}
+	if f.Priority != nil {
+		if *f.Priority == 0 {
+			*f.Priority = DefaultPriority
+		}
+		if *f.Priority < MinPriority || *f.Priority > MaxPriority {
+			return fmt.Errorf("%w: priority must be between %d and %d", ErrValidation, MinPriority, MaxPriority)
+		}
+	}
+	return nil
+}
+
+func toModels(entities []entity.TaskEntity) []model.Task {
+	tasks := make([]model.Task, 0, len(entities))
+	for i := range entities {
+		tasks = append(tasks, *entities[i].ToModel())
+	}
+	return tasks
+}
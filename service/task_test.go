@@ -0,0 +1,566 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"myApi/model"
+	"myApi/repository"
+	"myApi/repository/memory"
+	"myApi/reqctx"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTaskService(t *testing.T) (*TaskService, context.Context) {
+	t.Helper()
+	logger := slog.New(slog.DiscardHandler)
+	workspaces := memory.NewWorkspaceRepository()
+	s := NewTaskService(memory.NewTaskRepository(logger), memory.NewUserRepository(workspaces), workspaces, logger)
+	return s, reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID)
+}
+
+func createTestTask(t *testing.T, s *TaskService, ctx context.Context, title string) *model.Task {
+	t.Helper()
+	task, err := s.Create(ctx, model.Task{Title: title})
+	if err != nil {
+		t.Fatalf("Create(%q) = %v", title, err)
+	}
+	return task
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCreateValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     model.Task
+		wantErr  bool
+		title    string
+		priority int
+	}{
+		{name: "defaults priority", task: model.Task{Title: "Write report"}, title: "Write report", priority: DefaultPriority},
+		{name: "trims title", task: model.Task{Title: "  Write report  ", Priority: 1}, title: "Write report", priority: 1},
+		{name: "keeps max priority", task: model.Task{Title: "ok", Priority: MaxPriority}, title: "ok", priority: MaxPriority},
+		{name: "two-letter title", task: model.Task{Title: "ab"}, title: "ab", priority: DefaultPriority},
+		{name: "multibyte title counts runes", task: model.Task{Title: "ёж"}, title: "ёж", priority: DefaultPriority},
+		{name: "empty title", task: model.Task{Title: ""}, wantErr: true},
+		{name: "blank title", task: model.Task{Title: "   "}, wantErr: true},
+		{name: "one-letter title", task: model.Task{Title: "a"}, wantErr: true},
+		{name: "long title", task: model.Task{Title: strings.Repeat("a", maxTitleLength+1)}, wantErr: true},
+		{name: "long description", task: model.Task{Title: "ok", Description: strings.Repeat("д", maxDescriptionLength+1)}, wantErr: true},
+		{name: "negative priority", task: model.Task{Title: "ok", Priority: -1}, wantErr: true},
+		{name: "priority above max", task: model.Task{Title: "ok", Priority: MaxPriority + 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ctx := newTestTaskService(t)
+			created, err := s.Create(ctx, tt.task)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Fatalf("Create() = %v, want ErrValidation", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create() = %v", err)
+			}
+			if created.Title != tt.title || created.Priority != tt.priority {
+				t.Errorf("created title %q priority %d, want %q %d", created.Title, created.Priority, tt.title, tt.priority)
+			}
+			if created.Status != model.StatusPending || created.Version != 1 {
+				t.Errorf("created status %s version %d, want pending 1", created.Status, created.Version)
+			}
+		})
+	}
+}
+
+func TestCreateIgnoresStatus(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	created, err := s.Create(ctx, model.Task{Title: "Done already", Status: model.StatusCompleted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Status != model.StatusPending {
+		t.Errorf("status = %s, want pending", created.Status)
+	}
+}
+
+func TestPatchValidatesOnlyGivenFields(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	task := createTestTask(t, s, ctx, "Original")
+
+	if _, err := s.Patch(ctx, task.ID, model.TaskPatch{Set: model.TaskFields{Title: ptr("x")}}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Patch(short title) = %v, want ErrValidation", err)
+	}
+	if _, err := s.Patch(ctx, task.ID, model.TaskPatch{Set: model.TaskFields{Priority: ptr(9)}}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Patch(priority 9) = %v, want ErrValidation", err)
+	}
+
+	patched, err := s.Patch(ctx, task.ID, model.TaskPatch{Set: model.TaskFields{Description: ptr("details")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patched.Title != "Original" || patched.Description != "details" || patched.Priority != DefaultPriority {
+		t.Errorf("patched = %+v", patched)
+	}
+	if patched.Version != task.Version+1 {
+		t.Errorf("version = %d, want %d", patched.Version, task.Version+1)
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	type step func(s *TaskService, ctx context.Context, id, version int) (*model.Task, error)
+	var (
+		start    step = (*TaskService).Start
+		complete step = (*TaskService).Complete
+		stop     step = (*TaskService).Stop
+		reopen   step = (*TaskService).Reopen
+	)
+
+	tests := []struct {
+		name   string
+		setup  []step
+		action step
+		want   model.TaskStatus
+		err    error
+	}{
+		{name: "start pending", action: start, want: model.StatusInProgress},
+		{name: "complete in progress", setup: []step{start}, action: complete, want: model.StatusCompleted},
+		{name: "stop in progress", setup: []step{start}, action: stop, want: model.StatusPending},
+		{name: "reopen completed", setup: []step{start, complete}, action: reopen, want: model.StatusInProgress},
+		{name: "complete pending", action: complete, err: model.ErrInvalidTransition},
+		{name: "stop pending", action: stop, err: model.ErrInvalidTransition},
+		{name: "reopen pending", action: reopen, err: model.ErrInvalidTransition},
+		{name: "start in progress", setup: []step{start}, action: start, err: model.ErrInvalidTransition},
+		{name: "reopen in progress", setup: []step{start}, action: reopen, err: model.ErrInvalidTransition},
+		{name: "start completed", setup: []step{start, complete}, action: start, err: model.ErrInvalidTransition},
+		{name: "stop completed", setup: []step{start, complete}, action: stop, err: model.ErrInvalidTransition},
+		{name: "complete completed", setup: []step{start, complete}, action: complete, err: model.ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ctx := newTestTaskService(t)
+			task := createTestTask(t, s, ctx, "Task")
+			for _, setup := range tt.setup {
+				var err error
+				if task, err = setup(s, ctx, task.ID, 0); err != nil {
+					t.Fatalf("setup: %v", err)
+				}
+			}
+
+			got, err := tt.action(s, ctx, task.ID, task.Version)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				current, _ := s.Get(ctx, task.ID)
+				if current.Status != task.Status || current.Version != task.Version {
+					t.Errorf("refused transition changed the task: %+v", current)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.want || got.Version != task.Version+1 {
+				t.Errorf("status %s version %d, want %s %d", got.Status, got.Version, tt.want, task.Version+1)
+			}
+		})
+	}
+}
+
+func TestTransitionTimestamps(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	task := createTestTask(t, s, ctx, "Task")
+
+	started, err := s.Start(ctx, task.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started.StartedAt == nil || started.CompletedAt != nil {
+		t.Fatalf("after start: started_at %v, completed_at %v", started.StartedAt, started.CompletedAt)
+	}
+	completed, err := s.Complete(ctx, task.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completed.CompletedAt == nil || !completed.StartedAt.Equal(*started.StartedAt) {
+		t.Fatalf("after complete: started_at %v, completed_at %v", completed.StartedAt, completed.CompletedAt)
+	}
+	reopened, err := s.Reopen(ctx, task.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.CompletedAt != nil || !reopened.StartedAt.Equal(*started.StartedAt) {
+		t.Fatalf("after reopen: started_at %v, completed_at %v", reopened.StartedAt, reopened.CompletedAt)
+	}
+}
+
+func TestTransitionVersionMismatch(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	task := createTestTask(t, s, ctx, "Task")
+	if _, err := s.Start(ctx, task.ID, task.Version+1); !errors.Is(err, model.ErrVersionMismatch) {
+		t.Errorf("Start(stale version) = %v, want ErrVersionMismatch", err)
+	}
+}
+
+func TestMissedUpdateErrors(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	task := createTestTask(t, s, ctx, "Original")
+	deleted := createTestTask(t, s, ctx, "Deleted")
+	if err := s.Delete(ctx, deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+	stale := task.Version
+	if _, err := s.Patch(ctx, task.ID, model.TaskPatch{Set: model.TaskFields{Priority: ptr(5)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		patch func() (*model.Task, error)
+		err   error
+	}{
+		{
+			name: "update with stale version",
+			patch: func() (*model.Task, error) {
+				return s.Update(ctx, model.Task{ID: task.ID, Title: "New", Version: stale})
+			},
+			err: model.ErrVersionMismatch,
+		},
+		{
+			name: "update of deleted task",
+			patch: func() (*model.Task, error) {
+				return s.Update(ctx, model.Task{ID: deleted.ID, Title: "New", Version: 1})
+			},
+			err: repository.ErrTaskNotFound,
+		},
+		{
+			name: "update of missing task",
+			patch: func() (*model.Task, error) {
+				return s.Update(ctx, model.Task{ID: 999, Title: "New", Version: 1})
+			},
+			err: repository.ErrTaskNotFound,
+		},
+		{
+			name: "patch with stale version",
+			patch: func() (*model.Task, error) {
+				return s.Patch(ctx, task.ID, model.TaskPatch{Set: model.TaskFields{Title: ptr("New")}, Version: stale})
+			},
+			err: model.ErrVersionMismatch,
+		},
+		{
+			name: "patch with failed test",
+			patch: func() (*model.Task, error) {
+				return s.Patch(ctx, task.ID, model.TaskPatch{Set: model.TaskFields{Title: ptr("New")}, Test: model.TaskFields{Title: ptr("Other")}})
+			},
+			err: model.ErrPatchTestFailed,
+		},
+		{
+			name: "patch with stale version and failed test",
+			patch: func() (*model.Task, error) {
+				return s.Patch(ctx, task.ID, model.TaskPatch{Set: model.TaskFields{Title: ptr("New")}, Test: model.TaskFields{Title: ptr("Other")}, Version: stale})
+			},
+			err: model.ErrVersionMismatch,
+		},
+		{
+			name: "patch of deleted task",
+			patch: func() (*model.Task, error) {
+				return s.Patch(ctx, deleted.ID, model.TaskPatch{Set: model.TaskFields{Title: ptr("New")}, Test: model.TaskFields{Title: ptr("Deleted")}})
+			},
+			err: repository.ErrTaskNotFound,
+		},
+		{
+			name: "patch of missing task",
+			patch: func() (*model.Task, error) {
+				return s.Patch(ctx, 999, model.TaskPatch{Set: model.TaskFields{Title: ptr("New")}, Version: 1})
+			},
+			err: repository.ErrTaskNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.patch(); !errors.Is(err, tt.err) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPatchWithoutChangesDoesNotWrite(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	task := createTestTask(t, s, ctx, "Original")
+
+	tests := []struct {
+		name  string
+		patch model.TaskPatch
+		err   error
+	}{
+		{name: "empty", patch: model.TaskPatch{}},
+		{name: "empty with current version", patch: model.TaskPatch{Version: task.Version}},
+		{name: "empty with stale version", patch: model.TaskPatch{Version: task.Version + 1}, err: model.ErrVersionMismatch},
+		{name: "passing test", patch: model.TaskPatch{Test: model.TaskFields{Title: ptr("Original"), Priority: ptr(DefaultPriority)}}},
+		{name: "failing test", patch: model.TaskPatch{Test: model.TaskFields{Title: ptr("Other")}}, err: model.ErrPatchTestFailed},
+		{name: "test with stale version", patch: model.TaskPatch{Test: model.TaskFields{Title: ptr("Original")}, Version: task.Version + 1}, err: model.ErrVersionMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Patch(ctx, task.ID, tt.patch)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err == nil && got.Version != task.Version {
+				t.Errorf("version = %d, want %d", got.Version, task.Version)
+			}
+		})
+	}
+
+	history, err := s.History(ctx, task.ID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Entries) != 1 {
+		t.Errorf("history has %d entries, want only the create", len(history.Entries))
+	}
+}
+
+func TestCheckAssignee(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	workspaces := memory.NewWorkspaceRepository()
+	users := memory.NewUserRepository(workspaces)
+	s := NewTaskService(memory.NewTaskRepository(logger), users, workspaces, logger)
+	ctx := reqctx.WithWorkspace(context.Background(), model.DefaultWorkspaceID)
+
+	var ids []int
+	for _, email := range []string{"member@example.com", "outsider@example.com"} {
+		user, err := users.CreateUser(ctx, model.User{Email: email, Role: model.RoleMember})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, user.ID)
+	}
+	member, outsider := ids[0], ids[1]
+	if err := workspaces.AddMember(ctx, model.DefaultWorkspaceID, member); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		assignee int
+		wantErr  error
+	}{
+		{name: "member", ctx: ctx, assignee: member},
+		{name: "not a member", ctx: ctx, assignee: outsider, wantErr: ErrValidation},
+		{name: "missing user", ctx: ctx, assignee: 999, wantErr: ErrValidation},
+		{name: "zero id", ctx: ctx, assignee: 0, wantErr: ErrValidation},
+		{name: "no workspace", ctx: context.Background(), assignee: member, wantErr: repository.ErrNoWorkspace},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := s.Create(tt.ctx, model.Task{Title: "Assigned", AssigneeID: &tt.assignee})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (created.AssigneeID == nil || *created.AssigneeID != tt.assignee) {
+				t.Errorf("assignee = %v, want %d", created.AssigneeID, tt.assignee)
+			}
+
+			task := createTestTask(t, s, ctx, "Unassigned")
+			assigned, err := s.Assign(tt.ctx, task.ID, tt.assignee, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Assign() = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				// Отклонённое назначение не меняет задачу.
+				if got, _ := s.Get(ctx, task.ID); got.AssigneeID != nil || got.Version != task.Version {
+					t.Errorf("task after a rejected Assign() = %+v", got)
+				}
+				return
+			}
+			if assigned.AssigneeID == nil || *assigned.AssigneeID != tt.assignee {
+				t.Errorf("assignee = %v, want %d", assigned.AssigneeID, tt.assignee)
+			}
+		})
+	}
+}
+
+func TestSetParent(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	root := createTestTask(t, s, ctx, "Root")
+	child, err := s.Create(ctx, model.Task{Title: "Child", ParentID: &root.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	grandchild, err := s.Create(ctx, model.Task{Title: "Grandchild", ParentID: &child.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		id       int
+		parentID int
+		wantErr  error
+	}{
+		{"itself", root.ID, root.ID, model.ErrTaskCycle},
+		{"under a grandchild", root.ID, grandchild.ID, model.ErrTaskCycle},
+		{"invalid parent", child.ID, 0, ErrValidation},
+		{"missing parent", child.ID, 999, ErrValidation},
+	}
+	for _, tt := range tests {
+		if _, err := s.SetParent(ctx, tt.id, tt.parentID, 0); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: SetParent(%d, %d) = %v, want %v", tt.name, tt.id, tt.parentID, err, tt.wantErr)
+		}
+	}
+
+	tree, err := s.Tree(ctx, root.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Children) != 1 || len(tree.Children[0].Children) != 1 || tree.Children[0].Children[0].Task.ID != grandchild.ID {
+		t.Fatalf("Tree() = %+v, want root → child → grandchild", tree)
+	}
+	if _, err := s.Start(ctx, grandchild.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Complete(ctx, grandchild.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Get(ctx, root.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Subtasks != (model.SubtaskProgress{Total: 2, Completed: 1}) || got.Version <= root.Version {
+		t.Errorf("root after completing the grandchild: subtasks %+v, version %d (was %d)", got.Subtasks, got.Version, root.Version)
+	}
+}
+
+func TestScheduleValidation(t *testing.T) {
+	due := time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)
+	start := due.Add(-2 * time.Hour)
+	tests := []struct {
+		name       string
+		schedule   model.TaskSchedule
+		wantErr    bool
+		recurrence string
+	}{
+		{name: "no dates", schedule: model.TaskSchedule{}},
+		{name: "due only", schedule: model.TaskSchedule{DueAt: &due}},
+		{name: "start before due", schedule: model.TaskSchedule{StartAt: &start, DueAt: &due, Timezone: "Europe/Moscow"}},
+		{name: "start equals due", schedule: model.TaskSchedule{StartAt: &due, DueAt: &due}},
+		{name: "start without due", schedule: model.TaskSchedule{StartAt: &start}},
+		{
+			name:       "recurrence is canonicalized",
+			schedule:   model.TaskSchedule{DueAt: &due, Recurrence: "rrule:freq=weekly;interval=1;byday=mo,th"},
+			recurrence: "FREQ=WEEKLY;BYDAY=MO,TH",
+		},
+		{name: "start after due", schedule: model.TaskSchedule{StartAt: &due, DueAt: &start}, wantErr: true},
+		{name: "unknown timezone", schedule: model.TaskSchedule{DueAt: &due, Timezone: "Mars/Olympus"}, wantErr: true},
+		{name: "recurrence without due", schedule: model.TaskSchedule{Recurrence: "FREQ=DAILY"}, wantErr: true},
+		{name: "malformed recurrence", schedule: model.TaskSchedule{DueAt: &due, Recurrence: "FREQ=DAILY;COUNT"}, wantErr: true},
+		{name: "unknown frequency", schedule: model.TaskSchedule{DueAt: &due, Recurrence: "FREQ=HOURLY"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ctx := newTestTaskService(t)
+			task := createTestTask(t, s, ctx, "Scheduled")
+			scheduled, err := s.Schedule(ctx, task.ID, tt.schedule, task.Version)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("Schedule() = %v, want ErrValidation", err)
+				}
+				// Отклонённое расписание задачу не меняет.
+				if got, _ := s.Get(ctx, task.ID); got.Version != task.Version {
+					t.Errorf("version after a rejected schedule = %d, want %d", got.Version, task.Version)
+				}
+			} else if err != nil {
+				t.Fatalf("Schedule() = %v", err)
+			} else if scheduled.Recurrence != tt.recurrence || scheduled.Timezone != tt.schedule.Timezone {
+				t.Errorf("scheduled recurrence %q timezone %q, want %q %q",
+					scheduled.Recurrence, scheduled.Timezone, tt.recurrence, tt.schedule.Timezone)
+			}
+
+			// Create проверяет даты так же, как Schedule.
+			created, err := s.Create(ctx, model.Task{
+				Title: "Created", StartAt: tt.schedule.StartAt, DueAt: tt.schedule.DueAt,
+				Timezone: tt.schedule.Timezone, Recurrence: tt.schedule.Recurrence,
+			})
+			if tt.wantErr != errors.Is(err, ErrValidation) {
+				t.Fatalf("Create() = %v, want validation error: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && created.Recurrence != tt.recurrence {
+				t.Errorf("created recurrence = %q, want %q", created.Recurrence, tt.recurrence)
+			}
+		})
+	}
+}
+
+func TestScheduleVersionMismatch(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	task := createTestTask(t, s, ctx, "Scheduled")
+	due := time.Now().Add(time.Hour)
+	if _, err := s.Schedule(ctx, task.ID, model.TaskSchedule{DueAt: &due}, task.Version+1); !errors.Is(err, model.ErrVersionMismatch) {
+		t.Errorf("Schedule() with a stale version = %v, want ErrVersionMismatch", err)
+	}
+	if _, err := s.Schedule(ctx, 999, model.TaskSchedule{DueAt: &due}, 0); !errors.Is(err, repository.ErrTaskNotFound) {
+		t.Errorf("Schedule() of a missing task = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestSearchNormalizes(t *testing.T) {
+	s, ctx := newTestTaskService(t)
+	for _, title := range []string{"Crash on login", "Crash on logout", "Crash in reports"} {
+		createTestTask(t, s, ctx, title)
+	}
+
+	tests := []struct {
+		name    string
+		search  model.TaskSearch
+		limit   int
+		offset  int
+		hits    int
+		total   int
+		wantErr bool
+	}{
+		{name: "defaults", search: model.TaskSearch{Query: "  crash  "}, limit: model.DefaultSearchLimit, hits: 3, total: 3},
+		{name: "window", search: model.TaskSearch{Query: "crash", Limit: 1, Offset: 2}, limit: 1, offset: 2, hits: 1, total: 3},
+		{name: "limit above max", search: model.TaskSearch{Query: "crash", Limit: model.MaxSearchLimit + 1}, limit: model.MaxSearchLimit, hits: 3, total: 3},
+		{name: "negative offset", search: model.TaskSearch{Query: "crash", Limit: -1, Offset: -5}, limit: model.DefaultSearchLimit, hits: 3, total: 3},
+		{name: "offset past the end", search: model.TaskSearch{Query: "crash", Offset: 10}, limit: model.DefaultSearchLimit, offset: 10, total: 3},
+		{name: "max query", search: model.TaskSearch{Query: "crash " + strings.Repeat("ж", 194)}, limit: model.DefaultSearchLimit},
+		{name: "empty query", search: model.TaskSearch{Query: ""}, wantErr: true},
+		{name: "blank query", search: model.TaskSearch{Query: " \t "}, wantErr: true},
+		{name: "long query", search: model.TaskSearch{Query: strings.Repeat("ж", 201)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.Search(ctx, tt.search)
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrInvalidSearch) {
+					t.Errorf("Search() = %v, want ErrInvalidSearch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Search() = %v", err)
+			}
+			if page.Limit != tt.limit || page.Offset != tt.offset || len(page.Hits) != tt.hits || page.Total != tt.total {
+				t.Errorf("Search() = limit %d offset %d, %d of %d hits, want %d %d, %d of %d",
+					page.Limit, page.Offset, len(page.Hits), page.Total, tt.limit, tt.offset, tt.hits, tt.total)
+			}
+		})
+	}
+
+	page, err := s.Search(ctx, model.TaskSearch{Query: "LOGIN crash"})
+	if err != nil || len(page.Hits) != 1 {
+		t.Fatalf("Search() = %+v, %v", page, err)
+	}
+	if hit := page.Hits[0]; hit.Task.Title != "Crash on login" || model.RenderHighlight(hit.Title) != "<mark>Crash</mark> on <mark>login</mark>" {
+		t.Errorf("hit = %q (task %q)", hit.Title, hit.Task.Title)
+	}
+}
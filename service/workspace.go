@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"myApi/auth"
+	"myApi/db/entity"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"strings"
+	"unicode/utf8"
+)
+
+type WorkspaceRepository interface {
+	CreateWorkspace(ctx context.Context, name string, ownerID int) (entity.WorkspaceEntity, error)
+	GetWorkspace(ctx context.Context, id int) (entity.WorkspaceEntity, error)
+	ListUserWorkspaces(ctx context.Context, userID int) ([]entity.WorkspaceEntity, error)
+	IsMember(ctx context.Context, workspaceID, userID int) (bool, error)
+	ListMembers(ctx context.Context, workspaceID int) ([]entity.WorkspaceMemberEntity, error)
+	AddMember(ctx context.Context, workspaceID, userID int) error
+	RemoveMember(ctx context.Context, workspaceID, userID int) error
+}
+
+type WorkspaceService struct {
+	repo   WorkspaceRepository
+	users  UserRepository
+	logger *slog.Logger
+}
+
+func NewWorkspaceService(repo WorkspaceRepository, users UserRepository, logger *slog.Logger) *WorkspaceService {
+	return &WorkspaceService{
+		repo:   repo,
+		users:  users,
+		logger: logger,
+	}
+}
+
+// Resolve выбирает рабочее пространство запроса. Токен или ключ, выпущенный для
+// пространства, работает только в нём; иначе берётся запрошенное клиентом, а без
+// него — первое пространство пользователя. requested = 0 — клиент ничего не указал.
+func (s *WorkspaceService) Resolve(ctx context.Context, principal auth.Principal, requested int) (int, error) {
+	id := requested
+	switch {
+	case principal.WorkspaceID != 0:
+		if requested != 0 && requested != principal.WorkspaceID {
+			return 0, fmt.Errorf("%w: token is bound to workspace %d", policy.ErrForbidden, principal.WorkspaceID)
+		}
+		id = principal.WorkspaceID
+	case requested == 0:
+		workspaces, err := s.repo.ListUserWorkspaces(ctx, principal.UserID)
+		if err != nil {
+			return 0, err
+		}
+		if len(workspaces) == 0 {
+			return 0, repository.ErrNoWorkspace
+		}
+		id = workspaces[0].ID
+	}
+
+	if err := checkWorkspaceAccess(ctx, s.repo, principal, id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Create создаёт пространство; создатель становится его первым участником.
+func (s *WorkspaceService) Create(ctx context.Context, principal auth.Principal, name string) (*model.Workspace, error) {
+	name, err := workspaceName(name)
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.repo.CreateWorkspace(ctx, name, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return created.ToModel(), nil
+}
+
+func (s *WorkspaceService) List(ctx context.Context, principal auth.Principal) ([]model.Workspace, error) {
+	found, err := s.repo.ListUserWorkspaces(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	workspaces := make([]model.Workspace, 0, len(found))
+	for i := range found {
+		// Ключ, привязанный к пространству, не раскрывает остальные пространства владельца.
+		if principal.WorkspaceID != 0 && found[i].ID != principal.WorkspaceID {
+			continue
+		}
+		workspaces = append(workspaces, *found[i].ToModel())
+	}
+	return workspaces, nil
+}
+
+func (s *WorkspaceService) ListMembers(ctx context.Context, principal auth.Principal, workspaceID int) ([]model.WorkspaceMember, error) {
+	if err := s.checkAccess(ctx, principal, workspaceID); err != nil {
+		return nil, err
+	}
+	found, err := s.repo.ListMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]model.WorkspaceMember, len(found))
+	for i := range found {
+		members[i] = found[i].ToModel()
+	}
+	return members, nil
+}
+
+// AddMember добавляет пользователя в пространство; приглашать может любой участник.
+func (s *WorkspaceService) AddMember(ctx context.Context, principal auth.Principal, workspaceID, userID int) error {
+	if err := s.checkAccess(ctx, principal, workspaceID); err != nil {
+		return err
+	}
+	if _, err := s.users.GetUserByID(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.repo.AddMember(ctx, workspaceID, userID); err != nil {
+		return err
+	}
+	s.logger.Info("Workspace member added", "workspace_id", workspaceID, "user_id", userID, "by", principal.Actor())
+	return nil
+}
+
+func (s *WorkspaceService) RemoveMember(ctx context.Context, principal auth.Principal, workspaceID, userID int) error {
+	if err := s.checkAccess(ctx, principal, workspaceID); err != nil {
+		return err
+	}
+	if err := s.repo.RemoveMember(ctx, workspaceID, userID); err != nil {
+		return err
+	}
+	s.logger.Info("Workspace member removed", "workspace_id", workspaceID, "user_id", userID, "by", principal.Actor())
+	return nil
+}
+
+func (s *WorkspaceService) checkAccess(ctx context.Context, principal auth.Principal, workspaceID int) error {
+	if principal.WorkspaceID != 0 && principal.WorkspaceID != workspaceID {
+		return fmt.Errorf("%w: token is bound to workspace %d", policy.ErrForbidden, principal.WorkspaceID)
+	}
+	return checkWorkspaceAccess(ctx, s.repo, principal, workspaceID)
+}
+
+// checkWorkspaceAccess пускает в пространство его участников и администраторов.
+// Администратор видит любое пространство, чтобы разбирать обращения команд.
+func checkWorkspaceAccess(ctx context.Context, repo WorkspaceRepository, principal auth.Principal, workspaceID int) error {
+	if _, err := repo.GetWorkspace(ctx, workspaceID); err != nil {
+		return err
+	}
+	if principal.Role == model.RoleAdmin {
+		return nil
+	}
+	member, err := repo.IsMember(ctx, workspaceID, principal.UserID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return fmt.Errorf("%w: not a member of workspace %d", policy.ErrForbidden, workspaceID)
+	}
+	return nil
+}
+
+func workspaceName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return "", fmt.Errorf("%w: workspace name must be 1-100 characters", ErrValidation)
+	}
+	return name, nil
+}
+
+// personalWorkspaceName — название пространства, которое создаётся при регистрации.
+func personalWorkspaceName(user model.User) string {
+	if user.Name != "" {
+		return user.Name + "'s workspace"
+	}
+	local, _, _ := strings.Cut(user.Email, "@")
+	return local + "'s workspace"
+}
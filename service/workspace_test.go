@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"myApi/auth"
+	"myApi/model"
+	"myApi/policy"
+	"myApi/repository"
+	"testing"
+)
+
+func TestWorkspaceResolve(t *testing.T) {
+	a := newTestAuthService(t)
+	ctx := context.Background()
+	admin, adminWS := a.register(t, "admin@example.com")
+	member, memberWS := a.register(t, "member@example.com")
+	bound := member
+	bound.WorkspaceID = memberWS
+	homeless := member
+	homeless.UserID = 999
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		requested int
+		want      int
+		wantErr   error
+	}{
+		{name: "first own workspace", principal: member, want: memberWS},
+		{name: "requested own workspace", principal: member, requested: memberWS, want: memberWS},
+		{name: "requested foreign workspace", principal: member, requested: adminWS, wantErr: policy.ErrForbidden},
+		{name: "requested missing workspace", principal: member, requested: 999, wantErr: repository.ErrWorkspaceNotFound},
+		{name: "admin requests any workspace", principal: admin, requested: memberWS, want: memberWS},
+		{name: "bound principal", principal: bound, want: memberWS},
+		{name: "bound principal requests its workspace", principal: bound, requested: memberWS, want: memberWS},
+		{name: "bound principal requests another", principal: bound, requested: adminWS, wantErr: policy.ErrForbidden},
+		{name: "no workspaces", principal: homeless, wantErr: repository.ErrNoWorkspace},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.workspaces.Resolve(ctx, tt.principal, tt.requested)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve() = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkspaceMembers(t *testing.T) {
+	a := newTestAuthService(t)
+	ctx := context.Background()
+	a.register(t, "admin@example.com")
+	owner, _ := a.register(t, "owner@example.com")
+	guest, guestWS := a.register(t, "guest@example.com")
+
+	team, err := a.workspaces.Create(ctx, owner, "  Team  ")
+	if err != nil || team.Name != "Team" {
+		t.Fatalf("Create() = %+v, %v", team, err)
+	}
+	if _, err := a.workspaces.Create(ctx, owner, " "); !errors.Is(err, ErrValidation) {
+		t.Errorf("Create() with a blank name = %v, want ErrValidation", err)
+	}
+
+	// Пока гость не участник, он не видит пространство и не может приглашать.
+	if _, err := a.workspaces.ListMembers(ctx, guest, team.ID); !errors.Is(err, policy.ErrForbidden) {
+		t.Errorf("ListMembers() by a stranger = %v, want ErrForbidden", err)
+	}
+	if err := a.workspaces.AddMember(ctx, guest, team.ID, guest.UserID); !errors.Is(err, policy.ErrForbidden) {
+		t.Errorf("AddMember() by a stranger = %v, want ErrForbidden", err)
+	}
+	if err := a.workspaces.AddMember(ctx, owner, team.ID, 999); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("AddMember() of a missing user = %v, want ErrUserNotFound", err)
+	}
+
+	if err := a.workspaces.AddMember(ctx, owner, team.ID, guest.UserID); err != nil {
+		t.Fatalf("AddMember() = %v", err)
+	}
+	// Повторное приглашение ничего не меняет.
+	if err := a.workspaces.AddMember(ctx, owner, team.ID, guest.UserID); err != nil {
+		t.Fatalf("AddMember() again = %v", err)
+	}
+	members, err := a.workspaces.ListMembers(ctx, guest, team.ID)
+	if err != nil || len(members) != 2 || members[0].UserID != owner.UserID || members[1].UserID != guest.UserID {
+		t.Fatalf("ListMembers() = %+v, %v, want owner and guest", members, err)
+	}
+	list, err := a.workspaces.List(ctx, guest)
+	if err != nil || len(list) != 2 {
+		t.Errorf("List() = %+v, %v, want personal and team workspaces", list, err)
+	}
+
+	// Привязанный к личному пространству токен не видит остальные.
+	bound := guest
+	bound.WorkspaceID = guestWS
+	if list, err := a.workspaces.List(ctx, bound); err != nil || len(list) != 1 || list[0].ID != guestWS {
+		t.Errorf("List() with a bound token = %+v, %v", list, err)
+	}
+	if _, err := a.workspaces.ListMembers(ctx, bound, team.ID); !errors.Is(err, policy.ErrForbidden) {
+		t.Errorf("ListMembers() with a token bound elsewhere = %v, want ErrForbidden", err)
+	}
+
+	if err := a.workspaces.RemoveMember(ctx, owner, team.ID, guest.UserID); err != nil {
+		t.Fatalf("RemoveMember() = %v", err)
+	}
+	if err := a.workspaces.RemoveMember(ctx, owner, team.ID, guest.UserID); !errors.Is(err, repository.ErrUserNotFound) {
+		t.Errorf("RemoveMember() again = %v, want ErrUserNotFound", err)
+	}
+	if _, err := a.workspaces.Resolve(ctx, guest, team.ID); !errors.Is(err, policy.ErrForbidden) {
+		t.Errorf("Resolve() after removal = %v, want ErrForbidden", err)
+	}
+	// Исключённый участник больше не получает токен для пространства.
+	if _, err := a.auth.Login(ctx, "guest@example.com", "password123", team.ID); !errors.Is(err, policy.ErrForbidden) {
+		t.Errorf("Login() after removal = %v, want ErrForbidden", err)
+	}
+}
+
+func TestWorkspaceAccessFollowsRole(t *testing.T) {
+	a := newTestAuthService(t)
+	ctx := context.Background()
+	admin, adminWS := a.register(t, "admin@example.com")
+	member, _ := a.register(t, "member@example.com")
+
+	if _, err := a.workspaces.Resolve(ctx, member, adminWS); !errors.Is(err, policy.ErrForbidden) {
+		t.Fatalf("Resolve() by a member = %v, want ErrForbidden", err)
+	}
+	if _, err := a.auth.SetUserRole(ctx, admin, member.UserID, model.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	// Роль в principal берётся при аутентификации, поэтому повышаем её и здесь.
+	member.Role = model.RoleAdmin
+	if got, err := a.workspaces.Resolve(ctx, member, adminWS); err != nil || got != adminWS {
+		t.Errorf("Resolve() by a new admin = %d, %v, want %d", got, err, adminWS)
+	}
+}
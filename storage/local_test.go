@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestLocal(t *testing.T) *LocalStorage {
+	t.Helper()
+	s, err := NewLocalStorage(filepath.Join(t.TempDir(), "blobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// files — все файлы под root, включая временные.
+func files(t *testing.T, root string) []string {
+	t.Helper()
+	var out []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			out = append(out, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func blobInfo(body string) BlobInfo {
+	sum := sha256.Sum256([]byte(body))
+	return BlobInfo{Size: int64(len(body)), SHA256: hex.EncodeToString(sum[:])}
+}
+
+func TestLocalPath(t *testing.T) {
+	s := newTestLocal(t)
+	for _, key := range []string{"a", "attachments/1/file.txt", "a/./b"} {
+		path, err := s.path(key)
+		if err != nil {
+			t.Errorf("path(%q) = %v", key, err)
+			continue
+		}
+		if rel, err := filepath.Rel(s.root, path); err != nil || !filepath.IsLocal(rel) {
+			t.Errorf("path(%q) = %q is outside the root", key, path)
+		}
+	}
+	for _, key := range []string{"", "..", "../x", "a/../../x", "/etc/passwd", `a\b`, `..\x`} {
+		if _, err := s.path(key); err == nil {
+			t.Errorf("path(%q) accepted", key)
+		}
+	}
+}
+
+func TestLocalPutOpenDelete(t *testing.T) {
+	s := newTestLocal(t)
+	ctx := context.Background()
+	if err := s.Put(ctx, "a/b/c.txt", strings.NewReader("hello"), blobInfo("hello")); err != nil {
+		t.Fatalf("Put() = %v", err)
+	}
+	rc, err := s.Open(ctx, "a/b/c.txt")
+	if err != nil {
+		t.Fatalf("Open() = %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "hello" {
+		t.Errorf("Open() read %q", got)
+	}
+	if err := s.Delete(ctx, "a/b/c.txt"); err != nil {
+		t.Fatalf("Delete() = %v", err)
+	}
+	if _, err := s.Open(ctx, "a/b/c.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() after Delete() = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "a/b/c.txt"); err != nil {
+		t.Errorf("Delete() of a missing key = %v", err)
+	}
+}
+
+func TestLocalPutRejectsMismatchWithoutLeavingFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		info BlobInfo
+	}{
+		{"short body", "hell", blobInfo("hello")},
+		{"long body", "hello!", BlobInfo{Size: 5}},
+		{"checksum", "hallo", blobInfo("hello")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestLocal(t)
+			ctx := context.Background()
+			if err := s.Put(ctx, "dir/blob", strings.NewReader(tt.body), tt.info); err == nil {
+				t.Fatal("Put() accepted a mismatched body")
+			}
+			if left := files(t, s.root); len(left) != 0 {
+				t.Errorf("Put() left files behind: %v", left)
+			}
+			if _, err := s.Open(ctx, "dir/blob"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Open() = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestLocalPutMismatchKeepsPreviousBlob(t *testing.T) {
+	s := newTestLocal(t)
+	ctx := context.Background()
+	if err := s.Put(ctx, "blob", strings.NewReader("first"), blobInfo("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, "blob", strings.NewReader("broken"), blobInfo("second")); err == nil {
+		t.Fatal("Put() accepted a mismatched body")
+	}
+	rc, err := s.Open(ctx, "blob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if got, _ := io.ReadAll(rc); string(got) != "first" {
+		t.Errorf("blob = %q, want the previous content", got)
+	}
+	if left := files(t, s.root); len(left) != 1 {
+		t.Errorf("files = %v, want only the blob", left)
+	}
+}
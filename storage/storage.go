@@ -0,0 +1,29 @@
+// Package storage хранит содержимое вложений вне базы данных. Бэкенд выбирается
+// конфигурацией: локальный каталог или S3-совместимое хранилище.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobInfo описывает записываемое содержимое. SHA256 — хеш в hex; бэкенды, которые
+// умеют, сверяют с ним полученные байты.
+type BlobInfo struct {
+	Size        int64
+	ContentType string
+	SHA256      string
+}
+
+// Storage — хранилище неизменяемых blob'ов по ключу вида "a/b/c". Ключи выбирает
+// вызывающий; запись по существующему ключу заменяет содержимое.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, info BlobInfo) error
+	// Open возвращает ErrNotFound, если ключа нет.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete не считает ошибкой отсутствие ключа.
+	Delete(ctx context.Context, key string) error
+}